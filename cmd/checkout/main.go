@@ -7,7 +7,15 @@ import (
 
 	"go.opentelemetry.io/otel/plugin/grpctrace"
 
+	"github.com/italolelis/coffee-shop/internal/app/catalog"
 	"github.com/italolelis/coffee-shop/internal/app/http/rest"
+	"github.com/italolelis/coffee-shop/internal/app/order"
+	"github.com/italolelis/coffee-shop/internal/app/promotion"
+	"github.com/italolelis/coffee-shop/internal/app/storage/bolt"
+	"github.com/italolelis/coffee-shop/internal/app/storage/inmem"
+	"github.com/italolelis/coffee-shop/internal/app/storage/postgres"
+	"github.com/italolelis/coffee-shop/internal/app/tax"
+	"github.com/italolelis/coffee-shop/internal/pkg/idempotency"
 	"github.com/italolelis/coffee-shop/internal/pkg/log"
 	"github.com/italolelis/coffee-shop/internal/pkg/pb"
 	"github.com/italolelis/coffee-shop/internal/pkg/signal"
@@ -17,22 +25,51 @@ import (
 )
 
 type config struct {
-	LogLevel string `split_words:"true" default:"info"`
-	API      struct {
+	LogLevel  string `split_words:"true" default:"info"`
+	LogFormat string `split_words:"true" default:"json"`
+	API       struct {
 		Addr            string        `split_words:"true" default:"0.0.0.0:8080"`
 		ReadTimeout     time.Duration `split_words:"true" default:"30s"`
 		WriteTimeout    time.Duration `split_words:"true" default:"30s"`
 		IdleTimeout     time.Duration `split_words:"true" default:"5s"`
 		ShutdownTimeout time.Duration `split_words:"true" default:"5s"`
 	}
+	Idempotency struct {
+		Window time.Duration `split_words:"true" default:"24h"`
+	}
+	Promotions struct {
+		// TimeZone is where time windows, like happy hours, are evaluated
+		TimeZone string `split_words:"true" default:"UTC"`
+	}
+	Tax struct {
+		// Mode is "inclusive" when prices include the taxes, or "exclusive" when taxes are added to them
+		Mode string `split_words:"true" default:"inclusive"`
+		// Rates are percentages keyed by "store/category", e.g. "eat-in:19,take-away/drinks:7"
+		Rates map[string]string `split_words:"true"`
+	}
 	Payment struct {
 		Addr    string        `split_words:"true" required:"true"`
 		Timeout time.Duration `split_words:"true" default:"2s"`
 	}
+	Checkout struct {
+		// StaleAfter is how long a checkout saga can go without progress before it's
+		// resumed, it must be longer than a checkout can take
+		StaleAfter     time.Duration `split_words:"true" default:"2m"`
+		ResumeInterval time.Duration `split_words:"true" default:"1m"`
+	}
 	Tracing struct {
 		Addr        string `split_words:"true"`
 		ServiceName string `split_words:"true"`
 	}
+	Storage struct {
+		// Backend selects where orders are stored, either "inmem", "postgres" or "bolt"
+		Backend string `split_words:"true" default:"inmem"`
+		// Path is the database file used by the bolt backend
+		Path string `split_words:"true" default:"coffee-shop.db"`
+	}
+	Database struct {
+		DSN string `split_words:"true"`
+	}
 }
 
 func main() {
@@ -59,6 +96,10 @@ func run(ctx context.Context) error {
 	}
 
 	log.SetLevel(cfg.LogLevel)
+	if err := log.SetFormat(cfg.LogFormat); err != nil {
+		return err
+	}
+	logger = log.WithContext(ctx)
 
 	// =========================================================================
 	// Start Tracing Support
@@ -73,6 +114,80 @@ func run(ctx context.Context) error {
 	t := tp.Tracer("main")
 	ctx = tracing.NewContext(ctx, t)
 
+	// =========================================================================
+	// Start Storage
+	// =========================================================================
+	var (
+		or order.Reader
+		ow order.Writer
+		sr order.SagaReader
+		sw order.SagaWriter
+
+		idem idempotency.Store
+
+		pr promotion.Reader
+		pw promotion.Writer
+	)
+
+	switch cfg.Storage.Backend {
+	case "inmem":
+		logger.Info("using in memory storage")
+		orw := inmem.NewOrderReadWrite()
+		or, ow = orw, orw
+
+		srw := inmem.NewSagaReadWrite()
+		sr, sw = srw, srw
+
+		idem = inmem.NewIdempotencyStore(cfg.Idempotency.Window)
+
+		prw := inmem.NewPromotionReadWrite(promotion.DefaultPromotions()...)
+		pr, pw = prw, prw
+	case "postgres":
+		logger.Info("connecting to the database")
+		db, err := postgres.Open(ctx, cfg.Database.DSN)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		orw := postgres.NewOrderReadWrite(db)
+		or, ow = orw, orw
+
+		srw := postgres.NewSagaReadWrite(db)
+		sr, sw = srw, srw
+
+		idem = postgres.NewIdempotencyStore(db, cfg.Idempotency.Window)
+
+		prw := postgres.NewPromotionReadWrite(db)
+		if err := prw.Seed(ctx, promotion.DefaultPromotions()...); err != nil {
+			return err
+		}
+		pr, pw = prw, prw
+	case "bolt":
+		logger.Infow("opening database file", "path", cfg.Storage.Path)
+		db, err := bolt.Open(cfg.Storage.Path)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		orw := bolt.NewOrderReadWrite(db)
+		or, ow = orw, orw
+
+		srw := bolt.NewSagaReadWrite(db)
+		sr, sw = srw, srw
+
+		idem = bolt.NewIdempotencyStore(db, cfg.Idempotency.Window)
+
+		prw := bolt.NewPromotionReadWrite(db)
+		if err := prw.Seed(ctx, promotion.DefaultPromotions()...); err != nil {
+			return err
+		}
+		pr, pw = prw, prw
+	default:
+		return fmt.Errorf("storage backend %q not supported", cfg.Storage.Backend)
+	}
+
 	// Make a channel to listen for errors coming from the listener. Use a
 	// buffered channel so the goroutine can exit if we don't collect this error.
 	var serverErrors = make(chan error, 1)
@@ -95,6 +210,46 @@ func run(ctx context.Context) error {
 	}
 	defer paymentDiler.Close()
 
+	pc := pb.NewPaymentClient(paymentDiler)
+
+	// a checkout can't take longer than its request, sagas idle for longer were interrupted
+	if cfg.Checkout.StaleAfter <= cfg.API.WriteTimeout {
+		return fmt.Errorf("checkout stale after (%s) must be longer than the API write timeout (%s)",
+			cfg.Checkout.StaleAfter, cfg.API.WriteTimeout)
+	}
+
+	// settle the checkouts interrupted by the last shutdown before accepting new ones
+	co := order.NewCheckoutOrchestrator(ow, or, pc, sr, sw)
+	if err := co.Resume(ctx, cfg.Checkout.StaleAfter); err != nil {
+		return err
+	}
+
+	loc, err := time.LoadLocation(cfg.Promotions.TimeZone)
+	if err != nil {
+		return fmt.Errorf("failed to load the promotions time zone: %w", err)
+	}
+
+	rates, err := tax.ParseRates(cfg.Tax.Rates)
+	if err != nil {
+		return fmt.Errorf("failed to parse the tax rates: %w", err)
+	}
+
+	te, err := tax.NewEngine(tax.Mode(cfg.Tax.Mode), rates...)
+	if err != nil {
+		return fmt.Errorf("failed to create the tax engine: %w", err)
+	}
+
+	os := order.NewService(
+		ow,
+		or,
+		inmem.NewCatalogReadWrite(catalog.DefaultMenu()...),
+		pc,
+		idem,
+		co,
+		promotion.NewEngine(pr, pw, loc),
+		te,
+	)
+
 	s := rest.NewServer(
 		rest.Config{
 			Addr:         cfg.API.Addr,
@@ -102,13 +257,34 @@ func run(ctx context.Context) error {
 			WriteTimeout: cfg.API.WriteTimeout,
 			IdleTimeout:  cfg.API.IdleTimeout,
 		},
-		pb.NewPaymentClient(paymentDiler),
+		os,
 	)
 	go func() {
 		logger.Infow("Initializing REST support", "host", cfg.API.Addr)
 		serverErrors <- s.ListenAndServe(ctx)
 	}()
 
+	// =========================================================================
+	// Resume Checkouts
+	// =========================================================================
+	// sagas that were still running at startup, here or in another replica, are
+	// resumed once they go stale
+	go func() {
+		ticker := time.NewTicker(cfg.Checkout.ResumeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := co.Resume(ctx, cfg.Checkout.StaleAfter); err != nil {
+					logger.Errorw("failed to resume checkouts", "err", err)
+				}
+			}
+		}
+	}()
+
 	// =========================================================================
 	// Signal notifier
 	// =========================================================================
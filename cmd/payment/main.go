@@ -6,6 +6,10 @@ import (
 	"time"
 
 	"github.com/italolelis/coffee-shop/internal/app/http/grpc"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/app/storage/inmem"
+	"github.com/italolelis/coffee-shop/internal/app/storage/postgres"
+	"github.com/italolelis/coffee-shop/internal/pkg/idempotency"
 	"github.com/italolelis/coffee-shop/internal/pkg/log"
 	"github.com/italolelis/coffee-shop/internal/pkg/signal"
 	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
@@ -13,8 +17,9 @@ import (
 )
 
 type config struct {
-	LogLevel string `split_words:"true" default:"info"`
-	Web      struct {
+	LogLevel  string `split_words:"true" default:"info"`
+	LogFormat string `split_words:"true" default:"json"`
+	Web       struct {
 		Addr            string        `split_words:"true" default:"0.0.0.0:8081"`
 		ShutdownTimeout time.Duration `split_words:"true" default:"5s"`
 	}
@@ -22,6 +27,23 @@ type config struct {
 		Addr        string `split_words:"true"`
 		ServiceName string `split_words:"true"`
 	}
+	Database struct {
+		DSN string `split_words:"true"`
+	}
+	Idempotency struct {
+		Window time.Duration `split_words:"true" default:"24h"`
+	}
+	// Methods configures each payment method, e.g. METHODS_APPLE_PAY_ENABLED=false
+	Methods struct {
+		CreditCard payment.MethodConfig `split_words:"true"`
+		ApplePay   payment.MethodConfig `split_words:"true"`
+		GiftCard   payment.MethodConfig `split_words:"true"`
+		Cash       payment.MethodConfig `split_words:"true"`
+	}
+	Authorization struct {
+		// ExpiryInterval is how often expired authorizations are looked for
+		ExpiryInterval time.Duration `split_words:"true" default:"1m"`
+	}
 }
 
 func main() {
@@ -48,6 +70,10 @@ func run(ctx context.Context) error {
 	}
 
 	log.SetLevel(cfg.LogLevel)
+	if err := log.SetFormat(cfg.LogFormat); err != nil {
+		return err
+	}
+	logger = log.WithContext(ctx)
 
 	// =========================================================================
 	// Start Tracing Support
@@ -66,15 +92,106 @@ func run(ctx context.Context) error {
 	// buffered channel so the goroutine can exit if we don't collect this error.
 	var serverErrors = make(chan error, 1)
 
+	// =========================================================================
+	// Start Storage
+	// =========================================================================
+	var (
+		pr   payment.Reader
+		pw   payment.Writer
+		rr   payment.RefundReader
+		rw   payment.RefundWriter
+		gr   payment.GiftCardReader
+		gw   payment.GiftCardWriter
+		dr   payment.DrawerReader
+		dw   payment.DrawerWriter
+		idem idempotency.Store
+	)
+
+	if cfg.Database.DSN != "" {
+		logger.Info("connecting to the database")
+		db, err := postgres.Open(ctx, cfg.Database.DSN)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		prw := postgres.NewPaymentReadWrite(db)
+		pr, pw = prw, prw
+		rrw := postgres.NewRefundReadWrite(db)
+		rr, rw = rrw, rrw
+		grw := postgres.NewGiftCardReadWrite(db)
+		gr, gw = grw, grw
+		drw := postgres.NewDrawerReadWrite(db)
+		dr, dw = drw, drw
+		idem = postgres.NewIdempotencyStore(db, cfg.Idempotency.Window)
+	} else {
+		logger.Info("no database configured, using in memory storage")
+		prw := inmem.NewPaymentReadWrite()
+		pr, pw = prw, prw
+		rrw := inmem.NewRefundReadWrite(prw)
+		rr, rw = rrw, rrw
+		grw := inmem.NewGiftCardReadWrite()
+		gr, gw = grw, grw
+		drw := inmem.NewDrawerReadWrite()
+		dr, dw = drw, drw
+		idem = inmem.NewIdempotencyStore(cfg.Idempotency.Window)
+	}
+
+	// =========================================================================
+	// Register Payment Methods
+	// =========================================================================
+	methods := payment.NewRegistry()
+	if err := methods.Register("credit_card", cfg.Methods.CreditCard, payment.NewCreditCard); err != nil {
+		return err
+	}
+
+	if err := methods.Register("apple_pay", cfg.Methods.ApplePay, payment.NewApplePay); err != nil {
+		return err
+	}
+
+	if err := methods.Register("gift_card", cfg.Methods.GiftCard, payment.NewGiftCard(gw)); err != nil {
+		return err
+	}
+
+	if err := methods.Register("cash", cfg.Methods.Cash, payment.NewCash(dw)); err != nil {
+		return err
+	}
+
+	for _, m := range methods.List() {
+		logger.Infow("payment method registered", "method", m.Name, "enabled", m.Enabled)
+	}
+
 	// =========================================================================
 	// Start GRPC Service
 	// =========================================================================
-	s := grpc.NewServer(grpc.Config{Addr: cfg.Web.Addr}, tp.Tracer("main"))
+	s := grpc.NewServer(grpc.Config{Addr: cfg.Web.Addr}, tp.Tracer("main"), methods, pr, pw, rr, rw, gr, gw, dr, dw, idem)
 	go func() {
 		logger.Infow("Initializing GRPC support", "addr", cfg.Web.Addr)
 		serverErrors <- s.ListenAndServe(ctx)
 	}()
 
+	// =========================================================================
+	// Expire Authorizations
+	// =========================================================================
+	go func() {
+		ticker := time.NewTicker(cfg.Authorization.ExpiryInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				n, err := payment.ExpireAuthorizations(ctx, pr, pw, now)
+				if err != nil {
+					logger.Errorw("failed to expire authorizations", "err", err)
+				} else if n > 0 {
+					logger.Infow("authorizations expired", "count", n)
+				}
+			}
+		}
+	}()
+
 	// =========================================================================
 	// Signal notifier
 	// =========================================================================
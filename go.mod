@@ -3,15 +3,20 @@ module github.com/italolelis/coffee-shop
 go 1.14
 
 require (
+	github.com/DATA-DOG/go-sqlmock v1.4.1
 	github.com/go-chi/chi v4.1.1+incompatible
 	github.com/go-chi/render v1.0.1
 	github.com/golang/protobuf v1.4.1
 	github.com/google/uuid v1.1.1
+	github.com/jmoiron/sqlx v1.2.1-0.20190826204134-d7d95172beb5
 	github.com/kelseyhightower/envconfig v1.4.0
+	github.com/lib/pq v1.4.0
 	github.com/stretchr/testify v1.5.1
+	go.etcd.io/bbolt v1.3.5
 	go.opentelemetry.io/otel v0.4.3
 	go.opentelemetry.io/otel/exporters/trace/jaeger v0.4.3
 	go.uber.org/zap v1.15.0
+	google.golang.org/genproto v0.0.0-20191009194640-548a555dbc03
 	google.golang.org/grpc v1.29.1
 	google.golang.org/protobuf v1.22.0
 )
@@ -0,0 +1,173 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+)
+
+var (
+	ErrProductNotFound  = errors.New("product not found")
+	ErrSizeNotAvailable = errors.New("serving size not available")
+	// ErrModifierNotAvailable is returned when customizing a product with a modifier it doesn't offer
+	ErrModifierNotAvailable = errors.New("modifier not available")
+	// ErrConflictingModifiers is returned when more than one modifier of an exclusive group is chosen
+	ErrConflictingModifiers = errors.New("conflicting modifiers")
+)
+
+type Reader interface {
+	FetchByName(context.Context, string) (*Product, error)
+}
+
+type Writer interface {
+	Add(context.Context, *Product) error
+}
+
+type (
+	// Product is an item of the menu that can be ordered in one or more serving sizes
+	Product struct {
+		Name string `json:"name" db:"name"`
+		// Category groups products that are taxed alike, e.g. drinks or food
+		Category  string    `json:"category" db:"category"`
+		Sizes     Sizes     `json:"sizes" db:"sizes"`
+		Modifiers Modifiers `json:"modifiers" db:"modifiers"`
+	}
+
+	Sizes []*Size
+
+	Modifiers []*Modifier
+
+	// Modifier customizes a product, e.g. with oat milk or an extra shot, and is added
+	// to the price of the serving size. Only one modifier of an exclusive group, such
+	// as the milk, can be chosen, others like extra shots can be chosen several times.
+	Modifier struct {
+		Name      string      `json:"name" db:"name"`
+		Group     string      `json:"group" db:"group"`
+		Exclusive bool        `json:"exclusive" db:"exclusive"`
+		Price     money.Money `json:"price" db:"price"`
+	}
+
+	// Size is a serving size of a product and its price
+	Size struct {
+		Name  string      `json:"name" db:"name"`
+		Price money.Money `json:"price" db:"price"`
+	}
+)
+
+// NewProduct creates a new product with the given serving sizes
+func NewProduct(name string, sizes ...*Size) *Product {
+	return &Product{Name: name, Sizes: sizes}
+}
+
+// NewSize creates a new serving size
+func NewSize(name string, price money.Money) *Size {
+	return &Size{Name: name, Price: price}
+}
+
+// InCategory sets the category of the product
+func (p *Product) InCategory(category string) *Product {
+	p.Category = category
+	return p
+}
+
+// WithModifiers offers the given modifiers for the product
+func (p *Product) WithModifiers(modifiers ...*Modifier) *Product {
+	p.Modifiers = append(p.Modifiers, modifiers...)
+	return p
+}
+
+// Customize looks up the chosen modifiers, making sure the product offers them
+// and that no exclusive group is chosen twice
+func (p *Product) Customize(names ...string) (Modifiers, error) {
+	var (
+		modifiers = make(Modifiers, 0, len(names))
+		groups    = make(map[string]string, len(names))
+	)
+
+	for _, name := range names {
+		m, err := p.Modifier(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if chosen, ok := groups[m.Group]; ok && m.Exclusive {
+			return nil, fmt.Errorf("%w: %q and %q are both %s", ErrConflictingModifiers, chosen, m.Name, m.Group)
+		}
+
+		groups[m.Group] = m.Name
+		modifiers = append(modifiers, m)
+	}
+
+	return modifiers, nil
+}
+
+// Modifier returns the modifier of the product with the given name
+func (p *Product) Modifier(name string) (*Modifier, error) {
+	for _, m := range p.Modifiers {
+		if m.Name == name {
+			return m, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s with %q", ErrModifierNotAvailable, p.Name, name)
+}
+
+// Price returns the price of the product in the given serving size
+func (p *Product) Price(size string) (money.Money, error) {
+	for _, s := range p.Sizes {
+		if s.Name == size {
+			return s.Price, nil
+		}
+	}
+
+	return money.Money{}, fmt.Errorf("%w: %s in size %q", ErrSizeNotAvailable, p.Name, size)
+}
+
+// DefaultMenu returns the products served by default in the coffee shop
+func DefaultMenu() []*Product {
+	var (
+		coffee = []*Modifier{
+			{Name: "extra shot", Group: "shots", Price: money.New(50, "EUR")},
+			{Name: "decaf", Group: "decaf", Exclusive: true, Price: money.New(0, "EUR")},
+			{Name: "vanilla syrup", Group: "syrup", Price: money.New(40, "EUR")},
+			{Name: "caramel syrup", Group: "syrup", Price: money.New(40, "EUR")},
+			{Name: "hazelnut syrup", Group: "syrup", Price: money.New(40, "EUR")},
+			{Name: "iced", Group: "temperature", Exclusive: true, Price: money.New(0, "EUR")},
+			{Name: "extra hot", Group: "temperature", Exclusive: true, Price: money.New(0, "EUR")},
+		}
+		milk = []*Modifier{
+			{Name: "oat milk", Group: "milk", Exclusive: true, Price: money.New(40, "EUR")},
+			{Name: "soy milk", Group: "milk", Exclusive: true, Price: money.New(40, "EUR")},
+			{Name: "almond milk", Group: "milk", Exclusive: true, Price: money.New(40, "EUR")},
+			{Name: "lactose free milk", Group: "milk", Exclusive: true, Price: money.New(20, "EUR")},
+		}
+	)
+
+	return []*Product{
+		NewProduct("espresso",
+			NewSize("S", money.New(180, "EUR")),
+			NewSize("M", money.New(220, "EUR")),
+		).InCategory("drinks").WithModifiers(coffee...),
+		NewProduct("americano",
+			NewSize("S", money.New(200, "EUR")),
+			NewSize("M", money.New(240, "EUR")),
+			NewSize("L", money.New(280, "EUR")),
+		).InCategory("drinks").WithModifiers(coffee...),
+		NewProduct("cappuccino",
+			NewSize("S", money.New(220, "EUR")),
+			NewSize("M", money.New(240, "EUR")),
+			NewSize("L", money.New(260, "EUR")),
+		).InCategory("drinks").WithModifiers(coffee...).WithModifiers(milk...),
+		NewProduct("latte",
+			NewSize("S", money.New(240, "EUR")),
+			NewSize("M", money.New(260, "EUR")),
+			NewSize("L", money.New(290, "EUR")),
+		).InCategory("drinks").WithModifiers(coffee...).WithModifiers(milk...),
+		NewProduct("flat white",
+			NewSize("S", money.New(260, "EUR")),
+			NewSize("M", money.New(290, "EUR")),
+		).InCategory("drinks").WithModifiers(coffee...).WithModifiers(milk...),
+	}
+}
@@ -0,0 +1,60 @@
+package catalog
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProduct_Customize(t *testing.T) {
+	t.Parallel()
+
+	p := NewProduct("latte", NewSize("M", money.New(260, "EUR"))).WithModifiers(
+		&Modifier{Name: "extra shot", Group: "shots", Price: money.New(50, "EUR")},
+		&Modifier{Name: "oat milk", Group: "milk", Exclusive: true, Price: money.New(40, "EUR")},
+		&Modifier{Name: "soy milk", Group: "milk", Exclusive: true, Price: money.New(40, "EUR")},
+	)
+
+	tests := []struct {
+		name          string
+		modifiers     []string
+		expectedErr   error
+		expectedCount int
+	}{
+		{name: "no modifiers", expectedCount: 0},
+		{name: "repeated modifier", modifiers: []string{"extra shot", "extra shot", "oat milk"}, expectedCount: 3},
+		{name: "two of an exclusive group", modifiers: []string{"oat milk", "soy milk"}, expectedErr: ErrConflictingModifiers},
+		{name: "not offered", modifiers: []string{"caramel syrup"}, expectedErr: ErrModifierNotAvailable},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			modifiers, err := p.Customize(tt.modifiers...)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, modifiers, tt.expectedCount)
+		})
+	}
+}
+
+func TestProduct_Price(t *testing.T) {
+	t.Parallel()
+
+	p := NewProduct("latte", NewSize("S", money.New(240, "EUR")), NewSize("M", money.New(260, "EUR")))
+
+	price, err := p.Price("M")
+	require.NoError(t, err)
+	assert.Equal(t, money.New(260, "EUR"), price)
+
+	_, err = p.Price("XL")
+	assert.True(t, errors.Is(err, ErrSizeNotAvailable))
+}
@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/pkg/log"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/italolelis/coffee-shop/internal/pkg/pb"
+)
+
+// OpenDrawer starts a cash drawer session with the requested float
+func (h *PaymentHandler) OpenDrawer(ctx context.Context, r *pb.OpenDrawerRequest) (*pb.DrawerSession, error) {
+	d, err := payment.OpenDrawer(money.New(r.Float, r.Currency))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.dw.Open(ctx, d); err != nil {
+		return nil, err
+	}
+
+	log.WithContext(ctx).Named("payments").Infow("cash drawer opened", "session_id", d.ID, "float", d.Float.String())
+	return toDrawerResponse(d)
+}
+
+// CloseDrawer ends the open cash drawer session and reports how the counted
+// cash compares with the expected cash
+func (h *PaymentHandler) CloseDrawer(ctx context.Context, r *pb.CloseDrawerRequest) (*pb.DrawerSession, error) {
+	var d *payment.DrawerSession
+	if err := h.dw.UpdateOpen(ctx, func(s *payment.DrawerSession) error {
+		d = s
+		return s.Close(money.New(r.Counted, s.Float.Currency))
+	}); err != nil {
+		return nil, err
+	}
+
+	logger := log.WithContext(ctx).Named("payments").With("session_id", d.ID)
+	if diff := d.Difference(); !diff.IsZero() {
+		logger.Warnw("cash drawer closed with a difference", "expected", d.Expected().String(), "counted", d.Counted.String(), "difference", diff.String())
+	} else {
+		logger.Infow("cash drawer closed", "counted", d.Counted.String())
+	}
+
+	return toDrawerResponse(d)
+}
+
+// GetDrawerReport returns the reconciliation of a drawer session, or of the open session when no ID is given
+func (h *PaymentHandler) GetDrawerReport(ctx context.Context, r *pb.GetDrawerReportRequest) (*pb.DrawerSession, error) {
+	if r.ID == "" {
+		d, err := h.dr.FetchOpen(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return toDrawerResponse(d)
+	}
+
+	id, err := uuid.Parse(r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid drawer session ID: %v", payment.ErrInvalidRequest, err)
+	}
+
+	d, err := h.dr.FetchByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDrawerResponse(d)
+}
+
+func toDrawerResponse(d *payment.DrawerSession) (*pb.DrawerSession, error) {
+	openedAt, err := ptypes.TimestampProto(d.OpenedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert drawer opening date: %w", err)
+	}
+
+	resp := &pb.DrawerSession{
+		ID:       d.ID.String(),
+		Status:   string(d.Status),
+		Float:    d.Float.Amount,
+		Sales:    d.Sales.Amount,
+		PaidOut:  d.PaidOut.Amount,
+		Payments: int32(d.Payments),
+		Expected: d.Expected().Amount,
+		Currency: d.Float.Currency,
+		OpenedAt: openedAt,
+	}
+
+	if d.Status == payment.DrawerClosed {
+		resp.Counted = d.Counted.Amount
+		resp.Difference = d.Difference().Amount
+
+		if resp.ClosedAt, err = ptypes.TimestampProto(d.ClosedAt); err != nil {
+			return nil, fmt.Errorf("failed to convert drawer closing date: %w", err)
+		}
+	}
+
+	return resp, nil
+}
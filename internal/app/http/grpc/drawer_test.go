@@ -0,0 +1,65 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/app/storage/inmem"
+	"github.com/italolelis/coffee-shop/internal/pkg/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentHandler_CloseDrawer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	drawers := inmem.NewDrawerReadWrite()
+	methods := payment.NewRegistry()
+	require.NoError(t, methods.Register("cash", payment.MethodConfig{Enabled: true}, payment.NewCash(drawers)))
+
+	store := inmem.NewPaymentReadWrite()
+	h := &PaymentHandler{methods: methods, r: store, w: store, rw: inmem.NewRefundReadWrite(store), dr: drawers, dw: drawers}
+
+	opened, err := h.OpenDrawer(ctx, &pb.OpenDrawerRequest{Float: 10000, Currency: "EUR"})
+	require.NoError(t, err)
+
+	_, err = h.OpenDrawer(ctx, &pb.OpenDrawerRequest{Float: 10000, Currency: "EUR"})
+	assert.Equal(t, payment.ErrDrawerAlreadyOpen, err)
+
+	c, err := h.Pay(ctx, &pb.PaymentRequest{
+		OrderID: uuid.New().String(), Method: "cash", Amount: 780, Currency: "EUR", CashTendered: 1000,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), c.Tendered)
+	assert.Equal(t, int64(220), c.Change)
+
+	_, err = h.Refund(ctx, &pb.RefundRequest{ID: c.ID, Amount: 260, Currency: "EUR"})
+	require.NoError(t, err)
+
+	report, err := h.CloseDrawer(ctx, &pb.CloseDrawerRequest{Counted: 10500})
+	require.NoError(t, err)
+	assert.Equal(t, opened.ID, report.ID)
+	assert.Equal(t, string(payment.DrawerClosed), report.Status)
+	assert.Equal(t, int64(780), report.Sales)
+	assert.Equal(t, int64(260), report.PaidOut)
+	assert.Equal(t, int32(1), report.Payments)
+	assert.Equal(t, int64(10520), report.Expected)
+	assert.Equal(t, int64(-20), report.Difference)
+
+	// once closed the session is only found by its ID
+	_, err = h.GetDrawerReport(ctx, &pb.GetDrawerReportRequest{})
+	assert.Equal(t, payment.ErrDrawerClosed, err)
+
+	found, err := h.GetDrawerReport(ctx, &pb.GetDrawerReportRequest{ID: opened.ID})
+	require.NoError(t, err)
+	assert.Equal(t, report, found)
+
+	_, err = h.Pay(ctx, &pb.PaymentRequest{
+		OrderID: uuid.New().String(), Method: "cash", Amount: 780, Currency: "EUR", CashTendered: 1000,
+	})
+	assert.Error(t, err)
+}
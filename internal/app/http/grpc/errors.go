@@ -0,0 +1,96 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/pkg/idempotency"
+	"github.com/italolelis/coffee-shop/internal/pkg/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorInterceptor converts the errors returned by the handlers to gRPC statuses,
+// so clients get meaningful codes instead of codes.Unknown
+func errorInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return resp, nil
+}
+
+// toStatus maps domain errors to a gRPC status with error details
+func toStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var decline *payment.DeclineError
+
+	switch {
+	case errors.As(err, &decline):
+		return withDetails(codes.FailedPrecondition, err, declined(decline.Reason))
+	case errors.Is(err, payment.ErrDeclined):
+		return withDetails(codes.FailedPrecondition, err, declined(""))
+	case errors.Is(err, payment.ErrMethodNotSupported):
+		return withDetails(codes.InvalidArgument, err, badRequest("Method", err))
+	case errors.Is(err, payment.ErrInvalidRequest):
+		return withDetails(codes.InvalidArgument, err, badRequest("PaymentRequest", err))
+	case errors.Is(err, payment.ErrNotVoidable), errors.Is(err, payment.ErrNotRefundable),
+		errors.Is(err, payment.ErrRefundExceedsPayment), errors.Is(err, payment.ErrNotCapturable),
+		errors.Is(err, payment.ErrNotReleasable), errors.Is(err, payment.ErrAuthorizationExpired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, payment.ErrInvalidRefund):
+		return withDetails(codes.InvalidArgument, err, badRequest("Amount", err))
+	case errors.Is(err, payment.ErrConfirmationNotFound), errors.Is(err, payment.ErrGiftCardNotFound),
+		errors.Is(err, payment.ErrDrawerNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, payment.ErrInvalidGiftCardAmount), errors.Is(err, payment.ErrInvalidCashAmount):
+		return withDetails(codes.InvalidArgument, err, badRequest("Amount", err))
+	case errors.Is(err, payment.ErrInsufficientBalance):
+		return withDetails(codes.FailedPrecondition, err, declined(err.Error()))
+	case errors.Is(err, payment.ErrDrawerClosed), errors.Is(err, payment.ErrDrawerAlreadyOpen):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, idempotency.ErrInProgress):
+		return withDetails(codes.Aborted, err, &errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(time.Second)})
+	case errors.Is(err, idempotency.ErrKeyReused):
+		return withDetails(codes.AlreadyExists, err, badRequest("IdempotencyKey", err))
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func declined(reason string) proto.Message {
+	return &errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{
+		{Type: pb.ViolationDeclined, Subject: "payment", Description: reason},
+	}}
+}
+
+func badRequest(field string, err error) proto.Message {
+	return &errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+		{Field: field, Description: err.Error()},
+	}}
+}
+
+// withDetails creates a status error with the given details, falling back to
+// a plain status if the details can't be attached
+func withDetails(code codes.Code, err error, details ...proto.Message) error {
+	st, derr := status.New(code, err.Error()).WithDetails(details...)
+	if derr != nil {
+		return status.Error(code, err.Error())
+	}
+
+	return st.Err()
+}
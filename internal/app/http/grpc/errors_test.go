@@ -0,0 +1,133 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/app/storage/inmem"
+	"github.com/italolelis/coffee-shop/internal/pkg/idempotency"
+	"github.com/italolelis/coffee-shop/internal/pkg/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode codes.Code
+	}{
+		{name: "declined", err: &payment.DeclineError{Reason: "insufficient funds"}, expectedCode: codes.FailedPrecondition},
+		{name: "method not supported", err: fmt.Errorf("%w: %q", payment.ErrMethodNotSupported, "paypal"), expectedCode: codes.InvalidArgument},
+		{name: "invalid request", err: fmt.Errorf("%w: order id is required", payment.ErrInvalidRequest), expectedCode: codes.InvalidArgument},
+		{name: "confirmation not found", err: payment.ErrConfirmationNotFound, expectedCode: codes.NotFound},
+		{name: "gift card not found", err: payment.ErrGiftCardNotFound, expectedCode: codes.NotFound},
+		{name: "invalid gift card amount", err: payment.ErrInvalidGiftCardAmount, expectedCode: codes.InvalidArgument},
+		{name: "drawer closed", err: payment.ErrDrawerClosed, expectedCode: codes.FailedPrecondition},
+		{name: "drawer session not found", err: payment.ErrDrawerNotFound, expectedCode: codes.NotFound},
+		{name: "invalid cash amount", err: payment.ErrInvalidCashAmount, expectedCode: codes.InvalidArgument},
+		{name: "idempotency key in progress", err: idempotency.ErrInProgress, expectedCode: codes.Aborted},
+		{name: "unexpected", err: errors.New("disk full"), expectedCode: codes.Internal},
+		{name: "already a status", err: status.Error(codes.Unavailable, "down"), expectedCode: codes.Unavailable},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			st, ok := status.FromError(toStatus(tt.err))
+			require.True(t, ok)
+			assert.Equal(t, tt.expectedCode, st.Code())
+		})
+	}
+}
+
+func TestToStatus_DeclineReason(t *testing.T) {
+	t.Parallel()
+
+	st, ok := status.FromError(toStatus(&payment.DeclineError{Reason: "insufficient funds"}))
+	require.True(t, ok)
+	require.Len(t, st.Details(), 1)
+
+	pf, ok := st.Details()[0].(*errdetails.PreconditionFailure)
+	require.True(t, ok)
+	assert.Equal(t, pb.ViolationDeclined, pf.Violations[0].Type)
+	assert.Equal(t, "insufficient funds", pf.Violations[0].Description)
+}
+
+func TestPaymentHandler_MalformedIDs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	methods := payment.NewRegistry()
+	require.NoError(t, methods.Register("credit_card", payment.MethodConfig{Enabled: true}, payment.NewCreditCard))
+
+	store := inmem.NewPaymentReadWrite()
+	h := &PaymentHandler{methods: methods, r: store, w: store}
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{
+			name: "pay with a malformed order id",
+			call: func() error {
+				_, err := h.Pay(ctx, &pb.PaymentRequest{OrderID: "not-a-uuid", Method: "credit_card", Amount: 780, Currency: "EUR"})
+				return err
+			},
+		},
+		{
+			name: "fetch a malformed confirmation id",
+			call: func() error {
+				_, err := h.GetConfirmation(ctx, &pb.GetConfirmationRequest{ID: "not-a-uuid"})
+				return err
+			},
+		},
+		{
+			name: "fetch by a malformed order id",
+			call: func() error {
+				_, err := h.GetConfirmation(ctx, &pb.GetConfirmationRequest{OrderID: "not-a-uuid"})
+				return err
+			},
+		},
+		{
+			name: "void a malformed order id",
+			call: func() error {
+				_, err := h.Void(ctx, &pb.VoidRequest{OrderID: "not-a-uuid"})
+				return err
+			},
+		},
+		{
+			name: "fetch without ids",
+			call: func() error {
+				_, err := h.GetConfirmation(ctx, &pb.GetConfirmationRequest{})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.call()
+			assert.True(t, errors.Is(err, payment.ErrInvalidRequest))
+
+			st, ok := status.FromError(toStatus(err))
+			require.True(t, ok)
+			assert.Equal(t, codes.InvalidArgument, st.Code())
+			require.Len(t, st.Details(), 1)
+			_, ok = st.Details()[0].(*errdetails.BadRequest)
+			assert.True(t, ok)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/pkg/log"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/italolelis/coffee-shop/internal/pkg/pb"
+)
+
+// IssueGiftCard creates a gift card loaded with the requested amount
+func (h *PaymentHandler) IssueGiftCard(ctx context.Context, r *pb.IssueGiftCardRequest) (*pb.GiftCard, error) {
+	card, err := payment.IssueGiftCard(money.New(r.Amount, r.Currency))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.gw.Add(ctx, card); err != nil {
+		return nil, fmt.Errorf("failed to save gift card: %w", err)
+	}
+
+	log.WithContext(ctx).Named("payments").Infow("gift card issued", "balance", card.Balance.String())
+	return toGiftCardResponse(card)
+}
+
+// TopUpGiftCard adds the requested amount to the balance of a gift card
+func (h *PaymentHandler) TopUpGiftCard(ctx context.Context, r *pb.TopUpGiftCardRequest) (*pb.GiftCard, error) {
+	var card *payment.GiftCardAccount
+	if err := h.gw.Update(ctx, r.Number, func(c *payment.GiftCardAccount) error {
+		card = c
+		return c.TopUp(money.New(r.Amount, r.Currency))
+	}); err != nil {
+		return nil, err
+	}
+
+	return toGiftCardResponse(card)
+}
+
+// GetGiftCardBalance returns what is left on a gift card
+func (h *PaymentHandler) GetGiftCardBalance(ctx context.Context, r *pb.GetGiftCardBalanceRequest) (*pb.GiftCard, error) {
+	card, err := h.gr.FetchByNumber(ctx, r.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	return toGiftCardResponse(card)
+}
+
+func toGiftCardResponse(card *payment.GiftCardAccount) (*pb.GiftCard, error) {
+	issuedAt, err := ptypes.TimestampProto(card.IssuedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert gift card issue date: %w", err)
+	}
+
+	return &pb.GiftCard{
+		Number:   card.Number,
+		Balance:  card.Balance.Amount,
+		Currency: card.Balance.Currency,
+		IssuedAt: issuedAt,
+	}, nil
+}
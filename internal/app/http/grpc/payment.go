@@ -2,40 +2,672 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/google/uuid"
 	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/pkg/idempotency"
 	"github.com/italolelis/coffee-shop/internal/pkg/log"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
 	"github.com/italolelis/coffee-shop/internal/pkg/pb"
 )
 
-type PaymentHandler struct{}
+type PaymentHandler struct {
+	methods *payment.Registry
+	r       payment.Reader
+	w       payment.Writer
+	rr      payment.RefundReader
+	rw      payment.RefundWriter
+	gr      payment.GiftCardReader
+	gw      payment.GiftCardWriter
+	dr      payment.DrawerReader
+	dw      payment.DrawerWriter
+	idem    idempotency.Store
+}
+
+// processFunc charges or authorizes an order request with a payment method
+type processFunc func(payment.Method, context.Context, payment.OrderRequest) (*payment.Confirmation, error)
+
+// tender is the part of the order paid with a single payment method
+type tender struct {
+	method payment.Method
+	req    payment.OrderRequest
+}
 
+// Pay charges the order. Requests with an idempotency key are processed only once,
+// repeated keys replay the original confirmation.
 func (h *PaymentHandler) Pay(ctx context.Context, r *pb.PaymentRequest) (*pb.PaymentConfirmation, error) {
+	if len(r.Tenders) > 0 {
+		return nil, fmt.Errorf("%w: split payments are made with PaySplit", payment.ErrInvalidRequest)
+	}
+
+	resp, err := h.idempotent(ctx, "pay", r, payment.Method.Process)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Payments[0], nil
+}
+
+// Authorize holds the order total, which is charged later with Capture. Requests
+// with an idempotency key are processed only once, like in Pay.
+func (h *PaymentHandler) Authorize(ctx context.Context, r *pb.PaymentRequest) (*pb.PaymentConfirmation, error) {
+	if len(r.Tenders) > 0 {
+		return nil, fmt.Errorf("%w: split payments are authorized with AuthorizeSplit", payment.ErrInvalidRequest)
+	}
+
+	resp, err := h.idempotent(ctx, "authorize", r, payment.Method.Authorize)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Payments[0], nil
+}
+
+// PaySplit charges the order with several payment methods. Either every tender is
+// charged or none is, tenders charged before a failing one are voided.
+func (h *PaymentHandler) PaySplit(ctx context.Context, r *pb.PaymentRequest) (*pb.SplitConfirmation, error) {
+	if len(r.Tenders) == 0 {
+		return nil, fmt.Errorf("%w: split payments need at least one tender", payment.ErrInvalidRequest)
+	}
+
+	return h.idempotent(ctx, "pay_split", r, payment.Method.Process)
+}
+
+// AuthorizeSplit holds the order total with several payment methods, all or nothing like in PaySplit
+func (h *PaymentHandler) AuthorizeSplit(ctx context.Context, r *pb.PaymentRequest) (*pb.SplitConfirmation, error) {
+	if len(r.Tenders) == 0 {
+		return nil, fmt.Errorf("%w: split payments need at least one tender", payment.ErrInvalidRequest)
+	}
+
+	return h.idempotent(ctx, "authorize_split", r, payment.Method.Authorize)
+}
+
+// idempotent runs the payment request once per idempotency key, replaying the
+// original confirmations for repeated keys
+func (h *PaymentHandler) idempotent(ctx context.Context, action string, r *pb.PaymentRequest, fn processFunc) (*pb.SplitConfirmation, error) {
+	if r.IdempotencyKey == "" {
+		return h.pay(ctx, action, r, fn)
+	}
+
+	logger := log.WithContext(ctx).
+		Named("payments").
+		With("action", action).
+		With("order_id", r.OrderID).
+		With("idempotency_key", r.IdempotencyKey)
+
+	rec, err := h.idem.Reserve(ctx, r.IdempotencyKey, fingerprint(r))
+	if err != nil {
+		return nil, err
+	}
+
+	if rec != nil {
+		logger.Debug("replaying payment confirmation")
+		return h.replay(ctx, rec.Response)
+	}
+
+	resp, err := h.pay(ctx, action, r, fn)
+	if err != nil {
+		if rerr := h.idem.Release(ctx, r.IdempotencyKey); rerr != nil {
+			logger.Errorw("failed to release idempotency key", "err", rerr)
+		}
+
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(resp.Payments))
+	for _, p := range resp.Payments {
+		ids = append(ids, p.ID)
+	}
+
+	// the payment already happened, so failing to store the key must not fail the request
+	if err := h.idem.Complete(ctx, r.IdempotencyKey, []byte(strings.Join(ids, ","))); err != nil {
+		logger.Errorw("failed to complete idempotency key", "err", err)
+	}
+
+	return resp, nil
+}
+
+// replay loads the confirmations stored for an idempotency key, which is the
+// comma separated list of their ids
+func (h *PaymentHandler) replay(ctx context.Context, stored []byte) (*pb.SplitConfirmation, error) {
+	resp := &pb.SplitConfirmation{}
+	for _, raw := range strings.Split(string(stored), ",") {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse stored confirmation id: %w", err)
+		}
+
+		c, err := h.r.FetchByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		conf, err := toConfirmationResponse(c)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Payments = append(resp.Payments, conf)
+	}
+
+	return resp, nil
+}
+
+// fingerprint hashes the parts of the payment request that must match when its idempotency key is reused
+func fingerprint(r *pb.PaymentRequest) string {
+	parts := []string{
+		r.OrderID, r.Method, strconv.FormatInt(r.Amount, 10), r.Currency, r.GiftCardNumber,
+		strconv.FormatInt(r.CashTendered, 10), strconv.FormatInt(r.Tip, 10), r.Barista,
+	}
+	for _, t := range r.Tenders {
+		parts = append(parts, t.Method, strconv.FormatInt(t.Amount, 10), t.GiftCardNumber, strconv.FormatInt(t.CashTendered, 10))
+	}
+
+	return idempotency.Fingerprint(parts...)
+}
+
+// pay processes every tender of the request in order. When a tender fails, the ones
+// already processed are voided, so the customer pays all or nothing.
+func (h *PaymentHandler) pay(ctx context.Context, action string, r *pb.PaymentRequest, fn processFunc) (*pb.SplitConfirmation, error) {
 	logger := log.WithContext(ctx).
 		Named("payments").
-		With("action", "pay").
+		With("action", action).
 		With("order_id", r.OrderID)
 
-	m, err := payment.NewMethodFactory(r.Method)
+	tenders, err := h.tenders(ctx, r)
 	if err != nil {
 		return nil, err
 	}
 
+	confirmations := make([]*payment.Confirmation, 0, len(tenders))
+	for _, t := range tenders {
+		logger.Debugw("processing payment", "amount", t.req.Total.String())
+		c, err := fn(t.method, ctx, t.req)
+		if err != nil {
+			h.rollback(ctx, tenders, confirmations)
+			return nil, fmt.Errorf("failed to process payment: %w", err)
+		}
+
+		// the tender went through, so it's voided along with the others if saving fails
+		confirmations = append(confirmations, c)
+
+		if err := h.w.Add(ctx, c); err != nil {
+			h.rollback(ctx, tenders, confirmations)
+			return nil, fmt.Errorf("failed to save payment confirmation: %w", err)
+		}
+	}
+
+	resp := &pb.SplitConfirmation{Payments: make([]*pb.PaymentConfirmation, 0, len(confirmations))}
+	for _, c := range confirmations {
+		conf, err := toConfirmationResponse(c)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Payments = append(resp.Payments, conf)
+	}
+
+	logger.Debugw("payment processed", "tenders", len(confirmations))
+	return resp, nil
+}
+
+// tenders validates the request and splits it into the tenders to process. Requests
+// without tenders are paid in full with their own method, the tip of split payments
+// is charged to the last tender.
+func (h *PaymentHandler) tenders(ctx context.Context, r *pb.PaymentRequest) ([]tender, error) {
 	orderID, err := uuid.Parse(r.OrderID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse order id: %w", err)
+		return nil, fmt.Errorf("%w: invalid order ID: %v", payment.ErrInvalidRequest, err)
+	}
+
+	shiftID, err := h.shift(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := payment.OrderRequest{
+		OrderID:        orderID,
+		Total:          money.New(r.Amount+r.Tip, r.Currency),
+		Items:          make([]payment.LineItem, 0, len(r.Items)),
+		GiftCardNumber: r.GiftCardNumber,
+		CashTendered:   money.New(r.CashTendered, r.Currency),
+		Discount:       money.New(r.Discount, r.Currency),
+		Tax:            money.New(r.Tax, r.Currency),
+		Tip:            money.New(r.Tip, r.Currency),
+		Barista:        r.Barista,
+		ShiftID:        shiftID,
+	}
+	for _, i := range r.Items {
+		req.Items = append(req.Items, payment.LineItem{
+			Name:        i.Name,
+			ServingSize: i.ServingSize,
+			Qty:         int(i.Qty),
+			UnitPrice:   money.New(i.UnitAmount, r.Currency),
+			Modifiers:   i.Modifiers,
+		})
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if len(r.Tenders) == 0 {
+		m, err := h.methods.Method(r.Method)
+		if err != nil {
+			return nil, err
+		}
+
+		return []tender{{method: m, req: req}}, nil
+	}
+
+	var (
+		tenders = make([]tender, 0, len(r.Tenders))
+		sum     = money.New(0, r.Currency)
+	)
+
+	for idx, t := range r.Tenders {
+		m, err := h.methods.Method(t.Method)
+		if err != nil {
+			return nil, err
+		}
+
+		amount := money.New(t.Amount, r.Currency)
+		if amount.IsZero() || amount.IsNegative() {
+			return nil, fmt.Errorf("%w: amount of tender %d must be positive, got %s", payment.ErrInvalidRequest, idx, amount)
+		}
+
+		sum.Amount += amount.Amount
+		tenders = append(tenders, tender{method: m, req: payment.OrderRequest{
+			OrderID:        orderID,
+			Total:          amount,
+			Barista:        r.Barista,
+			ShiftID:        shiftID,
+			GiftCardNumber: t.GiftCardNumber,
+			CashTendered:   money.New(t.CashTendered, r.Currency),
+		}})
+	}
+
+	if sum != req.Total {
+		return nil, fmt.Errorf("%w: tenders add up to %s, but the order total plus the tip is %s", payment.ErrInvalidRequest, sum, req.Total)
+	}
+
+	if !req.Tip.IsZero() {
+		last := &tenders[len(tenders)-1].req
+		if last.Total.Amount <= req.Tip.Amount {
+			return nil, fmt.Errorf("%w: the last tender must cover the tip of %s, got %s", payment.ErrInvalidRequest, req.Tip, last.Total)
+		}
+
+		last.Tip = req.Tip
+	}
+
+	return tenders, nil
+}
+
+// shift returns the cash drawer session that is open while a tipped payment is made,
+// tips given while no drawer is open aren't tied to a shift
+func (h *PaymentHandler) shift(ctx context.Context, r *pb.PaymentRequest) (uuid.UUID, error) {
+	if r.Tip <= 0 {
+		return uuid.Nil, nil
+	}
+
+	d, err := h.dr.FetchOpen(ctx)
+	switch {
+	case errors.Is(err, payment.ErrDrawerClosed):
+		return uuid.Nil, nil
+	case err != nil:
+		return uuid.Nil, fmt.Errorf("failed to fetch the open cash drawer: %w", err)
+	}
+
+	return d.ID, nil
+}
+
+// rollback voids the tenders processed before one failed. Tenders that can't be
+// voided are logged, as the original failure is what the client needs to see.
+func (h *PaymentHandler) rollback(ctx context.Context, tenders []tender, confirmations []*payment.Confirmation) {
+	logger := log.WithContext(ctx).Named("payments").With("action", "rollback")
+
+	for i := len(confirmations) - 1; i >= 0; i-- {
+		c := confirmations[i]
+		if err := tenders[i].method.Void(ctx, c); err != nil {
+			logger.Errorw("failed to void tender", "payment_id", c.ID, "method", c.Method, "err", err)
+			continue
+		}
+
+		if err := h.w.Add(ctx, c); err != nil {
+			logger.Errorw("failed to save voided tender", "payment_id", c.ID, "err", err)
+		}
+	}
+}
+
+// Capture charges an authorized payment. Capturing twice is not an error, so the
+// checkout can retry failed pickups.
+func (h *PaymentHandler) Capture(ctx context.Context, r *pb.CaptureRequest) (*pb.PaymentConfirmation, error) {
+	return h.settle(ctx, "capture", r.ID, r.OrderID, "", payment.Method.Capture)
+}
+
+// ReleaseAuthorization gives back the funds held by an authorization without charging them
+func (h *PaymentHandler) ReleaseAuthorization(ctx context.Context, r *pb.ReleaseRequest) (*pb.PaymentConfirmation, error) {
+	return h.settle(ctx, "release_authorization", r.ID, r.OrderID, r.Reason, payment.Method.ReleaseAuthorization)
+}
+
+// settle applies a change of status to an existing payment through its payment method
+func (h *PaymentHandler) settle(ctx context.Context, action, id, orderID, reason string, fn func(payment.Method, context.Context, *payment.Confirmation) error) (*pb.PaymentConfirmation, error) {
+	logger := log.WithContext(ctx).
+		Named("payments").
+		With("action", action).
+		With("payment_id", id).
+		With("order_id", orderID)
+
+	c, err := h.fetchConfirmation(ctx, id, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := h.methods.Settler(c.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fn(m, ctx, c); err != nil {
+		// expired authorizations are saved as such, even though the capture failed
+		if errors.Is(err, payment.ErrAuthorizationExpired) {
+			if werr := h.w.Add(ctx, c); werr != nil {
+				logger.Errorw("failed to save expired authorization", "err", werr)
+			}
+		}
+
+		return nil, err
+	}
+
+	if err := h.w.Add(ctx, c); err != nil {
+		return nil, fmt.Errorf("failed to save payment confirmation: %w", err)
+	}
+
+	logger.Infow("payment settled", "status", c.Status, "reason", reason)
+	return toConfirmationResponse(c)
+}
+
+func (h *PaymentHandler) GetConfirmation(ctx context.Context, r *pb.GetConfirmationRequest) (*pb.PaymentConfirmation, error) {
+	c, err := h.fetchConfirmation(ctx, r.ID, r.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toConfirmationResponse(c)
+}
+
+// Void cancels a payment. It's used by the checkout to compensate payments of
+// orders that couldn't be completed, so voiding twice is not an error.
+func (h *PaymentHandler) Void(ctx context.Context, r *pb.VoidRequest) (*pb.PaymentConfirmation, error) {
+	if r.ID == "" && r.OrderID != "" {
+		return h.voidOrder(ctx, r.OrderID, r.Reason)
+	}
+
+	return h.settle(ctx, "void", r.ID, r.OrderID, r.Reason, payment.Method.Void)
+}
+
+// voidOrder voids every payment of the order that wasn't settled otherwise, e.g. all
+// tenders of a split payment, and returns the latest one
+func (h *PaymentHandler) voidOrder(ctx context.Context, orderID, reason string) (*pb.PaymentConfirmation, error) {
+	logger := log.WithContext(ctx).
+		Named("payments").
+		With("action", "void").
+		With("order_id", orderID)
+
+	oid, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid order ID: %v", payment.ErrInvalidRequest, err)
+	}
+
+	confirmations, err := h.r.FetchAllByOrderID(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(confirmations) == 0 {
+		return nil, payment.ErrConfirmationNotFound
+	}
+
+	for _, c := range confirmations {
+		if c.Status != payment.StatusCaptured && c.Status != payment.StatusAuthorized {
+			continue
+		}
+
+		m, err := h.methods.Settler(c.Method)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := m.Void(ctx, c); err != nil {
+			return nil, err
+		}
+
+		if err := h.w.Add(ctx, c); err != nil {
+			return nil, fmt.Errorf("failed to save payment confirmation: %w", err)
+		}
+
+		logger.Infow("payment settled", "payment_id", c.ID, "status", c.Status, "reason", reason)
+	}
+
+	return toConfirmationResponse(confirmations[len(confirmations)-1])
+}
+
+// Refund gives back part or all of a payment and records the refund. Requests with an
+// idempotency key are processed only once, repeated keys replay the original refund.
+func (h *PaymentHandler) Refund(ctx context.Context, r *pb.RefundRequest) (*pb.RefundConfirmation, error) {
+	if r.IdempotencyKey == "" {
+		return h.refund(ctx, r)
+	}
+
+	logger := log.WithContext(ctx).
+		Named("payments").
+		With("action", "refund").
+		With("payment_id", r.ID).
+		With("order_id", r.OrderID).
+		With("idempotency_key", r.IdempotencyKey)
+
+	rec, err := h.idem.Reserve(ctx, r.IdempotencyKey, refundFingerprint(r))
+	if err != nil {
+		return nil, err
 	}
 
-	logger.Debug("processing payment")
-	c, err := m.Process(payment.OrderRequest{OrderID: orderID})
+	if rec != nil {
+		logger.Debug("replaying refund")
+		return h.replayRefund(ctx, rec.Response)
+	}
+
+	resp, err := h.refund(ctx, r)
 	if err != nil {
-		return nil, fmt.Errorf("failed to process payment: %w", err)
+		if rerr := h.idem.Release(ctx, r.IdempotencyKey); rerr != nil {
+			logger.Errorw("failed to release idempotency key", "err", rerr)
+		}
+
+		return nil, err
+	}
+
+	// the refund already happened, so failing to store the key must not fail the request
+	if err := h.idem.Complete(ctx, r.IdempotencyKey, []byte(resp.PaymentID+","+resp.ID)); err != nil {
+		logger.Errorw("failed to complete idempotency key", "err", err)
+	}
+
+	return resp, nil
+}
+
+// refund applies the refund to the payment while the payment is locked, so concurrent
+// refunds can't both pass the check of what is left to refund
+func (h *PaymentHandler) refund(ctx context.Context, r *pb.RefundRequest) (*pb.RefundConfirmation, error) {
+	logger := log.WithContext(ctx).
+		Named("payments").
+		With("action", "refund").
+		With("payment_id", r.ID).
+		With("order_id", r.OrderID)
+
+	c, err := h.fetchConfirmation(ctx, r.ID, r.OrderID)
+	if err != nil {
+		return nil, err
 	}
 
-	// save confirmation in storage
+	m, err := h.methods.Settler(c.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	req := payment.RefundRequest{Reason: r.Reason}
+	if r.Amount != 0 {
+		req.Amount = money.New(r.Amount, r.Currency)
+	}
+
+	c, rf, err := h.rw.Refund(ctx, c.ID, func(c *payment.Confirmation) (*payment.Refund, error) {
+		return m.Refund(ctx, c, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infow("payment refunded", "amount", rf.Amount.String(), "reason", rf.Reason)
+	return toRefundResponse(rf, c)
+}
+
+// replayRefund loads the refund stored for an idempotency key, which is the id of the
+// payment and the id of the refund separated by a comma
+func (h *PaymentHandler) replayRefund(ctx context.Context, stored []byte) (*pb.RefundConfirmation, error) {
+	parts := strings.SplitN(string(stored), ",", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("failed to parse stored refund: %q", stored)
+	}
+
+	paymentID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse stored payment id: %w", err)
+	}
+
+	refundID, err := uuid.Parse(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse stored refund id: %w", err)
+	}
+
+	c, err := h.r.FetchByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	refunds, err := h.rr.FetchByPaymentID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rf := range refunds {
+		if rf.ID == refundID {
+			return toRefundResponse(rf, c)
+		}
+	}
+
+	return nil, fmt.Errorf("stored refund %s of payment %s not found", refundID, paymentID)
+}
+
+// refundFingerprint hashes the parts of the refund request that must match when its idempotency key is reused
+func refundFingerprint(r *pb.RefundRequest) string {
+	return idempotency.Fingerprint("refund", r.ID, r.OrderID, strconv.FormatInt(r.Amount, 10), r.Currency, r.Reason)
+}
+
+// ListMethods returns the payment methods customers can pay with
+func (h *PaymentHandler) ListMethods(ctx context.Context, r *pb.ListMethodsRequest) (*pb.ListMethodsResponse, error) {
+	resp := &pb.ListMethodsResponse{}
+	for _, m := range h.methods.List() {
+		if !m.Enabled && !r.IncludeDisabled {
+			continue
+		}
+
+		resp.Methods = append(resp.Methods, &pb.PaymentMethod{
+			Name:        m.Name,
+			DisplayName: m.DisplayName,
+			Enabled:     m.Enabled,
+		})
+	}
+
+	return resp, nil
+}
+
+// fetchConfirmation looks up a confirmation by its ID or by the ID of the paid order
+func (h *PaymentHandler) fetchConfirmation(ctx context.Context, id, orderID string) (*payment.Confirmation, error) {
+	switch {
+	case id != "":
+		cid, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid confirmation ID: %v", payment.ErrInvalidRequest, err)
+		}
+
+		return h.r.FetchByID(ctx, cid)
+	case orderID != "":
+		oid, err := uuid.Parse(orderID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid order ID: %v", payment.ErrInvalidRequest, err)
+		}
+
+		return h.r.FetchByOrderID(ctx, oid)
+	default:
+		return nil, fmt.Errorf("%w: either the confirmation ID or the order ID is required", payment.ErrInvalidRequest)
+	}
+}
+
+func toConfirmationResponse(c *payment.Confirmation) (*pb.PaymentConfirmation, error) {
+	payedAt, err := ptypes.TimestampProto(c.PayedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert payment date: %w", err)
+	}
+
+	resp := &pb.PaymentConfirmation{
+		ID:       c.ID.String(),
+		OrderID:  c.OrderID.String(),
+		Method:   c.Method,
+		Amount:   c.Amount.Amount,
+		Currency: c.Amount.Currency,
+		PayedAt:  payedAt,
+		Status:   string(c.Status),
+		Refunded: c.Refunded.Amount,
+		Tendered: c.Tendered.Amount,
+		Change:   c.Change.Amount,
+		Tip:      c.Tip.Amount,
+		Barista:  c.Barista,
+	}
+
+	if c.ShiftID != uuid.Nil {
+		resp.ShiftID = c.ShiftID.String()
+	}
+
+	if !c.ExpiresAt.IsZero() {
+		if resp.ExpiresAt, err = ptypes.TimestampProto(c.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to convert authorization expiry: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
+func toRefundResponse(rf *payment.Refund, c *payment.Confirmation) (*pb.RefundConfirmation, error) {
+	refundedAt, err := ptypes.TimestampProto(rf.RefundedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert refund date: %w", err)
+	}
+
+	conf, err := toConfirmationResponse(c)
+	if err != nil {
+		return nil, err
+	}
 
-	logger.Debug("payment processed")
-	return &pb.PaymentConfirmation{ID: c.ID.String(), OrderID: c.OrderID.String()}, nil
+	return &pb.RefundConfirmation{
+		ID:         rf.ID.String(),
+		PaymentID:  rf.PaymentID.String(),
+		OrderID:    rf.OrderID.String(),
+		Amount:     rf.Amount.Amount,
+		Currency:   rf.Amount.Currency,
+		Reason:     rf.Reason,
+		RefundedAt: refundedAt,
+		Payment:    conf,
+	}, nil
 }
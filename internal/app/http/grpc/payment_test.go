@@ -0,0 +1,137 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/app/storage/inmem"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/italolelis/coffee-shop/internal/pkg/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentHandler_AuthorizeSplit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	card, err := payment.IssueGiftCard(money.New(300, "EUR"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		tenders         []*pb.Tender
+		expectedErr     error
+		expectedStatus  []payment.Status
+		expectedBalance money.Money
+	}{
+		{
+			name: "every tender authorized",
+			tenders: []*pb.Tender{
+				{Method: "gift_card", Amount: 300, GiftCardNumber: card.Number},
+				{Method: "credit_card", Amount: 480},
+			},
+			expectedStatus:  []payment.Status{payment.StatusCaptured, payment.StatusAuthorized},
+			expectedBalance: money.New(0, "EUR"),
+		},
+		{
+			name: "later tender declined",
+			tenders: []*pb.Tender{
+				{Method: "credit_card", Amount: 380},
+				{Method: "gift_card", Amount: 400, GiftCardNumber: card.Number},
+			},
+			expectedErr:     payment.ErrDeclined,
+			expectedStatus:  []payment.Status{payment.StatusVoided},
+			expectedBalance: money.New(300, "EUR"),
+		},
+		{
+			name: "tenders don't add up to the total",
+			tenders: []*pb.Tender{
+				{Method: "credit_card", Amount: 500},
+				{Method: "gift_card", Amount: 100, GiftCardNumber: card.Number},
+			},
+			expectedErr:     payment.ErrInvalidRequest,
+			expectedStatus:  []payment.Status{},
+			expectedBalance: money.New(300, "EUR"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cards := inmem.NewGiftCardReadWrite()
+			require.NoError(t, cards.Add(ctx, card))
+
+			methods := payment.NewRegistry()
+			require.NoError(t, methods.Register("credit_card", payment.MethodConfig{Enabled: true}, payment.NewCreditCard))
+			require.NoError(t, methods.Register("gift_card", payment.MethodConfig{Enabled: true}, payment.NewGiftCard(cards)))
+
+			store := inmem.NewPaymentReadWrite()
+			h := &PaymentHandler{methods: methods, r: store, w: store, gr: cards, gw: cards}
+
+			orderID := uuid.New()
+			resp, err := h.AuthorizeSplit(ctx, &pb.PaymentRequest{
+				OrderID:  orderID.String(),
+				Amount:   780,
+				Currency: "EUR",
+				Tenders:  tt.tenders,
+			})
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, resp.Payments, len(tt.tenders))
+			}
+
+			confirmations, err := store.FetchAllByOrderID(ctx, orderID)
+			require.NoError(t, err)
+
+			statuses := make([]payment.Status, 0, len(confirmations))
+			for _, c := range confirmations {
+				statuses = append(statuses, c.Status)
+			}
+			assert.Equal(t, tt.expectedStatus, statuses)
+
+			balance, err := cards.FetchByNumber(ctx, card.Number)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedBalance, balance.Balance)
+		})
+	}
+}
+
+func TestPaymentHandler_VoidOrder(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	methods := payment.NewRegistry()
+	require.NoError(t, methods.Register("credit_card", payment.MethodConfig{Enabled: true}, payment.NewCreditCard))
+	require.NoError(t, methods.Register("apple_pay", payment.MethodConfig{Enabled: true}, payment.NewApplePay))
+
+	store := inmem.NewPaymentReadWrite()
+	h := &PaymentHandler{methods: methods, r: store, w: store}
+
+	orderID := uuid.New()
+	_, err := h.AuthorizeSplit(ctx, &pb.PaymentRequest{
+		OrderID:  orderID.String(),
+		Amount:   780,
+		Currency: "EUR",
+		Tenders:  []*pb.Tender{{Method: "credit_card", Amount: 500}, {Method: "apple_pay", Amount: 280}},
+	})
+	require.NoError(t, err)
+
+	_, err = h.Void(ctx, &pb.VoidRequest{OrderID: orderID.String(), Reason: "order not saved"})
+	require.NoError(t, err)
+
+	confirmations, err := store.FetchAllByOrderID(ctx, orderID)
+	require.NoError(t, err)
+	require.Len(t, confirmations, 2)
+	for _, c := range confirmations {
+		assert.Equal(t, payment.StatusVoided, c.Status)
+	}
+}
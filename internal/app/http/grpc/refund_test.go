@@ -0,0 +1,128 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/app/storage/inmem"
+	"github.com/italolelis/coffee-shop/internal/pkg/idempotency"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/italolelis/coffee-shop/internal/pkg/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRefundHandler creates a handler with a gift card paid for 1000
+func newRefundHandler(t *testing.T) (*PaymentHandler, *inmem.GiftCardReadWrite, *payment.GiftCardAccount, *pb.PaymentConfirmation) {
+	ctx := context.Background()
+
+	card, err := payment.IssueGiftCard(money.New(1000, "EUR"))
+	require.NoError(t, err)
+
+	cards := inmem.NewGiftCardReadWrite()
+	require.NoError(t, cards.Add(ctx, card))
+
+	methods := payment.NewRegistry()
+	require.NoError(t, methods.Register("gift_card", payment.MethodConfig{Enabled: true}, payment.NewGiftCard(cards)))
+
+	store := inmem.NewPaymentReadWrite()
+	refunds := inmem.NewRefundReadWrite(store)
+	h := &PaymentHandler{
+		methods: methods, r: store, w: store, rr: refunds, rw: refunds, gr: cards, gw: cards,
+		idem: inmem.NewIdempotencyStore(time.Hour),
+	}
+
+	c, err := h.Pay(ctx, &pb.PaymentRequest{
+		OrderID: uuid.New().String(), Method: "gift_card", Amount: 1000, Currency: "EUR", GiftCardNumber: card.Number,
+	})
+	require.NoError(t, err)
+
+	return h, cards, card, c
+}
+
+func TestPaymentHandler_ConcurrentRefunds(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx                = context.Background()
+		h, cards, card, c  = newRefundHandler(t)
+		wg                 sync.WaitGroup
+		mux                sync.Mutex
+		refunded, rejected int
+	)
+
+	// more refunds than the payment covers run at once, only the ones it covers are given back
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := h.Refund(ctx, &pb.RefundRequest{ID: c.ID, Amount: 300, Currency: "EUR"})
+
+			mux.Lock()
+			defer mux.Unlock()
+
+			if err == nil {
+				refunded++
+				return
+			}
+
+			assert.True(t, errors.Is(err, payment.ErrRefundExceedsPayment), err)
+			rejected++
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(t, 3, refunded)
+	assert.Equal(t, 7, rejected)
+
+	conf, err := h.GetConfirmation(ctx, &pb.GetConfirmationRequest{ID: c.ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(900), conf.Refunded)
+
+	refunds, err := h.rr.FetchByPaymentID(ctx, uuid.MustParse(c.ID))
+	require.NoError(t, err)
+	assert.Len(t, refunds, 3)
+
+	balance, err := cards.FetchByNumber(ctx, card.Number)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(900, "EUR"), balance.Balance)
+}
+
+func TestPaymentHandler_IdempotentRefund(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx               = context.Background()
+		h, cards, card, c = newRefundHandler(t)
+	)
+
+	req := &pb.RefundRequest{ID: c.ID, Amount: 300, Currency: "EUR", IdempotencyKey: "refund-1"}
+
+	first, err := h.Refund(ctx, req)
+	require.NoError(t, err)
+
+	replayed, err := h.Refund(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, replayed.ID)
+	assert.Equal(t, int64(300), replayed.Payment.Refunded)
+
+	_, err = h.Refund(ctx, &pb.RefundRequest{ID: c.ID, Amount: 500, Currency: "EUR", IdempotencyKey: "refund-1"})
+	assert.True(t, errors.Is(err, idempotency.ErrKeyReused))
+
+	// a failed refund frees its key, so it can be retried
+	_, err = h.Refund(ctx, &pb.RefundRequest{ID: c.ID, Amount: 800, Currency: "EUR", IdempotencyKey: "refund-2"})
+	assert.True(t, errors.Is(err, payment.ErrRefundExceedsPayment))
+
+	_, err = h.Refund(ctx, &pb.RefundRequest{ID: c.ID, Amount: 800, Currency: "EUR", IdempotencyKey: "refund-2"})
+	assert.True(t, errors.Is(err, payment.ErrRefundExceedsPayment))
+
+	balance, err := cards.FetchByNumber(ctx, card.Number)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(300, "EUR"), balance.Balance)
+}
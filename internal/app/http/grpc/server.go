@@ -6,6 +6,8 @@ import (
 	"net"
 	"time"
 
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/pkg/idempotency"
 	"github.com/italolelis/coffee-shop/internal/pkg/pb"
 	"go.opentelemetry.io/otel/api/trace"
 	"go.opentelemetry.io/otel/plugin/grpctrace"
@@ -25,17 +27,30 @@ type Server struct {
 }
 
 // NewServer creates a new Server
-func NewServer(cfg Config, tp trace.Tracer) *Server {
+func NewServer(
+	cfg Config,
+	tp trace.Tracer,
+	methods *payment.Registry,
+	r payment.Reader,
+	w payment.Writer,
+	rr payment.RefundReader,
+	rw payment.RefundWriter,
+	gr payment.GiftCardReader,
+	gw payment.GiftCardWriter,
+	dr payment.DrawerReader,
+	dw payment.DrawerWriter,
+	idem idempotency.Store,
+) *Server {
 	return &Server{
 		cfg: cfg,
 		g: grpc.NewServer(
-			grpc.UnaryInterceptor(grpctrace.UnaryServerInterceptor(tp)),
+			grpc.ChainUnaryInterceptor(grpctrace.UnaryServerInterceptor(tp), errorInterceptor),
 			grpc.StreamInterceptor(grpctrace.StreamServerInterceptor(tp)),
 			grpc.KeepaliveParams(keepalive.ServerParameters{
 				Timeout: 30 * time.Second,
 			}),
 		),
-		ph: &PaymentHandler{},
+		ph: &PaymentHandler{methods: methods, r: r, w: w, rr: rr, rw: rw, gr: gr, gw: gw, dr: dr, dw: dw, idem: idem},
 	}
 }
 
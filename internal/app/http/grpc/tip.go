@@ -0,0 +1,69 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/pkg/pb"
+)
+
+// defaultTipReportPeriod is covered by tip reports requested without a period
+const defaultTipReportPeriod = 24 * time.Hour
+
+// GetTipReport sums the tips of the payments made in the requested period by barista and shift
+func (h *PaymentHandler) GetTipReport(ctx context.Context, r *pb.TipReportRequest) (*pb.TipReport, error) {
+	to := time.Now().UTC()
+	if r.To != nil {
+		t, err := ptypes.Timestamp(r.To)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid end of the period: %v", payment.ErrInvalidRequest, err)
+		}
+
+		to = t
+	}
+
+	from := to.Add(-defaultTipReportPeriod)
+	if r.From != nil {
+		f, err := ptypes.Timestamp(r.From)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid start of the period: %v", payment.ErrInvalidRequest, err)
+		}
+
+		from = f
+	}
+
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: the period must start before it ends", payment.ErrInvalidRequest)
+	}
+
+	confirmations, err := h.r.FetchPaidBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.TipReport{Totals: make([]*pb.TipTotal, 0)}
+	for _, t := range payment.TipTotals(confirmations) {
+		if r.Barista != "" && t.Barista != r.Barista {
+			continue
+		}
+
+		total := &pb.TipTotal{
+			Barista:  t.Barista,
+			Amount:   t.Amount.Amount,
+			Currency: t.Amount.Currency,
+			Payments: int32(t.Payments),
+		}
+
+		if t.ShiftID != uuid.Nil {
+			total.ShiftID = t.ShiftID.String()
+		}
+
+		resp.Totals = append(resp.Totals, total)
+	}
+
+	return resp, nil
+}
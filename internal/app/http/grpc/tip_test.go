@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/app/storage/inmem"
+	"github.com/italolelis/coffee-shop/internal/pkg/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentHandler_GetTipReport(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	drawers := inmem.NewDrawerReadWrite()
+	methods := payment.NewRegistry()
+	require.NoError(t, methods.Register("cash", payment.MethodConfig{Enabled: true}, payment.NewCash(drawers)))
+	require.NoError(t, methods.Register("credit_card", payment.MethodConfig{Enabled: true}, payment.NewCreditCard))
+
+	store := inmem.NewPaymentReadWrite()
+	h := &PaymentHandler{methods: methods, r: store, w: store, rw: inmem.NewRefundReadWrite(store), dr: drawers, dw: drawers}
+
+	// tipped while no drawer is open
+	c, err := h.Pay(ctx, &pb.PaymentRequest{
+		OrderID: uuid.New().String(), Method: "credit_card", Amount: 780, Currency: "EUR", Tip: 100, Barista: "anna",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(880), c.Amount)
+	assert.Equal(t, int64(100), c.Tip)
+	assert.Empty(t, c.ShiftID)
+
+	shift, err := h.OpenDrawer(ctx, &pb.OpenDrawerRequest{Float: 10000, Currency: "EUR"})
+	require.NoError(t, err)
+
+	c, err = h.Pay(ctx, &pb.PaymentRequest{
+		OrderID: uuid.New().String(), Method: "cash", Amount: 780, Currency: "EUR", CashTendered: 1000, Tip: 50, Barista: "anna",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(170), c.Change)
+	assert.Equal(t, shift.ID, c.ShiftID)
+
+	split, err := h.PaySplit(ctx, &pb.PaymentRequest{
+		OrderID: uuid.New().String(), Amount: 780, Currency: "EUR", Tip: 80, Barista: "ben",
+		Tenders: []*pb.Tender{{Method: "cash", Amount: 500, CashTendered: 500}, {Method: "credit_card", Amount: 360}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), split.Payments[0].Tip)
+	assert.Equal(t, int64(80), split.Payments[1].Tip)
+
+	_, err = h.PaySplit(ctx, &pb.PaymentRequest{
+		OrderID: uuid.New().String(), Amount: 780, Currency: "EUR", Tip: 80, Barista: "ben",
+		Tenders: []*pb.Tender{{Method: "credit_card", Amount: 800}, {Method: "cash", Amount: 60, CashTendered: 60}},
+	})
+	assert.True(t, errors.Is(err, payment.ErrInvalidRequest))
+
+	voided, err := h.Pay(ctx, &pb.PaymentRequest{
+		OrderID: uuid.New().String(), Method: "credit_card", Amount: 780, Currency: "EUR", Tip: 200, Barista: "ben",
+	})
+	require.NoError(t, err)
+	_, err = h.Void(ctx, &pb.VoidRequest{ID: voided.ID})
+	require.NoError(t, err)
+
+	report, err := h.GetTipReport(ctx, &pb.TipReportRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []*pb.TipTotal{
+		{Barista: "anna", Amount: 100, Currency: "EUR", Payments: 1},
+		{Barista: "anna", ShiftID: shift.ID, Amount: 50, Currency: "EUR", Payments: 1},
+		{Barista: "ben", ShiftID: shift.ID, Amount: 80, Currency: "EUR", Payments: 1},
+	}, report.Totals)
+
+	report, err = h.GetTipReport(ctx, &pb.TipReportRequest{Barista: "ben"})
+	require.NoError(t, err)
+	require.Len(t, report.Totals, 1)
+	assert.Equal(t, int64(80), report.Totals[0].Amount)
+
+	drawer, err := h.GetDrawerReport(ctx, &pb.GetDrawerReportRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1330), drawer.Sales)
+}
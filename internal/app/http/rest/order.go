@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
@@ -25,19 +27,22 @@ func (h OrderHandler) Checkout(w http.ResponseWriter, r *http.Request) {
 
 	var cmd order.CheckoutCommand
 	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		logger.Errorw("failed to decode payload", "err", err)
+		logger.Debugw("failed to decode payload", "err", err)
+		writeProblem(w, r, newProblem(http.StatusBadRequest, "invalid_payload", "Invalid payload", err.Error()))
 
-		http.Error(w, "failed to decode payload", http.StatusBadRequest)
+		return
+	}
 
+	if err := orderIDFromURL(r, &cmd.OrderID); err != nil {
+		writeProblem(w, r, newProblem(http.StatusBadRequest, "invalid_order_id", "Invalid order id", err.Error()))
 		return
 	}
 
+	cmd.IdempotencyKey = r.Header.Get("Idempotency-Key")
+
 	orderID, err := h.srv.Checkout(ctx, cmd)
 	if err != nil {
-		logger.Errorw("failed to checkout order", "err", err)
-
-		http.Error(w, "failed to checkout order", http.StatusInternalServerError)
-
+		renderError(w, r, logger, "failed to checkout order", err)
 		return
 	}
 
@@ -53,19 +58,20 @@ func (h OrderHandler) AddToOrder(w http.ResponseWriter, r *http.Request) {
 
 	var cmd order.AddToOrderCommand
 	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		logger.Errorw("failed to decode payload", "err", err)
+		logger.Debugw("failed to decode payload", "err", err)
+		writeProblem(w, r, newProblem(http.StatusBadRequest, "invalid_payload", "Invalid payload", err.Error()))
 
-		http.Error(w, "failed to decode payload", http.StatusBadRequest)
+		return
+	}
 
+	if err := orderIDFromURL(r, &cmd.OrderID); err != nil {
+		writeProblem(w, r, newProblem(http.StatusBadRequest, "invalid_order_id", "Invalid order id", err.Error()))
 		return
 	}
 
 	orderID, err := h.srv.AddToOrder(ctx, cmd)
 	if err != nil {
-		logger.Errorw("failed to add items to order", "err", err)
-
-		http.Error(w, "failed to add items to order", http.StatusInternalServerError)
-
+		renderError(w, r, logger, "failed to add items to order", err)
 		return
 	}
 
@@ -82,22 +88,298 @@ func (h OrderHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
 
 	orderID, err := uuid.Parse(rawOrderID)
 	if err != nil {
-		http.Error(w, "invalid order id", http.StatusBadRequest)
+		writeProblem(w, r, newProblem(http.StatusBadRequest, "invalid_order_id", "Invalid order id", err.Error()))
 		return
 	}
 
 	o, err := h.srv.Fetch(ctx, orderID)
 	if err != nil {
-		if errors.Is(order.ErrNotFound, err) {
-			http.Error(w, "couldn't find order", http.StatusNotFound)
-			return
-		}
+		renderError(w, r, logger, "failed to fetch order", err)
+		return
+	}
+
+	render.JSON(w, r, o)
+}
+
+// RemoveItem removes a line from an open order, responding with the changed order
+func (h OrderHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		logger = log.WithContext(ctx).Named("orders").With("action", "remove-item")
+	)
+
+	var cmd order.RemoveItemCommand
+	if err := lineFromURL(w, r, &cmd.OrderID, &cmd.LineID); err != nil {
+		return
+	}
+
+	o, err := h.srv.RemoveItem(ctx, cmd)
+	if err != nil {
+		renderError(w, r, logger, "failed to remove item", err)
+		return
+	}
+
+	render.JSON(w, r, o)
+}
+
+// UpdateItem changes the quantity of a line of an open order, responding with the changed order
+func (h OrderHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		logger = log.WithContext(ctx).Named("orders").With("action", "update-item")
+	)
+
+	var cmd order.UpdateItemQtyCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		logger.Debugw("failed to decode payload", "err", err)
+		writeProblem(w, r, newProblem(http.StatusBadRequest, "invalid_payload", "Invalid payload", err.Error()))
+
+		return
+	}
+
+	if err := lineFromURL(w, r, &cmd.OrderID, &cmd.LineID); err != nil {
+		return
+	}
+
+	o, err := h.srv.UpdateItemQty(ctx, cmd)
+	if err != nil {
+		renderError(w, r, logger, "failed to update item", err)
+		return
+	}
+
+	render.JSON(w, r, o)
+}
+
+// ApplyCoupon applies a coupon to an open order, responding with the order and its discounts.
+// An empty coupon removes the coupon of the order.
+func (h OrderHandler) ApplyCoupon(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		logger = log.WithContext(ctx).Named("orders").With("action", "apply-coupon")
+	)
+
+	var cmd order.ApplyCouponCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		logger.Debugw("failed to decode payload", "err", err)
+		writeProblem(w, r, newProblem(http.StatusBadRequest, "invalid_payload", "Invalid payload", err.Error()))
+
+		return
+	}
 
-		logger.Errorw("failed to fetch order", "err", err)
-		http.Error(w, "failed to fetch order", http.StatusInternalServerError)
+	if err := orderIDFromURL(r, &cmd.OrderID); err != nil {
+		writeProblem(w, r, newProblem(http.StatusBadRequest, "invalid_order_id", "Invalid order id", err.Error()))
+		return
+	}
 
+	o, err := h.srv.ApplyCoupon(ctx, cmd)
+	if err != nil {
+		renderError(w, r, logger, "failed to apply coupon", err)
 		return
 	}
 
 	render.JSON(w, r, o)
 }
+
+// GetReceipt renders the order as a plain text receipt
+func (h OrderHandler) GetReceipt(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx        = r.Context()
+		logger     = log.WithContext(ctx).Named("orders").With("action", "get-receipt")
+		rawOrderID = chi.URLParam(r, "orderID")
+	)
+
+	orderID, err := uuid.Parse(rawOrderID)
+	if err != nil {
+		writeProblem(w, r, newProblem(http.StatusBadRequest, "invalid_order_id", "Invalid order id", err.Error()))
+		return
+	}
+
+	o, err := h.srv.Fetch(ctx, orderID)
+	if err != nil {
+		renderError(w, r, logger, "failed to fetch order", err)
+		return
+	}
+
+	render.PlainText(w, r, o.Receipt())
+}
+
+// GetPayments returns the payments of the order, one for each tender
+func (h OrderHandler) GetPayments(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx        = r.Context()
+		logger     = log.WithContext(ctx).Named("orders").With("action", "get-payments")
+		rawOrderID = chi.URLParam(r, "orderID")
+	)
+
+	orderID, err := uuid.Parse(rawOrderID)
+	if err != nil {
+		writeProblem(w, r, newProblem(http.StatusBadRequest, "invalid_order_id", "Invalid order id", err.Error()))
+		return
+	}
+
+	payments, err := h.srv.FetchPayments(ctx, orderID)
+	if err != nil {
+		renderError(w, r, logger, "failed to fetch payments", err)
+		return
+	}
+
+	render.JSON(w, r, payments)
+}
+
+// Refund gives back part or all of the payment of the order, responding with a refund for
+// each refunded tender. An empty body refunds the whole payment.
+func (h OrderHandler) Refund(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		logger = log.WithContext(ctx).Named("orders").With("action", "refund")
+	)
+
+	var cmd order.RefundCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil && !errors.Is(err, io.EOF) {
+		logger.Debugw("failed to decode payload", "err", err)
+		writeProblem(w, r, newProblem(http.StatusBadRequest, "invalid_payload", "Invalid payload", err.Error()))
+
+		return
+	}
+
+	if err := orderIDFromURL(r, &cmd.OrderID); err != nil {
+		writeProblem(w, r, newProblem(http.StatusBadRequest, "invalid_order_id", "Invalid order id", err.Error()))
+		return
+	}
+
+	cmd.IdempotencyKey = r.Header.Get("Idempotency-Key")
+
+	refunds, err := h.srv.Refund(ctx, cmd)
+	if err != nil {
+		renderError(w, r, logger, "failed to refund order", err)
+		return
+	}
+
+	w.Header().Add("Location", fmt.Sprintf("/orders/%s/payments", cmd.OrderID))
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, refunds)
+}
+
+// UpdateStatus moves the order to the next preparation step, capturing its payment on pick up
+func (h OrderHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		logger = log.WithContext(ctx).Named("orders").With("action", "update-status")
+	)
+
+	var cmd order.UpdateStatusCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		logger.Debugw("failed to decode payload", "err", err)
+		writeProblem(w, r, newProblem(http.StatusBadRequest, "invalid_payload", "Invalid payload", err.Error()))
+
+		return
+	}
+
+	if err := orderIDFromURL(r, &cmd.OrderID); err != nil {
+		writeProblem(w, r, newProblem(http.StatusBadRequest, "invalid_order_id", "Invalid order id", err.Error()))
+		return
+	}
+
+	o, err := h.srv.UpdateStatus(ctx, cmd)
+	if err != nil {
+		renderError(w, r, logger, "failed to update order status", err)
+		return
+	}
+
+	render.JSON(w, r, o)
+}
+
+// ListPaymentMethods returns the payment methods customers can checkout with
+func (h OrderHandler) ListPaymentMethods(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		logger = log.WithContext(ctx).Named("orders").With("action", "list-payment-methods")
+	)
+
+	methods, err := h.srv.PaymentMethods(ctx)
+	if err != nil {
+		renderError(w, r, logger, "failed to list payment methods", err)
+		return
+	}
+
+	render.JSON(w, r, methods)
+}
+
+// TipReport sums the tips given in the period of the query by barista and shift. The
+// period is given by the from and to query parameters in RFC 3339 and the barista
+// parameter limits the report to a single barista.
+func (h OrderHandler) TipReport(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		logger = log.WithContext(ctx).Named("orders").With("action", "tip-report")
+		query  = r.URL.Query()
+		errs   order.ValidationErrors
+	)
+
+	q := order.TipReportQuery{Barista: query.Get("barista")}
+	for _, p := range []struct {
+		field string
+		t     *time.Time
+	}{{"from", &q.From}, {"to", &q.To}} {
+		raw := query.Get(p.field)
+		if raw == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			errs = append(errs, &order.ValidationError{Field: p.field, Reason: "must be a date in RFC 3339", Err: err})
+			continue
+		}
+
+		*p.t = parsed
+	}
+
+	if len(errs) > 0 {
+		writeProblem(w, r, validationProblem(errs))
+		return
+	}
+
+	totals, err := h.srv.TipReport(ctx, q)
+	if err != nil {
+		renderError(w, r, logger, "failed to fetch the tip report", err)
+		return
+	}
+
+	render.JSON(w, r, totals)
+}
+
+// lineFromURL parses the order and line IDs of the URL, writing a problem when they are invalid
+func lineFromURL(w http.ResponseWriter, r *http.Request, orderID, lineID *uuid.UUID) error {
+	if err := orderIDFromURL(r, orderID); err != nil {
+		writeProblem(w, r, newProblem(http.StatusBadRequest, "invalid_order_id", "Invalid order id", err.Error()))
+		return err
+	}
+
+	parsed, err := uuid.Parse(chi.URLParam(r, "lineID"))
+	if err != nil {
+		writeProblem(w, r, newProblem(http.StatusBadRequest, "invalid_line_id", "Invalid line id", err.Error()))
+		return err
+	}
+
+	*lineID = parsed
+
+	return nil
+}
+
+// orderIDFromURL sets the order id from the URL, when the route has one
+func orderIDFromURL(r *http.Request, id *uuid.UUID) error {
+	raw := chi.URLParam(r, "orderID")
+	if raw == "" {
+		return nil
+	}
+
+	parsed, err := uuid.Parse(raw)
+	if err != nil {
+		return err
+	}
+
+	*id = parsed
+
+	return nil
+}
@@ -0,0 +1,653 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/catalog"
+	"github.com/italolelis/coffee-shop/internal/app/order"
+	"github.com/italolelis/coffee-shop/internal/pkg/idempotency"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeService is an order.Service whose behavior is defined per test
+type fakeService struct {
+	checkout      func(context.Context, order.CheckoutCommand) (uuid.UUID, error)
+	addToOrder    func(context.Context, order.AddToOrderCommand) (uuid.UUID, error)
+	fetch         func(context.Context, uuid.UUID) (*order.Order, error)
+	fetchPayments func(context.Context, uuid.UUID) ([]*order.Payment, error)
+	refund        func(context.Context, order.RefundCommand) ([]*order.Refund, error)
+	updateStatus  func(context.Context, order.UpdateStatusCommand) (*order.Order, error)
+	removeItem    func(context.Context, order.RemoveItemCommand) (*order.Order, error)
+	updateItemQty func(context.Context, order.UpdateItemQtyCommand) (*order.Order, error)
+	applyCoupon   func(context.Context, order.ApplyCouponCommand) (*order.Order, error)
+	methods       func(context.Context) ([]order.PaymentMethod, error)
+	tipReport     func(context.Context, order.TipReportQuery) ([]*order.TipTotal, error)
+}
+
+func (f *fakeService) Checkout(ctx context.Context, cmd order.CheckoutCommand) (uuid.UUID, error) {
+	return f.checkout(ctx, cmd)
+}
+
+func (f *fakeService) AddToOrder(ctx context.Context, cmd order.AddToOrderCommand) (uuid.UUID, error) {
+	return f.addToOrder(ctx, cmd)
+}
+
+func (f *fakeService) Fetch(ctx context.Context, id uuid.UUID) (*order.Order, error) {
+	return f.fetch(ctx, id)
+}
+
+func (f *fakeService) FetchPayments(ctx context.Context, id uuid.UUID) ([]*order.Payment, error) {
+	return f.fetchPayments(ctx, id)
+}
+
+func (f *fakeService) Refund(ctx context.Context, cmd order.RefundCommand) ([]*order.Refund, error) {
+	return f.refund(ctx, cmd)
+}
+
+func (f *fakeService) UpdateStatus(ctx context.Context, cmd order.UpdateStatusCommand) (*order.Order, error) {
+	return f.updateStatus(ctx, cmd)
+}
+
+func (f *fakeService) RemoveItem(ctx context.Context, cmd order.RemoveItemCommand) (*order.Order, error) {
+	return f.removeItem(ctx, cmd)
+}
+
+func (f *fakeService) UpdateItemQty(ctx context.Context, cmd order.UpdateItemQtyCommand) (*order.Order, error) {
+	return f.updateItemQty(ctx, cmd)
+}
+
+func (f *fakeService) ApplyCoupon(ctx context.Context, cmd order.ApplyCouponCommand) (*order.Order, error) {
+	return f.applyCoupon(ctx, cmd)
+}
+
+func (f *fakeService) PaymentMethods(ctx context.Context) ([]order.PaymentMethod, error) {
+	return f.methods(ctx)
+}
+
+func (f *fakeService) TipReport(ctx context.Context, q order.TipReportQuery) ([]*order.TipTotal, error) {
+	return f.tipReport(ctx, q)
+}
+
+func serve(srv order.Service, method, target, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	rec := httptest.NewRecorder()
+	NewServer(Config{}, srv).Handler().ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestOrderHandler_AddToOrder(t *testing.T) {
+	t.Parallel()
+
+	orderID := uuid.New()
+
+	tests := []struct {
+		name             string
+		target           string
+		body             string
+		err              error
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{
+			name:             "add to customer cart",
+			target:           "/orders/",
+			body:             `{"customer_name": "anna", "items": [{"name": "latte", "serving_size": "M", "qty": 1}]}`,
+			expectedStatus:   http.StatusCreated,
+			expectedLocation: "/orders/" + orderID.String(),
+		},
+		{
+			name:             "add to explicit order",
+			target:           "/orders/" + orderID.String() + "/items",
+			body:             `{"items": [{"name": "latte", "serving_size": "M", "qty": 1}]}`,
+			expectedStatus:   http.StatusCreated,
+			expectedLocation: "/orders/" + orderID.String(),
+		},
+		{
+			name:           "invalid payload",
+			target:         "/orders/",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "unknown product",
+			target: "/orders/",
+			body:   `{"customer_name": "anna", "items": [{"name": "tea", "serving_size": "M", "qty": 1}]}`,
+			err: order.ValidationErrors{
+				{Field: "items[0].name", Reason: `"tea" is not in the menu`, Err: catalog.ErrProductNotFound},
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "unknown order",
+			target:         "/orders/" + uuid.New().String() + "/items",
+			body:           `{"items": [{"name": "latte", "serving_size": "M", "qty": 1}]}`,
+			err:            order.ErrNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := &fakeService{addToOrder: func(_ context.Context, cmd order.AddToOrderCommand) (uuid.UUID, error) {
+				if tt.err != nil {
+					return uuid.Nil, tt.err
+				}
+
+				return orderID, nil
+			}}
+
+			rec := serve(srv, http.MethodPost, tt.target, tt.body, nil)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedLocation, rec.Header().Get("Location"))
+		})
+	}
+}
+
+func TestOrderHandler_Checkout(t *testing.T) {
+	t.Parallel()
+
+	orderID := uuid.New()
+
+	var received order.CheckoutCommand
+	srv := &fakeService{checkout: func(_ context.Context, cmd order.CheckoutCommand) (uuid.UUID, error) {
+		received = cmd
+		return cmd.OrderID, nil
+	}}
+
+	rec := serve(srv, http.MethodPost, "/orders/"+orderID.String()+"/checkout",
+		`{"payment_method": "credit_card"}`, map[string]string{"Idempotency-Key": "abc"})
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "/orders/"+orderID.String(), rec.Header().Get("Location"))
+	assert.Equal(t, orderID, received.OrderID)
+	assert.Equal(t, "abc", received.IdempotencyKey)
+
+	srv.checkout = func(context.Context, order.CheckoutCommand) (uuid.UUID, error) {
+		return uuid.Nil, idempotency.ErrInProgress
+	}
+
+	rec = serve(srv, http.MethodPost, "/orders/checkout", `{"customer_name": "anna"}`, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestOrderHandler_Problems(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		err            error
+		expected       Problem
+		expectedParams []InvalidParam
+	}{
+		{
+			name: "validation errors",
+			err: order.ValidationErrors{
+				{Field: "payment_method", Reason: "is required"},
+			},
+			expected: Problem{Status: http.StatusUnprocessableEntity, Code: "validation_failed"},
+			expectedParams: []InvalidParam{
+				{Name: "payment_method", Reason: "is required"},
+			},
+		},
+		{
+			name:     "payment failed",
+			err:      fmt.Errorf("%w: card expired", order.ErrPaymentFailed),
+			expected: Problem{Status: http.StatusPaymentRequired, Code: "payment_failed"},
+		},
+		{
+			name:     "payment declined",
+			err:      &order.PaymentError{Kind: order.ErrPaymentDeclined, Reason: "insufficient funds"},
+			expected: Problem{Status: http.StatusPaymentRequired, Code: "payment_declined"},
+		},
+		{
+			name:     "authorization expired",
+			err:      &order.PaymentError{Kind: order.ErrPaymentConflict, Reason: "authorization expired"},
+			expected: Problem{Status: http.StatusConflict, Code: "payment_conflict"},
+		},
+		{
+			name:     "payment service unavailable",
+			err:      &order.PaymentError{Kind: order.ErrPaymentUnavailable, Reason: "connection refused", Retryable: true},
+			expected: Problem{Status: http.StatusServiceUnavailable, Code: "payment_unavailable", Retryable: true},
+		},
+		{
+			name: "payment service fault",
+			err: &order.PaymentError{
+				Kind:   order.ErrPaymentServiceFault,
+				Reason: `pq: duplicate key value violates unique constraint "payment_confirmations_pkey"`,
+			},
+			expected: Problem{
+				Status: http.StatusBadGateway,
+				Code:   "payment_service_error",
+				Detail: "the payment service failed to process the request",
+			},
+		},
+		{
+			name:     "order changed concurrently",
+			err:      fmt.Errorf("failed saving order: %w", order.ErrConflict),
+			expected: Problem{Status: http.StatusConflict, Code: "order_conflict"},
+		},
+		{
+			name:     "order already checked out",
+			err:      fmt.Errorf("failed to checkout order: %w", order.ErrInvalidTransition),
+			expected: Problem{Status: http.StatusConflict, Code: "invalid_status_transition"},
+		},
+		{
+			name:     "unexpected error",
+			err:      errors.New("connection reset"),
+			expected: Problem{Status: http.StatusInternalServerError, Code: "internal_error"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := &fakeService{checkout: func(context.Context, order.CheckoutCommand) (uuid.UUID, error) {
+				return uuid.Nil, tt.err
+			}}
+
+			rec := serve(srv, http.MethodPost, "/orders/checkout", `{"customer_name": "anna"}`, nil)
+			assert.Equal(t, tt.expected.Status, rec.Code)
+			assert.Equal(t, problemContentType, rec.Header().Get("Content-Type"))
+
+			var p Problem
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&p))
+			assert.Equal(t, tt.expected.Status, p.Status)
+			assert.Equal(t, tt.expected.Code, p.Code)
+			assert.Equal(t, tt.expected.Retryable, p.Retryable)
+			assert.NotContains(t, p.Detail, tt.err.Error())
+			if tt.expected.Detail != "" {
+				assert.Equal(t, tt.expected.Detail, p.Detail)
+			}
+			assert.Equal(t, "/orders/checkout", p.Instance)
+			assert.Equal(t, tt.expectedParams, p.InvalidParams)
+		})
+	}
+}
+
+func TestOrderHandler_GetOrder(t *testing.T) {
+	t.Parallel()
+
+	o := order.New("anna")
+	require.NoError(t, o.AddItem(&order.Item{Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 1}))
+
+	srv := &fakeService{fetch: func(_ context.Context, id uuid.UUID) (*order.Order, error) {
+		if id != o.ID {
+			return nil, order.ErrNotFound
+		}
+
+		return o, nil
+	}}
+
+	rec := serve(srv, http.MethodGet, "/orders/"+o.ID.String(), "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		ID     uuid.UUID    `json:"id"`
+		Status order.Status `json:"status"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Equal(t, o.ID, body.ID)
+	assert.Equal(t, order.StatusOpen, body.Status)
+
+	rec = serve(srv, http.MethodGet, "/orders/"+uuid.New().String(), "", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = serve(srv, http.MethodGet, "/orders/not-an-id", "", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestOrderHandler_ChangeItems(t *testing.T) {
+	t.Parallel()
+
+	o := order.New("anna")
+	require.NoError(t, o.AddItem(&order.Item{Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 2}))
+	lineID := o.Items[0].ID
+
+	change := func(orderID, id uuid.UUID, fn func(*order.Order) error) (*order.Order, error) {
+		if orderID != o.ID {
+			return nil, order.ErrNotFound
+		}
+
+		oc := *o
+		oc.Items = order.Items{&order.Item{ID: lineID, Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 2}}
+
+		return &oc, fn(&oc)
+	}
+
+	srv := &fakeService{
+		removeItem: func(_ context.Context, cmd order.RemoveItemCommand) (*order.Order, error) {
+			return change(cmd.OrderID, cmd.LineID, func(o *order.Order) error { return o.RemoveItem(cmd.LineID) })
+		},
+		updateItemQty: func(_ context.Context, cmd order.UpdateItemQtyCommand) (*order.Order, error) {
+			if err := cmd.Validate(); err != nil {
+				return nil, err
+			}
+
+			return change(cmd.OrderID, cmd.LineID, func(o *order.Order) error { return o.UpdateItemQty(cmd.LineID, cmd.Qty) })
+		},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "update qty",
+			method:         http.MethodPatch,
+			target:         "/orders/" + o.ID.String() + "/items/" + lineID.String(),
+			body:           `{"qty": 1}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "update to zero qty",
+			method:         http.MethodPatch,
+			target:         "/orders/" + o.ID.String() + "/items/" + lineID.String(),
+			body:           `{"qty": 0}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedCode:   "validation_failed",
+		},
+		{
+			name:           "remove line",
+			method:         http.MethodDelete,
+			target:         "/orders/" + o.ID.String() + "/items/" + lineID.String(),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown line",
+			method:         http.MethodDelete,
+			target:         "/orders/" + o.ID.String() + "/items/" + uuid.New().String(),
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "order_line_not_found",
+		},
+		{
+			name:           "invalid line id",
+			method:         http.MethodDelete,
+			target:         "/orders/" + o.ID.String() + "/items/not-an-id",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "invalid_line_id",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := serve(srv, tt.method, tt.target, tt.body, nil)
+			require.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedCode != "" {
+				var p Problem
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&p))
+				assert.Equal(t, tt.expectedCode, p.Code)
+			}
+		})
+	}
+}
+
+func TestOrderHandler_ApplyCoupon(t *testing.T) {
+	t.Parallel()
+
+	o := order.New("anna")
+	require.NoError(t, o.AddItem(&order.Item{Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 2}))
+
+	srv := &fakeService{applyCoupon: func(_ context.Context, cmd order.ApplyCouponCommand) (*order.Order, error) {
+		if cmd.OrderID != o.ID {
+			return nil, order.ErrNotFound
+		}
+
+		if cmd.Coupon != "WELCOME10" {
+			err := fmt.Errorf("%w: not found", order.ErrInvalidCoupon)
+			return nil, &order.ValidationError{Field: "coupon", Reason: err.Error(), Err: err}
+		}
+
+		oc := *o
+		oc.Coupon = cmd.Coupon
+		oc.Discounts = order.Discounts{{Promotion: "welcome", Code: cmd.Coupon, Amount: money.New(52, "EUR")}}
+
+		return &oc, nil
+	}}
+
+	tests := []struct {
+		name             string
+		target           string
+		body             string
+		expectedStatus   int
+		expectedCode     string
+		expectedDiscount money.Money
+		expectedTotal    money.Money
+	}{
+		{
+			name:             "valid coupon",
+			target:           "/orders/" + o.ID.String() + "/coupon",
+			body:             `{"coupon": "WELCOME10"}`,
+			expectedStatus:   http.StatusOK,
+			expectedDiscount: money.New(52, "EUR"),
+			expectedTotal:    money.New(468, "EUR"),
+		},
+		{
+			name:           "unknown coupon",
+			target:         "/orders/" + o.ID.String() + "/coupon",
+			body:           `{"coupon": "FREE"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedCode:   "validation_failed",
+		},
+		{
+			name:           "unknown order",
+			target:         "/orders/" + uuid.New().String() + "/coupon",
+			body:           `{"coupon": "WELCOME10"}`,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid payload",
+			target:         "/orders/" + o.ID.String() + "/coupon",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "invalid_payload",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := serve(srv, http.MethodPut, tt.target, tt.body, nil)
+			require.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedCode != "" {
+				var p Problem
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&p))
+				assert.Equal(t, tt.expectedCode, p.Code)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var resp struct {
+					Subtotal money.Money `json:"subtotal"`
+					Discount money.Money `json:"discount"`
+					Total    money.Money `json:"total"`
+				}
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, money.New(520, "EUR"), resp.Subtotal)
+				assert.Equal(t, tt.expectedDiscount, resp.Discount)
+				assert.Equal(t, tt.expectedTotal, resp.Total)
+			}
+		})
+	}
+}
+
+func TestOrderHandler_GetReceipt(t *testing.T) {
+	t.Parallel()
+
+	o := order.New("anna")
+	require.NoError(t, o.AddItem(&order.Item{
+		Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 1,
+		Modifiers: order.Modifiers{{Name: "oat milk", Price: money.New(40, "EUR")}},
+	}))
+
+	srv := &fakeService{fetch: func(_ context.Context, id uuid.UUID) (*order.Order, error) {
+		if id != o.ID {
+			return nil, order.ErrNotFound
+		}
+
+		return o, nil
+	}}
+
+	rec := serve(srv, http.MethodGet, "/orders/"+o.ID.String()+"/receipt", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+	assert.Equal(t, o.Receipt(), rec.Body.String())
+
+	rec = serve(srv, http.MethodGet, "/orders/"+uuid.New().String()+"/receipt", "", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestOrderHandler_Refund(t *testing.T) {
+	t.Parallel()
+
+	orderID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		err            error
+		expectedAmount *money.Money
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "full refund without body",
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "partial refund",
+			body:           `{"amount": {"amount": "1.30", "currency": "EUR"}, "reason": "cold coffee"}`,
+			expectedAmount: &money.Money{Amount: 130, Currency: "EUR"},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "refund exceeds payment",
+			body:           `{"amount": {"amount": "100.00", "currency": "EUR"}}`,
+			expectedAmount: &money.Money{Amount: 10000, Currency: "EUR"},
+			err:            fmt.Errorf("%w: only 7.80 EUR can be refunded", order.ErrRefundRejected),
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedCode:   "refund_rejected",
+		},
+		{
+			name:           "order not paid",
+			err:            order.ErrNotPaid,
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "payment_not_found",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := &fakeService{refund: func(_ context.Context, cmd order.RefundCommand) ([]*order.Refund, error) {
+				assert.Equal(t, orderID, cmd.OrderID)
+				assert.Equal(t, tt.expectedAmount, cmd.Amount)
+				assert.Equal(t, "abc", cmd.IdempotencyKey)
+
+				if tt.err != nil {
+					return nil, tt.err
+				}
+
+				return []*order.Refund{{ID: uuid.New(), OrderID: cmd.OrderID, Amount: money.New(780, "EUR")}}, nil
+			}}
+
+			rec := serve(srv, http.MethodPost, "/orders/"+orderID.String()+"/refund", tt.body, map[string]string{"Idempotency-Key": "abc"})
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedCode != "" {
+				var p Problem
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&p))
+				assert.Equal(t, tt.expectedCode, p.Code)
+			}
+		})
+	}
+}
+
+func TestOrderHandler_ListPaymentMethods(t *testing.T) {
+	t.Parallel()
+
+	srv := &fakeService{methods: func(context.Context) ([]order.PaymentMethod, error) {
+		return []order.PaymentMethod{{Name: "credit_card", DisplayName: "Credit card"}}, nil
+	}}
+
+	rec := serve(srv, http.MethodGet, "/payment-methods", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[{"name": "credit_card", "display_name": "Credit card"}]`, rec.Body.String())
+}
+
+func TestOrderHandler_TipReport(t *testing.T) {
+	t.Parallel()
+
+	shiftID := uuid.New()
+
+	var query order.TipReportQuery
+	srv := &fakeService{tipReport: func(_ context.Context, q order.TipReportQuery) ([]*order.TipTotal, error) {
+		query = q
+		return []*order.TipTotal{{Barista: "ben", ShiftID: shiftID, Amount: money.New(250, "EUR"), Payments: 3}}, nil
+	}}
+
+	rec := serve(srv, http.MethodGet, "/reports/tips?from=2026-10-18T08:00:00Z&to=2026-10-18T16:00:00Z&barista=ben", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[{"barista": "ben", "shift_id": "`+shiftID.String()+`", "amount": {"amount": "2.50", "currency": "EUR"}, "payments": 3}]`, rec.Body.String())
+	assert.Equal(t, order.TipReportQuery{
+		From:    time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC),
+		To:      time.Date(2026, 10, 18, 16, 0, 0, 0, time.UTC),
+		Barista: "ben",
+	}, query)
+
+	rec = serve(srv, http.MethodGet, "/reports/tips?from=yesterday", "", nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"from"`)
+}
+
+func TestServer_Options(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer(Config{}, &fakeService{},
+		WithMiddleware(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Test", "middleware")
+				next.ServeHTTP(w, r)
+			})
+		}),
+		WithRoutes(func(r chi.Router) {
+			r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})
+		}),
+	)
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "middleware", rec.Header().Get("X-Test"))
+}
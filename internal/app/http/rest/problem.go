@@ -0,0 +1,152 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/italolelis/coffee-shop/internal/app/order"
+	"github.com/italolelis/coffee-shop/internal/pkg/idempotency"
+	"go.uber.org/zap"
+)
+
+const (
+	problemContentType = "application/problem+json"
+	// retryAfter is the number of seconds clients should wait before retrying a retryable problem
+	retryAfter = "1"
+)
+
+// Problem is an RFC 7807 problem details response. Code is a stable identifier
+// of the problem that clients can rely on, unlike the title and detail.
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Code          string         `json:"code"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	Retryable     bool           `json:"retryable,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid_params,omitempty"`
+}
+
+// InvalidParam describes why a field of the request is invalid
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// knownErrors maps domain errors to their problem, the first match wins. The detail is
+// fixed, so messages of wrapped errors never reach clients.
+var knownErrors = []struct {
+	err    error
+	status int
+	code   string
+	title  string
+	detail string
+}{
+	{order.ErrNotFound, http.StatusNotFound, "order_not_found", "Order not found",
+		"the order doesn't exist"},
+	{order.ErrLineNotFound, http.StatusNotFound, "order_line_not_found", "Order line not found",
+		"the order has no line with this id"},
+	{order.ErrNotPaid, http.StatusNotFound, "payment_not_found", "Payment not found",
+		"the order has no payment"},
+	{order.ErrEmptyOrder, http.StatusConflict, "order_empty", "Order has no items",
+		"add items to the order before checking it out"},
+	{order.ErrNotOpen, http.StatusConflict, "order_not_open", "Order is not open",
+		"only open orders can be changed"},
+	{order.ErrInvalidTransition, http.StatusConflict, "invalid_status_transition", "Invalid order status transition",
+		"the order can't move to this status from its current one"},
+	{order.ErrConflict, http.StatusConflict, "order_conflict", "Order was changed by another request",
+		"fetch the order again and retry"},
+	{order.ErrPaymentUnavailable, http.StatusServiceUnavailable, "payment_unavailable", "Payment service unavailable",
+		"the payment service is temporarily unavailable, retry later"},
+	{order.ErrPaymentServiceFault, http.StatusBadGateway, "payment_service_error", "Payment service error",
+		"the payment service failed to process the request"},
+	{order.ErrPaymentDeclined, http.StatusPaymentRequired, "payment_declined", "Payment declined",
+		"the payment method declined the payment"},
+	{order.ErrPaymentInvalid, http.StatusUnprocessableEntity, "payment_invalid", "Invalid payment",
+		"the payment service rejected the payment request"},
+	{order.ErrPaymentConflict, http.StatusConflict, "payment_conflict", "Payment conflict",
+		"the payment can't be processed in its current state"},
+	{order.ErrPaymentFailed, http.StatusPaymentRequired, "payment_failed", "Payment failed",
+		"the payment didn't go through"},
+	{order.ErrRefundRejected, http.StatusUnprocessableEntity, "refund_rejected", "Refund rejected",
+		"the payment service rejected the refund"},
+	{idempotency.ErrInProgress, http.StatusConflict, "idempotency_key_in_progress", "Request in progress",
+		"a request with this idempotency key is still being processed"},
+	{idempotency.ErrKeyReused, http.StatusUnprocessableEntity, "idempotency_key_reused", "Idempotency key reused",
+		"the idempotency key was already used for a different request"},
+}
+
+func newProblem(status int, code string, title string, detail string) *Problem {
+	return &Problem{
+		Type:   "/problems/" + code,
+		Title:  title,
+		Status: status,
+		Code:   code,
+		Detail: detail,
+	}
+}
+
+// problemFromError maps an error returned by a service to a problem. The message of
+// the error is never exposed, it's only logged by renderError.
+func problemFromError(err error) *Problem {
+	var verrs order.ValidationErrors
+	if errors.As(err, &verrs) {
+		return validationProblem(verrs)
+	}
+
+	var verr *order.ValidationError
+	if errors.As(err, &verr) {
+		return validationProblem(order.ValidationErrors{verr})
+	}
+
+	for _, known := range knownErrors {
+		if errors.Is(err, known.err) {
+			p := newProblem(known.status, known.code, known.title, known.detail)
+
+			var perr *order.PaymentError
+			if errors.As(err, &perr) {
+				p.Retryable = perr.Retryable
+			}
+
+			return p
+		}
+	}
+
+	return newProblem(http.StatusInternalServerError, "internal_error", "Internal server error", "")
+}
+
+func validationProblem(verrs order.ValidationErrors) *Problem {
+	p := newProblem(http.StatusUnprocessableEntity, "validation_failed", "Validation failed", "one or more fields are invalid")
+	for _, verr := range verrs {
+		p.InvalidParams = append(p.InvalidParams, InvalidParam{Name: verr.Field, Reason: verr.Reason})
+	}
+
+	return p
+}
+
+// writeProblem writes the problem as an application/problem+json response
+func writeProblem(w http.ResponseWriter, r *http.Request, p *Problem) {
+	p.Instance = r.URL.Path
+
+	w.Header().Set("Content-Type", problemContentType)
+	if p.Retryable {
+		w.Header().Set("Retry-After", retryAfter)
+	}
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// renderError writes the problem of a service error and logs the error, which is the only
+// place its message ends up
+func renderError(w http.ResponseWriter, r *http.Request, logger *zap.SugaredLogger, msg string, err error) {
+	p := problemFromError(err)
+	if p.Status >= http.StatusInternalServerError {
+		logger.Errorw(msg, "err", err, "code", p.Code)
+	} else {
+		logger.Infow(msg, "err", err, "code", p.Code)
+	}
+
+	writeProblem(w, r, p)
+}
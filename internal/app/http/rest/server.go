@@ -9,8 +9,6 @@ import (
 
 	"github.com/go-chi/chi"
 	"github.com/italolelis/coffee-shop/internal/app/order"
-	"github.com/italolelis/coffee-shop/internal/app/storage/inmem"
-	"github.com/italolelis/coffee-shop/internal/pkg/pb"
 	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
 )
 
@@ -23,16 +21,32 @@ type Config struct {
 
 // Server represents a REST server
 type Server struct {
-	s  *http.Server
-	oh *OrderHandler
+	s           *http.Server
+	oh          *OrderHandler
+	middlewares []func(http.Handler) http.Handler
+	routes      []func(chi.Router)
 }
 
-// NewServer creates a new Server
-func NewServer(cfg Config, pc pb.PaymentClient) *Server {
-	orw := inmem.NewOrderReadWrite()
-	os := order.NewService(orw, orw, pc)
+// Option configures optional dependencies of the Server
+type Option func(*Server)
 
-	return &Server{
+// WithMiddleware adds middlewares to the router, they run after the tracing middleware
+func WithMiddleware(mws ...func(http.Handler) http.Handler) Option {
+	return func(s *Server) {
+		s.middlewares = append(s.middlewares, mws...)
+	}
+}
+
+// WithRoutes allows registering extra routes in the router
+func WithRoutes(fn func(chi.Router)) Option {
+	return func(s *Server) {
+		s.routes = append(s.routes, fn)
+	}
+}
+
+// NewServer creates a new Server that serves the given order service
+func NewServer(cfg Config, os order.Service, opts ...Option) *Server {
+	s := &Server{
 		s: &http.Server{
 			Addr:         cfg.Addr,
 			ReadTimeout:  cfg.ReadTimeout,
@@ -41,20 +55,52 @@ func NewServer(cfg Config, pc pb.PaymentClient) *Server {
 		},
 		oh: &OrderHandler{srv: os},
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.s.Handler = s.router()
+
+	return s
 }
 
-// ListenAndServe opens a port on given address
-// and listens for GRPC connections
-func (s *Server) ListenAndServe(ctx context.Context) error {
+// Handler returns the http.Handler with all the routes of the server
+func (s *Server) Handler() http.Handler {
+	return s.s.Handler
+}
+
+func (s *Server) router() chi.Router {
 	r := chi.NewRouter()
 	r.Use(tracing.Tracing)
+	r.Use(s.middlewares...)
 	r.Route("/orders", func(r chi.Router) {
 		r.Post("/checkout", http.HandlerFunc(s.oh.Checkout))
 		r.Post("/", http.HandlerFunc(s.oh.AddToOrder))
 		r.Get("/{orderID}", http.HandlerFunc(s.oh.GetOrder))
+		r.Get("/{orderID}/receipt", http.HandlerFunc(s.oh.GetReceipt))
+		r.Post("/{orderID}/items", http.HandlerFunc(s.oh.AddToOrder))
+		r.Patch("/{orderID}/items/{lineID}", http.HandlerFunc(s.oh.UpdateItem))
+		r.Delete("/{orderID}/items/{lineID}", http.HandlerFunc(s.oh.RemoveItem))
+		r.Put("/{orderID}/coupon", http.HandlerFunc(s.oh.ApplyCoupon))
+		r.Post("/{orderID}/checkout", http.HandlerFunc(s.oh.Checkout))
+		r.Get("/{orderID}/payments", http.HandlerFunc(s.oh.GetPayments))
+		r.Post("/{orderID}/refund", http.HandlerFunc(s.oh.Refund))
+		r.Put("/{orderID}/status", http.HandlerFunc(s.oh.UpdateStatus))
 	})
+	r.Get("/payment-methods", http.HandlerFunc(s.oh.ListPaymentMethods))
+	r.Get("/reports/tips", http.HandlerFunc(s.oh.TipReport))
+
+	for _, fn := range s.routes {
+		fn(r)
+	}
+
+	return r
+}
 
-	s.s.Handler = r
+// ListenAndServe opens a port on given address
+// and listens for HTTP connections
+func (s *Server) ListenAndServe(ctx context.Context) error {
 	s.s.BaseContext = func(l net.Listener) context.Context {
 		return ctx
 	}
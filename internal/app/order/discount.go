@@ -0,0 +1,143 @@
+package order
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+)
+
+// ErrInvalidCoupon is wrapped by the errors of coupons that can't be used
+var ErrInvalidCoupon = errors.New("invalid coupon")
+
+// Promotions works out the discounts orders are entitled to
+type Promotions interface {
+	// Discounts returns the discounts of the order, including the one of its coupon.
+	// It fails when the coupon of the order can't be used.
+	Discounts(ctx context.Context, o *Order) (Discounts, error)
+	// Redeem counts a use of the coupon, failing when it's expired or used up
+	Redeem(ctx context.Context, code string) error
+	// Release gives back a use of the coupon of a checkout that failed
+	Release(ctx context.Context, code string) error
+}
+
+type (
+	// Discount is money taken off the order by a promotion
+	Discount struct {
+		Promotion string `json:"promotion"`
+		// Code is the coupon that granted the discount, if any
+		Code   string      `json:"code,omitempty"`
+		Amount money.Money `json:"amount"`
+	}
+
+	Discounts []*Discount
+)
+
+// ApplyCoupon sets the coupon of an open order, an empty code removes it. The
+// discount of the coupon is only granted once the discounts are applied.
+func (o *Order) ApplyCoupon(code string) error {
+	if o.Status != StatusOpen {
+		return fmt.Errorf("can't apply a coupon to a %s order: %w", o.Status, ErrNotOpen)
+	}
+
+	o.Coupon = strings.ToUpper(strings.TrimSpace(code))
+
+	return nil
+}
+
+// ApplyDiscounts replaces the discounts of the order. Discounts are capped at the
+// subtotal, so the total is never negative.
+func (o *Order) ApplyDiscounts(discounts Discounts) error {
+	if o.Status != StatusOpen && o.Status != StatusCheckedOut {
+		return fmt.Errorf("can't discount a %s order: %w", o.Status, ErrNotOpen)
+	}
+
+	var (
+		subtotal = o.Subtotal()
+		left     = subtotal.Amount
+		applied  = make(Discounts, 0, len(discounts))
+	)
+
+	for _, d := range discounts {
+		if !d.Amount.SameCurrency(subtotal) {
+			return fmt.Errorf("%w: discount %q is in %s, the order in %s", money.ErrCurrencyMismatch, d.Promotion, d.Amount.Currency, subtotal.Currency)
+		}
+
+		if d.Amount.IsNegative() || d.Amount.IsZero() || left == 0 {
+			continue
+		}
+
+		amount := d.Amount
+		if amount.Amount > left {
+			amount.Amount = left
+		}
+
+		left -= amount.Amount
+		applied = append(applied, &Discount{Promotion: d.Promotion, Code: d.Code, Amount: amount})
+	}
+
+	o.Discounts = applied
+
+	return nil
+}
+
+// Subtotal sums the price of every item in the order, before discounts. AddItem
+// guarantees all items share the same currency, so the sum is exact.
+func (o *Order) Subtotal() money.Money {
+	subtotal := money.New(0, o.Currency())
+	for _, i := range o.Items {
+		subtotal.Amount += i.Subtotal().Amount
+	}
+
+	return subtotal
+}
+
+// DiscountTotal sums the discounts of the order
+func (o *Order) DiscountTotal() money.Money {
+	discount := money.New(0, o.Currency())
+	for _, d := range o.Discounts {
+		discount.Amount += d.Amount.Amount
+	}
+
+	return discount
+}
+
+// coupon checks if the coupon granted one of the discounts
+func (p Discounts) coupon(code string) bool {
+	if code == "" {
+		return false
+	}
+
+	for _, d := range p {
+		if d.Code == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Value return a driver.Value representation of the discounts
+func (p Discounts) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(p)
+}
+
+// Scan scans a database json representation into Discounts
+func (p *Discounts) Scan(src interface{}) error {
+	v := reflect.ValueOf(src)
+	if !v.IsValid() || v.IsNil() {
+		return nil
+	}
+	if data, ok := src.([]byte); ok {
+		return json.Unmarshal(data, &p)
+	}
+	return fmt.Errorf("could not not decode type %T -> %T", src, p)
+}
@@ -0,0 +1,192 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/italolelis/coffee-shop/internal/pkg/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrEmptyOrder = errors.New("order has no items")
+	// ErrPaymentFailed is returned when the payment service didn't accept the payment
+	ErrPaymentFailed = errors.New("payment failed")
+	// ErrPaymentDeclined is a permanent failure, the payment method refused the payment
+	ErrPaymentDeclined = errors.New("payment declined")
+	// ErrPaymentInvalid is a permanent failure, the payment request was rejected as invalid
+	ErrPaymentInvalid = errors.New("invalid payment")
+	// ErrPaymentConflict is a permanent failure, the payment can't be processed in its current
+	// state, e.g. an expired authorization or a closed cash drawer
+	ErrPaymentConflict = errors.New("payment conflict")
+	// ErrPaymentUnavailable is a temporary failure, the checkout can be retried
+	ErrPaymentUnavailable = errors.New("payment service unavailable")
+	// ErrPaymentServiceFault is returned when the payment service failed on its own side,
+	// e.g. an internal error or a misconfigured client, so neither retrying nor a different
+	// payment method helps
+	ErrPaymentServiceFault = errors.New("payment service fault")
+	// ErrRefundRejected is returned when the payment service refuses a refund, e.g. because
+	// it exceeds what is left of the payment
+	ErrRefundRejected = errors.New("refund rejected")
+)
+
+// PaymentError classifies a failed payment so clients know whether retrying makes sense
+type PaymentError struct {
+	// Kind is one of ErrPaymentDeclined, ErrPaymentInvalid, ErrPaymentConflict, ErrPaymentUnavailable,
+	// ErrPaymentServiceFault or ErrPaymentFailed
+	Kind error
+	// Reason explains why the payment failed, e.g. the decline reason
+	Reason    string
+	Retryable bool
+	Err       error
+}
+
+func (e *PaymentError) Error() string {
+	if e.Kind == ErrPaymentFailed {
+		return fmt.Sprintf("%s: %s", ErrPaymentFailed, e.Reason)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", ErrPaymentFailed, e.Kind, e.Reason)
+}
+
+// Is makes every payment error match ErrPaymentFailed besides its own kind
+func (e *PaymentError) Is(target error) bool {
+	return target == ErrPaymentFailed || target == e.Kind
+}
+
+func (e *PaymentError) Unwrap() error {
+	return e.Err
+}
+
+// rejected tells if the payment service definitely refused the payment, so nothing was
+// charged. Payments that failed for any other reason may have gone through.
+func (e *PaymentError) rejected() bool {
+	switch e.Kind {
+	case ErrPaymentDeclined, ErrPaymentInvalid, ErrPaymentConflict:
+		return true
+	default:
+		return false
+	}
+}
+
+// classifyPaymentError turns the gRPC status returned by the payment service into a PaymentError
+func classifyPaymentError(err error) *PaymentError {
+	st, ok := status.FromError(err)
+	if !ok {
+		return &PaymentError{Kind: ErrPaymentServiceFault, Reason: err.Error(), Err: err}
+	}
+
+	perr := &PaymentError{Kind: ErrPaymentFailed, Reason: st.Message(), Err: err}
+
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Aborted, codes.ResourceExhausted:
+		perr.Kind = ErrPaymentUnavailable
+		perr.Retryable = true
+	case codes.Internal, codes.Unknown, codes.Unimplemented, codes.Unauthenticated,
+		codes.PermissionDenied, codes.DataLoss:
+		perr.Kind = ErrPaymentServiceFault
+	case codes.InvalidArgument, codes.AlreadyExists:
+		perr.Kind = ErrPaymentInvalid
+	case codes.FailedPrecondition:
+		// only declines carry a violation, other preconditions are about the state of the payment
+		perr.Kind = ErrPaymentConflict
+		if v := declineViolation(st); v != nil {
+			perr.Kind = ErrPaymentDeclined
+			if v.Description != "" {
+				perr.Reason = v.Description
+			}
+		}
+	}
+
+	return perr
+}
+
+// declineViolation returns the violation of a declined payment, if the status has one
+func declineViolation(st *status.Status) *errdetails.PreconditionFailure_Violation {
+	for _, d := range st.Details() {
+		pf, ok := d.(*errdetails.PreconditionFailure)
+		if !ok {
+			continue
+		}
+
+		for _, v := range pf.Violations {
+			if v.Type == pb.ViolationDeclined {
+				return v
+			}
+		}
+	}
+
+	return nil
+}
+
+// classifyRefundError turns the gRPC status returned by the payment service on refunds into
+// an error. Refunds rejected by the payment service are permanent, temporary failures are
+// reported as an unavailable payment service so they can be retried.
+func classifyRefundError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return fmt.Errorf("failed to refund payment: %w", err)
+	}
+
+	switch st.Code() {
+	case codes.InvalidArgument, codes.FailedPrecondition:
+		return fmt.Errorf("%w: %s", ErrRefundRejected, st.Message())
+	case codes.NotFound:
+		return ErrNotPaid
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Aborted, codes.ResourceExhausted:
+		return classifyPaymentError(err)
+	default:
+		return fmt.Errorf("failed to refund payment: %w", err)
+	}
+}
+
+// ValidationError describes why a field of a command or an item is invalid
+type ValidationError struct {
+	Field  string
+	Reason string
+	// Err is the underlying error, if any
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors groups every invalid field of a command, so they can be reported at once
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+
+	return "validation failed: " + strings.Join(msgs, ", ")
+}
+
+// Is reports whether any of the validation errors matches the target
+func (e ValidationErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// orNil returns nil when there are no validation errors, so it can be returned as an error
+func (e ValidationErrors) orNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
@@ -6,41 +6,136 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
 )
 
+// Status represents a step in the lifecycle of an order
+type Status string
+
+const (
+	StatusOpen       Status = "open"
+	StatusCheckedOut Status = "checked_out"
+	StatusPaid       Status = "paid"
+	StatusPreparing  Status = "preparing"
+	StatusReady      Status = "ready"
+	StatusPickedUp   Status = "picked_up"
+	StatusCancelled  Status = "cancelled"
+	StatusRefunded   Status = "refunded"
+)
+
+var (
+	ErrNotOpen           = errors.New("order is not open")
+	ErrInvalidTransition = errors.New("invalid order status transition")
+	ErrLineNotFound      = errors.New("order line not found")
+)
+
+// MaxQty is the largest quantity of a single order line
+const MaxQty = 99
+
+// legacyLineNamespace derives the IDs of lines stored before lines had IDs
+var legacyLineNamespace = uuid.MustParse("6f0f1b6e-3c1d-4d8e-9a55-2f3c9f1d7e21")
+
+// transitions holds the statuses an order can move to from each status
+var transitions = map[Status][]Status{
+	StatusOpen:       {StatusCheckedOut, StatusCancelled},
+	StatusCheckedOut: {StatusPaid, StatusCancelled},
+	StatusPaid:       {StatusPreparing, StatusRefunded},
+	StatusPreparing:  {StatusReady, StatusRefunded},
+	StatusReady:      {StatusPickedUp, StatusRefunded},
+	StatusPickedUp:   {StatusRefunded},
+	StatusCancelled:  {},
+	StatusRefunded:   {},
+}
+
+// CanTransitionTo checks if an order in this status can move to the given status
+func (s Status) CanTransitionTo(to Status) bool {
+	for _, allowed := range transitions[s] {
+		if allowed == to {
+			return true
+		}
+	}
+
+	return false
+}
+
 type (
 	Order struct {
 		ID           uuid.UUID `json:"id" db:"id"`
 		CreatedAt    time.Time `json:"created_at" db:"created_at"`
 		CustomerName string    `json:"customer" db:"customer"`
+		Status       Status    `json:"status" db:"status"`
 		Items        Items     `json:"items" db:"items"`
+		// PaymentID is the id of the payment confirmation, once the order is paid. For split
+		// payments it's the first tender, PaymentIDs holds all of them.
+		PaymentID  uuid.UUID  `json:"payment_id" db:"payment_id"`
+		PaymentIDs PaymentIDs `json:"payment_ids" db:"payment_ids"`
+		// PaymentCaptured tells if the payment was charged, payments are only authorized
+		// at checkout and captured when the order is picked up
+		PaymentCaptured bool `json:"payment_captured" db:"payment_captured"`
+		// Coupon is the promotion code the customer applied to the order
+		Coupon    string    `json:"coupon,omitempty" db:"coupon"`
+		Discounts Discounts `json:"discounts" db:"discounts"`
+		// Store is where the order is served, e.g. eat-in or take-away, which decides its tax rates
+		Store string `json:"store,omitempty" db:"store"`
+		Taxes Taxes  `json:"taxes" db:"taxes"`
+		// Tip is given on checkout on top of the total
+		Tip *Tip `json:"tip,omitempty" db:"tip"`
+		// Version counts the saves of the order, stores that detect concurrent writes
+		// only save it when it wasn't saved since it was fetched
+		Version int `json:"-" db:"version"`
 	}
 
 	Items []*Item
 
+	// PaymentIDs are the ids of the payment confirmations of an order, one per tender
+	PaymentIDs []uuid.UUID
+
 	Item struct {
-		Name        string  `json:"name" db:"name"`
-		ServingSize string  `json:"serving_size" db:"serving_size"`
-		Price       float64 `json:"price" db:"price"`
-		Qty         int     `json:"qty" db:"qty"`
+		// ID identifies the line in the order, so it can be changed or removed
+		ID          uuid.UUID `json:"id" db:"id"`
+		Name        string    `json:"name" db:"name"`
+		ServingSize string    `json:"serving_size" db:"serving_size"`
+		// Category is the catalog category of the product, which decides its tax rate
+		Category string `json:"category,omitempty" db:"category"`
+		// Price is the price of the serving size, without the modifiers
+		Price     money.Money `json:"price" db:"price"`
+		Qty       int         `json:"qty" db:"qty"`
+		Modifiers Modifiers   `json:"modifiers,omitempty" db:"modifiers"`
+	}
+
+	Modifiers []*Modifier
+
+	// Modifier customizes an item, e.g. with oat milk or an extra shot, for a price
+	// that is added to the price of the item
+	Modifier struct {
+		Name  string      `json:"name" db:"name"`
+		Price money.Money `json:"price" db:"price"`
 	}
 )
 
 func New(customerName string) *Order {
 	return &Order{
-		ID:           uuid.NewSHA1(uuid.NameSpaceOID, []byte(customerName)),
+		ID:           uuid.New(),
 		CreatedAt:    time.Now().UTC(),
 		CustomerName: customerName,
+		Status:       StatusOpen,
 		Items:        make([]*Item, 0),
 	}
 }
 
 func (o *Order) AddItems(items Items) error {
-	for _, i := range items {
+	for idx, i := range items {
 		if err := o.AddItem(i); err != nil {
+			var verr *ValidationError
+			if errors.As(err, &verr) {
+				verr.Field = fmt.Sprintf("items[%d].%s", idx, verr.Field)
+			}
+
 			return err
 		}
 	}
@@ -49,35 +144,337 @@ func (o *Order) AddItems(items Items) error {
 }
 
 func (o *Order) AddItem(i *Item) error {
+	if o.Status != StatusOpen {
+		return fmt.Errorf("can't add items to a %s order: %w", o.Status, ErrNotOpen)
+	}
+
 	if i.Name == "" {
-		return errors.New("item name can't be empty")
+		return &ValidationError{Field: "name", Reason: "can't be empty"}
 	}
 
 	if i.ServingSize == "" {
-		return errors.New("serving size can't be empty")
+		return &ValidationError{Field: "serving_size", Reason: "can't be empty"}
+	}
+
+	if err := validateQty("qty", i.Qty); err != nil {
+		return err
+	}
+
+	if i.Price.IsNegative() {
+		return &ValidationError{Field: "price", Reason: "can't be negative"}
+	}
+
+	if len(o.Items) > 0 && !o.Items[0].Price.SameCurrency(i.Price) {
+		return &ValidationError{
+			Field:  "price",
+			Reason: fmt.Sprintf("can't add an item in %s to an order in %s", i.Price.Currency, o.Items[0].Price.Currency),
+			Err:    money.ErrCurrencyMismatch,
+		}
+	}
+
+	for idx, m := range i.Modifiers {
+		if err := m.validate(i.Price.Currency); err != nil {
+			err.Field = fmt.Sprintf("modifiers[%d].%s", idx, err.Field)
+			return err
+		}
 	}
 
 	for _, existingItem := range o.Items {
-		if existingItem.Name == i.Name && existingItem.ServingSize == i.ServingSize {
+		if existingItem.sameLine(i) {
+			if err := validateQty("qty", existingItem.Qty+i.Qty); err != nil {
+				return err
+			}
+
 			existingItem.Qty += i.Qty
 			return nil
 		}
 	}
 
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+
 	o.Items = append(o.Items, i)
 
 	return nil
 }
 
-func (o *Order) Total() float64 {
-	var total float64
-	for _, i := range o.Items {
-		total += i.Price * float64(i.Qty)
+// RemoveItem removes the line with the given ID from the order
+func (o *Order) RemoveItem(lineID uuid.UUID) error {
+	if o.Status != StatusOpen {
+		return fmt.Errorf("can't remove items from a %s order: %w", o.Status, ErrNotOpen)
+	}
+
+	idx, err := o.line(lineID)
+	if err != nil {
+		return err
 	}
 
+	o.Items = append(o.Items[:idx], o.Items[idx+1:]...)
+
+	return nil
+}
+
+// UpdateItemQty replaces the quantity of the line with the given ID. Lines are
+// removed with RemoveItem, so the quantity must be positive.
+func (o *Order) UpdateItemQty(lineID uuid.UUID, qty int) error {
+	if o.Status != StatusOpen {
+		return fmt.Errorf("can't change items of a %s order: %w", o.Status, ErrNotOpen)
+	}
+
+	if err := validateQty("qty", qty); err != nil {
+		return err
+	}
+
+	idx, err := o.line(lineID)
+	if err != nil {
+		return err
+	}
+
+	o.Items[idx].Qty = qty
+
+	return nil
+}
+
+// line returns the position of the line with the given ID
+func (o *Order) line(id uuid.UUID) (int, error) {
+	for idx, i := range o.Items {
+		if i.ID == id {
+			return idx, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %s", ErrLineNotFound, id)
+}
+
+// validateQty checks that a line quantity is positive and within MaxQty
+func validateQty(field string, qty int) *ValidationError {
+	switch {
+	case qty <= 0:
+		return &ValidationError{Field: field, Reason: "must be positive"}
+	case qty > MaxQty:
+		return &ValidationError{Field: field, Reason: fmt.Sprintf("can't be more than %d", MaxQty)}
+	}
+
+	return nil
+}
+
+// Checkout closes the order for changes so it can be paid
+func (o *Order) Checkout() error {
+	if len(o.Items) == 0 {
+		return ErrEmptyOrder
+	}
+
+	return o.transition(StatusCheckedOut)
+}
+
+// MarkPaid registers that the order was paid with the given payment confirmations,
+// one for each tender when the payment was split
+func (o *Order) MarkPaid(paymentIDs ...uuid.UUID) error {
+	if len(paymentIDs) == 0 {
+		return ErrNotPaid
+	}
+
+	if err := o.transition(StatusPaid); err != nil {
+		return err
+	}
+
+	o.PaymentID = paymentIDs[0]
+	o.PaymentIDs = paymentIDs
+
+	return nil
+}
+
+// Payments returns the ids of every payment confirmation of the order. Orders paid
+// before split payments existed only have a PaymentID.
+func (o *Order) Payments() []uuid.UUID {
+	if len(o.PaymentIDs) > 0 {
+		return o.PaymentIDs
+	}
+
+	if o.PaymentID != uuid.Nil {
+		return []uuid.UUID{o.PaymentID}
+	}
+
+	return nil
+}
+
+// MarkCaptured registers that the authorized payment of the order was charged
+func (o *Order) MarkCaptured() error {
+	if o.PaymentID == uuid.Nil {
+		return ErrNotPaid
+	}
+
+	o.PaymentCaptured = true
+
+	return nil
+}
+
+// StartPreparing registers that the barista started preparing the order
+func (o *Order) StartPreparing() error {
+	return o.transition(StatusPreparing)
+}
+
+// MarkReady registers that the order is ready to be picked up
+func (o *Order) MarkReady() error {
+	return o.transition(StatusReady)
+}
+
+// PickUp registers that the customer picked up the order
+func (o *Order) PickUp() error {
+	return o.transition(StatusPickedUp)
+}
+
+// Cancel cancels an order that wasn't paid yet
+func (o *Order) Cancel() error {
+	return o.transition(StatusCancelled)
+}
+
+// Refund registers that the payment of the order was given back to the customer
+func (o *Order) Refund() error {
+	return o.transition(StatusRefunded)
+}
+
+func (o *Order) transition(to Status) error {
+	if !o.Status.CanTransitionTo(to) {
+		return fmt.Errorf("%w: from %s to %s", ErrInvalidTransition, o.Status, to)
+	}
+
+	o.Status = to
+
+	return nil
+}
+
+// Currency returns the currency of the order, which is defined by its items
+func (o *Order) Currency() string {
+	if len(o.Items) == 0 {
+		return ""
+	}
+
+	return o.Items[0].Price.Currency
+}
+
+// Total is what the customer pays for the order, the subtotal minus the discounts
+// plus the taxes that aren't included in the prices
+func (o *Order) Total() money.Money {
+	total := o.Subtotal()
+	total.Amount -= o.DiscountTotal().Amount
+	if total.Amount < 0 {
+		total.Amount = 0
+	}
+
+	total.Amount += o.TaxTotal().Amount
+
 	return total
 }
 
+// MarshalJSON adds the breakdown of the total to the order
+func (o Order) MarshalJSON() ([]byte, error) {
+	type plainOrder Order
+
+	return json.Marshal(struct {
+		plainOrder
+		Subtotal    money.Money `json:"subtotal"`
+		Discount    money.Money `json:"discount"`
+		Tax         money.Money `json:"tax"`
+		TaxIncluded money.Money `json:"tax_included"`
+		Total       money.Money `json:"total"`
+	}{
+		plainOrder:  plainOrder(o),
+		Subtotal:    o.Subtotal(),
+		Discount:    o.DiscountTotal(),
+		Tax:         o.TaxTotal(),
+		TaxIncluded: o.TaxIncluded(),
+		Total:       o.Total(),
+	})
+}
+
+// UnitPrice returns the price of a single item, including its modifiers
+func (i *Item) UnitPrice() money.Money {
+	price := i.Price
+	for _, m := range i.Modifiers {
+		price.Amount += m.Price.Amount
+	}
+
+	return price
+}
+
+// Subtotal returns the unit price of the item multiplied by its quantity
+func (i *Item) Subtotal() money.Money {
+	return i.UnitPrice().Mul(int64(i.Qty))
+}
+
+// sameLine checks if both items are the same product, size and customization, in
+// which case they are merged into a single line. The order the modifiers were
+// chosen in doesn't matter.
+func (i *Item) sameLine(other *Item) bool {
+	if i.Name != other.Name || i.ServingSize != other.ServingSize || len(i.Modifiers) != len(other.Modifiers) {
+		return false
+	}
+
+	names, otherNames := i.Modifiers.names(), other.Modifiers.names()
+	for idx := range names {
+		if names[idx] != otherNames[idx] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// names returns the sorted names of the modifiers
+func (m Modifiers) names() []string {
+	names := make([]string, 0, len(m))
+	for _, modifier := range m {
+		names = append(names, modifier.Name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func (m *Modifier) validate(currency string) *ValidationError {
+	if m.Name == "" {
+		return &ValidationError{Field: "name", Reason: "can't be empty"}
+	}
+
+	if m.Price.IsNegative() {
+		return &ValidationError{Field: "price", Reason: "can't be negative"}
+	}
+
+	if m.Price.Currency != currency {
+		return &ValidationError{
+			Field:  "price",
+			Reason: fmt.Sprintf("must be in %s like the item, got %s", currency, m.Price.Currency),
+			Err:    money.ErrCurrencyMismatch,
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalJSON decodes the items, deriving an ID for lines stored before lines had
+// IDs. The ID only depends on the position and content of the line, so it's the same
+// every time the order is read until the order is saved again with the IDs.
+func (p *Items) UnmarshalJSON(data []byte) error {
+	var items []*Item
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	for idx, i := range items {
+		if i != nil && i.ID == uuid.Nil {
+			key := fmt.Sprintf("%d/%s/%s/%s", idx, i.Name, i.ServingSize, strings.Join(i.Modifiers.names(), ","))
+			i.ID = uuid.NewSHA1(legacyLineNamespace, []byte(key))
+		}
+	}
+
+	*p = items
+
+	return nil
+}
+
 // Value return a driver.Value representation of the order items
 func (p Items) Value() (driver.Value, error) {
 	if len(p) == 0 {
@@ -97,3 +494,23 @@ func (p *Items) Scan(src interface{}) error {
 	}
 	return fmt.Errorf("could not not decode type %T -> %T", src, p)
 }
+
+// Value return a driver.Value representation of the payment ids
+func (p PaymentIDs) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(p)
+}
+
+// Scan scans a database json representation into a []uuid.UUID
+func (p *PaymentIDs) Scan(src interface{}) error {
+	v := reflect.ValueOf(src)
+	if !v.IsValid() || v.IsNil() {
+		return nil
+	}
+	if data, ok := src.([]byte); ok {
+		return json.Unmarshal(data, &p)
+	}
+	return fmt.Errorf("could not not decode type %T -> %T", src, p)
+}
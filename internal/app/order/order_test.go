@@ -1,9 +1,13 @@
 package order
 
 import (
-	"math"
+	"errors"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -17,20 +21,20 @@ func TestOrder_AddItems(t *testing.T) {
 		items           Items
 		errorExpected   bool
 		expectedItemQty int
-		expectedTotal   float64
+		expectedTotal   money.Money
 	}{
 		{
 			name:            "add single item empty name",
 			customerName:    "test",
 			errorExpected:   true,
 			expectedItemQty: 0,
-			expectedTotal:   0,
+			expectedTotal:   money.Money{},
 			items: Items{
 				{
 					Name:        "",
 					Qty:         2,
 					ServingSize: "L",
-					Price:       2.60,
+					Price:       money.New(260, "EUR"),
 				},
 			},
 		},
@@ -39,13 +43,13 @@ func TestOrder_AddItems(t *testing.T) {
 			customerName:    "test",
 			errorExpected:   true,
 			expectedItemQty: 0,
-			expectedTotal:   0,
+			expectedTotal:   money.Money{},
 			items: Items{
 				{
 					Name:        "latte",
 					Qty:         2,
 					ServingSize: "",
-					Price:       2.60,
+					Price:       money.New(260, "EUR"),
 				},
 			},
 		},
@@ -54,19 +58,90 @@ func TestOrder_AddItems(t *testing.T) {
 			customerName:    "test",
 			errorExpected:   false,
 			expectedItemQty: 1,
-			expectedTotal:   7.80,
+			expectedTotal:   money.New(780, "EUR"),
 			items: Items{
 				{
 					Name:        "cappuccino",
 					Qty:         2,
 					ServingSize: "L",
-					Price:       2.60,
+					Price:       money.New(260, "EUR"),
 				},
 				{
 					Name:        "cappuccino",
 					Qty:         1,
 					ServingSize: "L",
-					Price:       2.60,
+					Price:       money.New(260, "EUR"),
+				},
+			},
+		},
+		{
+			name:          "add item with negative qty",
+			customerName:  "test",
+			errorExpected: true,
+			expectedTotal: money.Money{},
+			items: Items{
+				{Name: "latte", Qty: -1, ServingSize: "M", Price: money.New(260, "EUR")},
+			},
+		},
+		{
+			name:            "add same item with different modifiers",
+			customerName:    "test",
+			expectedItemQty: 2,
+			expectedTotal:   money.New(560, "EUR"),
+			items: Items{
+				{Name: "latte", Qty: 1, ServingSize: "M", Price: money.New(260, "EUR")},
+				{
+					Name: "latte", Qty: 1, ServingSize: "M", Price: money.New(260, "EUR"),
+					Modifiers: Modifiers{{Name: "oat milk", Price: money.New(40, "EUR")}},
+				},
+			},
+		},
+		{
+			name:            "add same modifiers in another order",
+			customerName:    "test",
+			expectedItemQty: 1,
+			expectedTotal:   money.New(700, "EUR"),
+			items: Items{
+				{
+					Name: "latte", Qty: 1, ServingSize: "M", Price: money.New(260, "EUR"),
+					Modifiers: Modifiers{{Name: "oat milk", Price: money.New(40, "EUR")}, {Name: "extra shot", Price: money.New(50, "EUR")}},
+				},
+				{
+					Name: "latte", Qty: 1, ServingSize: "M", Price: money.New(260, "EUR"),
+					Modifiers: Modifiers{{Name: "extra shot", Price: money.New(50, "EUR")}, {Name: "oat milk", Price: money.New(40, "EUR")}},
+				},
+			},
+		},
+		{
+			name:          "add modifier in another currency",
+			customerName:  "test",
+			errorExpected: true,
+			expectedTotal: money.Money{},
+			items: Items{
+				{
+					Name: "latte", Qty: 1, ServingSize: "M", Price: money.New(260, "EUR"),
+					Modifiers: Modifiers{{Name: "oat milk", Price: money.New(40, "USD")}},
+				},
+			},
+		},
+		{
+			name:            "add items in different currencies",
+			customerName:    "test",
+			errorExpected:   true,
+			expectedItemQty: 1,
+			expectedTotal:   money.New(260, "EUR"),
+			items: Items{
+				{
+					Name:        "latte",
+					Qty:         1,
+					ServingSize: "M",
+					Price:       money.New(260, "EUR"),
+				},
+				{
+					Name:        "espresso",
+					Qty:         1,
+					ServingSize: "S",
+					Price:       money.New(180, "USD"),
 				},
 			},
 		},
@@ -83,7 +158,286 @@ func TestOrder_AddItems(t *testing.T) {
 			}
 
 			assert.Equal(t, tt.expectedItemQty, len(o.Items))
-			assert.Equal(t, tt.expectedTotal, math.Floor(o.Total()*100)/100)
+			assert.Equal(t, tt.expectedTotal, o.Total())
 		})
 	}
 }
+
+func TestItems_ValueScan(t *testing.T) {
+	t.Parallel()
+
+	items := Items{{
+		ID: uuid.New(), Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 2,
+		Modifiers: Modifiers{{Name: "oat milk", Price: money.New(40, "EUR")}},
+	}}
+
+	v, err := items.Value()
+	require.NoError(t, err)
+
+	var scanned Items
+	require.NoError(t, scanned.Scan(v))
+	assert.Equal(t, items, scanned)
+
+	// lines stored before lines had IDs get the same ID every time they're read
+	legacy := []byte(`[{"name": "latte", "serving_size": "M", "price": {"amount": "2.60", "currency": "EUR"}, "qty": 1}]`)
+
+	var first, second Items
+	require.NoError(t, first.Scan(legacy))
+	require.NoError(t, second.Scan(legacy))
+	assert.NotEqual(t, uuid.Nil, first[0].ID)
+	assert.Equal(t, first[0].ID, second[0].ID)
+}
+
+func TestOrder_ChangeItems(t *testing.T) {
+	t.Parallel()
+
+	o := New("anna")
+	require.NoError(t, o.AddItems(Items{
+		{Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 2},
+		{Name: "espresso", ServingSize: "S", Price: money.New(180, "EUR"), Qty: 1},
+	}))
+	latte, espresso := o.Items[0].ID, o.Items[1].ID
+	require.NotEqual(t, latte, espresso)
+
+	require.NoError(t, o.UpdateItemQty(latte, 1))
+	assert.Equal(t, money.New(440, "EUR"), o.Total())
+
+	var verr *ValidationError
+	assert.True(t, errors.As(o.UpdateItemQty(latte, 0), &verr))
+	assert.True(t, errors.As(o.UpdateItemQty(latte, MaxQty+1), &verr))
+	assert.True(t, errors.Is(o.UpdateItemQty(uuid.New(), 1), ErrLineNotFound))
+
+	// merging can't go over the maximum quantity either
+	err := o.AddItem(&Item{Name: "espresso", ServingSize: "S", Price: money.New(180, "EUR"), Qty: MaxQty})
+	assert.True(t, errors.As(err, &verr))
+	assert.Equal(t, 1, o.Items[1].Qty)
+
+	require.NoError(t, o.RemoveItem(espresso))
+	require.Len(t, o.Items, 1)
+	assert.Equal(t, latte, o.Items[0].ID)
+	assert.True(t, errors.Is(o.RemoveItem(espresso), ErrLineNotFound))
+
+	require.NoError(t, o.Checkout())
+	assert.True(t, errors.Is(o.RemoveItem(latte), ErrNotOpen))
+	assert.True(t, errors.Is(o.UpdateItemQty(latte, 2), ErrNotOpen))
+}
+
+func TestOrder_Receipt(t *testing.T) {
+	t.Parallel()
+
+	o := New("anna")
+	require.NoError(t, o.AddItems(Items{
+		{
+			Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 2,
+			Modifiers: Modifiers{{Name: "oat milk", Price: money.New(40, "EUR")}, {Name: "extra shot", Price: money.New(50, "EUR")}},
+		},
+		{Name: "espresso", ServingSize: "S", Price: money.New(180, "EUR"), Qty: 1},
+	}))
+
+	receipt := o.Receipt()
+	assert.Contains(t, receipt, "Customer anna\n")
+	assert.Contains(t, receipt, "2 x latte (M)                   5.20 EUR\n")
+	assert.Contains(t, receipt, "    oat milk                    0.80 EUR\n")
+	assert.Contains(t, receipt, "    extra shot                  1.00 EUR\n")
+	assert.Contains(t, receipt, "1 x espresso (S)                1.80 EUR\n")
+	assert.Contains(t, receipt, "Total                           8.80 EUR\n")
+	assert.NotContains(t, receipt, "Subtotal")
+
+	require.NoError(t, o.ApplyDiscounts(Discounts{{Promotion: "happy hour", Amount: money.New(132, "EUR")}}))
+
+	receipt = o.Receipt()
+	assert.Contains(t, receipt, "Subtotal                        8.80 EUR\n")
+	assert.Contains(t, receipt, "happy hour                     -1.32 EUR\n")
+	assert.Contains(t, receipt, "Total                           7.48 EUR\n")
+}
+
+func TestOrder_ReceiptMultibyte(t *testing.T) {
+	t.Parallel()
+
+	o := New("anna")
+	require.NoError(t, o.AddItems(Items{
+		{Name: "Café crème", ServingSize: "M", Price: money.New(320, "EUR"), Qty: 1},
+		{Name: "Crème brûlée latte with a touch of crème", ServingSize: "L", Price: money.New(450, "EUR"), Qty: 1},
+	}))
+
+	receipt := o.Receipt()
+	assert.Contains(t, receipt, "1 x Café crème (M)              3.20 EUR\n")
+	assert.Contains(t, receipt, "1 x Crème brûlée latte with a t 4.50 EUR\n")
+
+	for _, line := range strings.Split(strings.TrimSuffix(receipt, "\n"), "\n")[3:] {
+		assert.True(t, utf8.ValidString(line), line)
+		assert.Equal(t, receiptWidth, utf8.RuneCountInString(line), line)
+	}
+}
+
+func TestOrder_ApplyDiscounts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		discounts         Discounts
+		expectedErr       error
+		expectedDiscounts int
+		expectedTotal     money.Money
+	}{
+		{
+			name:          "no discounts",
+			expectedTotal: money.New(520, "EUR"),
+		},
+		{
+			name: "several discounts",
+			discounts: Discounts{
+				{Promotion: "happy hour", Amount: money.New(78, "EUR")},
+				{Promotion: "welcome", Code: "WELCOME10", Amount: money.New(52, "EUR")},
+			},
+			expectedDiscounts: 2,
+			expectedTotal:     money.New(390, "EUR"),
+		},
+		{
+			name: "capped at the subtotal",
+			discounts: Discounts{
+				{Promotion: "free latte", Amount: money.New(260, "EUR")},
+				{Promotion: "five off", Amount: money.New(500, "EUR")},
+				{Promotion: "welcome", Amount: money.New(52, "EUR")},
+			},
+			expectedDiscounts: 2,
+			expectedTotal:     money.New(0, "EUR"),
+		},
+		{
+			name:          "zero discount skipped",
+			discounts:     Discounts{{Promotion: "happy hour", Amount: money.New(0, "EUR")}},
+			expectedTotal: money.New(520, "EUR"),
+		},
+		{
+			name:        "other currency",
+			discounts:   Discounts{{Promotion: "happy hour", Amount: money.New(78, "USD")}},
+			expectedErr: money.ErrCurrencyMismatch,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			o := New("anna")
+			require.NoError(t, o.AddItem(&Item{Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 2}))
+
+			err := o.ApplyDiscounts(tt.discounts)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, o.Discounts, tt.expectedDiscounts)
+			assert.Equal(t, money.New(520, "EUR"), o.Subtotal())
+			assert.Equal(t, tt.expectedTotal, o.Total())
+		})
+	}
+}
+
+func TestOrder_ApplyTaxes(t *testing.T) {
+	t.Parallel()
+
+	o := New("anna")
+	require.NoError(t, o.AddItem(&Item{Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 2}))
+
+	require.NoError(t, o.ApplyTaxes(Taxes{{Name: "Tax 19%", Rate: 1900, Base: money.New(520, "EUR"), Amount: money.New(99, "EUR")}}))
+	assert.Equal(t, money.New(99, "EUR"), o.TaxTotal())
+	assert.Equal(t, money.New(0, "EUR"), o.TaxIncluded())
+	assert.Equal(t, money.New(619, "EUR"), o.Total())
+
+	receipt := o.Receipt()
+	assert.Contains(t, receipt, "Subtotal                        5.20 EUR\n")
+	assert.Contains(t, receipt, "Tax 19%                         0.99 EUR\n")
+	assert.Contains(t, receipt, "Total                           6.19 EUR\n")
+
+	require.NoError(t, o.ApplyTaxes(Taxes{{Name: "Tax 19%", Rate: 1900, Inclusive: true, Base: money.New(520, "EUR"), Amount: money.New(83, "EUR")}}))
+	assert.Equal(t, money.New(0, "EUR"), o.TaxTotal())
+	assert.Equal(t, money.New(83, "EUR"), o.TaxIncluded())
+	assert.Equal(t, money.New(520, "EUR"), o.Total())
+
+	receipt = o.Receipt()
+	assert.NotContains(t, receipt, "Subtotal")
+	assert.Contains(t, receipt, "Total                           5.20 EUR\nincl. Tax 19%                   0.83 EUR\n")
+
+	err := o.ApplyTaxes(Taxes{{Name: "Tax 19%", Rate: 1900, Base: money.New(520, "USD"), Amount: money.New(99, "USD")}})
+	assert.True(t, errors.Is(err, money.ErrCurrencyMismatch))
+}
+
+func TestOrder_AddTip(t *testing.T) {
+	t.Parallel()
+
+	o := New("anna")
+	require.NoError(t, o.AddItem(&Item{Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 2}))
+	require.NoError(t, o.Checkout())
+	assert.Equal(t, money.New(0, "EUR"), o.TipAmount())
+
+	err := o.AddTip(&Tip{Amount: money.New(50, "USD")})
+	assert.True(t, errors.Is(err, money.ErrCurrencyMismatch))
+
+	require.NoError(t, o.AddTip(&Tip{Amount: money.New(80, "EUR"), Barista: "ben"}))
+	assert.Equal(t, money.New(520, "EUR"), o.Total())
+	assert.Equal(t, money.New(600, "EUR"), o.TotalWithTip())
+
+	receipt := o.Receipt()
+	assert.Contains(t, receipt, "Total                           5.20 EUR\n")
+	assert.Contains(t, receipt, "Tip for ben                     0.80 EUR\nPaid                            6.00 EUR\n")
+
+	data, err := o.Tip.Value()
+	require.NoError(t, err)
+
+	var scanned Tip
+	require.NoError(t, scanned.Scan(data))
+	assert.Equal(t, *o.Tip, scanned)
+
+	require.NoError(t, o.AddTip(nil))
+	assert.Equal(t, o.Total(), o.TotalWithTip())
+	assert.NotContains(t, o.Receipt(), "Tip")
+
+	require.NoError(t, o.MarkPaid(uuid.New()))
+	assert.True(t, errors.Is(o.AddTip(&Tip{Amount: money.New(80, "EUR")}), ErrNotOpen))
+}
+
+func TestOrder_ApplyCoupon(t *testing.T) {
+	t.Parallel()
+
+	o := New("anna")
+	require.NoError(t, o.AddItem(&Item{Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 1}))
+
+	require.NoError(t, o.ApplyCoupon(" welcome10 "))
+	assert.Equal(t, "WELCOME10", o.Coupon)
+
+	require.NoError(t, o.ApplyCoupon(""))
+	assert.Empty(t, o.Coupon)
+
+	require.NoError(t, o.Checkout())
+	assert.True(t, errors.Is(o.ApplyCoupon("WELCOME10"), ErrNotOpen))
+}
+
+func TestOrder_Transitions(t *testing.T) {
+	t.Parallel()
+
+	o := New("test")
+	require.NoError(t, o.AddItem(&Item{Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 1}))
+
+	require.NoError(t, o.Checkout())
+	assert.Equal(t, StatusCheckedOut, o.Status)
+
+	err := o.Checkout()
+	assert.True(t, errors.Is(err, ErrInvalidTransition))
+
+	err = o.AddItem(&Item{Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 1})
+	assert.True(t, errors.Is(err, ErrNotOpen))
+	assert.False(t, o.Status.CanTransitionTo(StatusOpen))
+
+	require.NoError(t, o.MarkPaid(uuid.New()))
+	require.NoError(t, o.StartPreparing())
+	require.NoError(t, o.MarkReady())
+	require.NoError(t, o.PickUp())
+	assert.Equal(t, StatusPickedUp, o.Status)
+
+	err = o.Cancel()
+	assert.True(t, errors.Is(err, ErrInvalidTransition))
+	assert.Equal(t, StatusPickedUp, o.Status)
+}
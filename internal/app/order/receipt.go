@@ -0,0 +1,76 @@
+package order
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+)
+
+// receiptWidth is the number of characters of a receipt line, which fits narrow receipt printers
+const receiptWidth = 40
+
+// Receipt renders the order as plain text for printing, with every amount of a
+// line priced for its whole quantity
+func (o *Order) Receipt() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Order %s\n", o.ID)
+	fmt.Fprintf(&b, "Customer %s\n", o.CustomerName)
+	fmt.Fprintf(&b, "%s\n", o.CreatedAt.Format("2006-01-02 15:04"))
+	b.WriteString(strings.Repeat("-", receiptWidth) + "\n")
+
+	for _, i := range o.Items {
+		receiptLine(&b, fmt.Sprintf("%d x %s (%s)", i.Qty, i.Name, i.ServingSize), i.Price.Mul(int64(i.Qty)))
+		for _, m := range i.Modifiers {
+			receiptLine(&b, "    "+m.Name, m.Price.Mul(int64(i.Qty)))
+		}
+	}
+
+	b.WriteString(strings.Repeat("-", receiptWidth) + "\n")
+	if len(o.Discounts) > 0 || !o.TaxTotal().IsZero() {
+		receiptLine(&b, "Subtotal", o.Subtotal())
+		for _, d := range o.Discounts {
+			receiptLine(&b, d.Promotion, d.Amount.Mul(-1))
+		}
+
+		for _, t := range o.Taxes {
+			if !t.Inclusive {
+				receiptLine(&b, t.Name, t.Amount)
+			}
+		}
+	}
+
+	receiptLine(&b, "Total", o.Total())
+	for _, t := range o.Taxes {
+		if t.Inclusive {
+			receiptLine(&b, "incl. "+t.Name, t.Amount)
+		}
+	}
+
+	if o.Tip != nil {
+		label := "Tip"
+		if o.Tip.Barista != "" {
+			label += " for " + o.Tip.Barista
+		}
+
+		receiptLine(&b, label, o.Tip.Amount)
+		receiptLine(&b, "Paid", o.TotalWithTip())
+	}
+
+	return b.String()
+}
+
+// receiptLine writes the label left aligned and the amount right aligned. Labels are
+// cut and padded by character, so names with accents aren't split mid-character.
+func receiptLine(b *strings.Builder, label string, amount money.Money) {
+	value := amount.String()
+
+	width := receiptWidth - utf8.RuneCountInString(value) - 1
+	if runes := []rune(label); len(runes) > width {
+		label = string(runes[:width])
+	}
+
+	b.WriteString(label + strings.Repeat(" ", width-utf8.RuneCountInString(label)) + " " + value + "\n")
+}
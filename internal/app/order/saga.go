@@ -0,0 +1,281 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/pkg/log"
+	"github.com/italolelis/coffee-shop/internal/pkg/pb"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SagaStep is the last step reached by a checkout saga
+type SagaStep string
+
+const (
+	// SagaStarted means the payment was requested but its outcome is not known yet
+	SagaStarted SagaStep = "started"
+	// SagaPaid means the payment was authorized but the order wasn't saved yet
+	SagaPaid SagaStep = "paid"
+	// SagaCompleted means the order was saved as paid
+	SagaCompleted SagaStep = "completed"
+	// SagaCompensating means the checkout failed after the payment and the payment must be voided
+	SagaCompensating SagaStep = "compensating"
+	// SagaCompensated means the payment of a failed checkout was voided
+	SagaCompensated SagaStep = "compensated"
+	// SagaAborted means the payment failed, so there is nothing to compensate
+	SagaAborted SagaStep = "aborted"
+)
+
+// Done checks if the saga reached a final step
+func (s SagaStep) Done() bool {
+	return s == SagaCompleted || s == SagaCompensated || s == SagaAborted
+}
+
+// CheckoutSaga records the progress of a checkout, which spans the payment service
+// and the order storage. It's used to compensate payments of checkouts that failed
+// halfway, even if the process crashed in between.
+type CheckoutSaga struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	OrderID   uuid.UUID `json:"order_id" db:"order_id"`
+	PaymentID uuid.UUID `json:"payment_id" db:"payment_id"`
+	Step      SagaStep  `json:"step" db:"step"`
+	// Reason explains why the saga is being compensated
+	Reason    string    `json:"reason" db:"reason"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// NewCheckoutSaga starts a saga for the checkout of the given order
+func NewCheckoutSaga(orderID uuid.UUID) *CheckoutSaga {
+	now := time.Now().UTC()
+
+	return &CheckoutSaga{
+		ID:        uuid.New(),
+		OrderID:   orderID,
+		Step:      SagaStarted,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+type SagaReader interface {
+	// FetchIncomplete returns the sagas that didn't reach a final step and weren't
+	// updated since the given time
+	FetchIncomplete(ctx context.Context, updatedBefore time.Time) ([]*CheckoutSaga, error)
+}
+
+type SagaWriter interface {
+	Add(context.Context, *CheckoutSaga) error
+}
+
+// CheckoutOrchestrator runs the checkout saga: it authorizes the payment, saves the paid
+// order and, when saving fails, voids the payment so nobody pays for an order that
+// was never recorded. The payment is captured later, when the order is picked up.
+type CheckoutOrchestrator struct {
+	w  Writer
+	r  Reader
+	pc pb.PaymentClient
+	sr SagaReader
+	sw SagaWriter
+}
+
+func NewCheckoutOrchestrator(w Writer, r Reader, pc pb.PaymentClient, sr SagaReader, sw SagaWriter) *CheckoutOrchestrator {
+	return &CheckoutOrchestrator{
+		w:  w,
+		r:  r,
+		pc: pc,
+		sr: sr,
+		sw: sw,
+	}
+}
+
+// Run authorizes the payment of the checked out order and saves it. Every saga is a
+// single payment attempt, so its ID is used as the idempotency key of the payment.
+func (c *CheckoutOrchestrator) Run(ctx context.Context, o *Order, req *pb.PaymentRequest) error {
+	ctx, span := tracing.Start(ctx, "service/order/checkout-saga")
+	defer span.End()
+
+	saga := NewCheckoutSaga(o.ID)
+	if err := c.sw.Add(ctx, saga); err != nil {
+		return fmt.Errorf("failed to start checkout saga: %w", err)
+	}
+
+	req.IdempotencyKey = saga.ID.String()
+
+	payments, err := c.authorize(ctx, req)
+	if err != nil {
+		perr := classifyPaymentError(err)
+		if perr.rejected() {
+			c.step(ctx, saga, SagaAborted)
+			return perr
+		}
+
+		// the payment service may have authorized the payment before failing
+		if cerr := c.compensate(ctx, saga, perr.Reason); cerr != nil {
+			span.RecordError(ctx, cerr)
+		}
+
+		return perr
+	}
+
+	paymentIDs, err := parsePaymentIDs(payments)
+	if err != nil {
+		return err
+	}
+
+	// split payments are compensated by order id, which voids every tender
+	if len(paymentIDs) == 1 {
+		saga.PaymentID = paymentIDs[0]
+	}
+	c.step(ctx, saga, SagaPaid)
+
+	if err := c.complete(ctx, o, paymentIDs); err != nil {
+		if cerr := c.compensate(ctx, saga, err.Error()); cerr != nil {
+			span.RecordError(ctx, cerr)
+		}
+
+		return err
+	}
+
+	c.step(ctx, saga, SagaCompleted)
+
+	return nil
+}
+
+// Resume drives the sagas interrupted by a crash to a final step. Sagas whose order
+// was saved are completed, the payments of all others are voided. Only sagas that
+// weren't updated for staleAfter are resumed, the others may still be running in
+// another process, so staleAfter must be longer than a checkout can take.
+func (c *CheckoutOrchestrator) Resume(ctx context.Context, staleAfter time.Duration) error {
+	ctx, span := tracing.Start(ctx, "service/order/resume-checkout-sagas")
+	defer span.End()
+
+	logger := log.WithContext(ctx).Named("orders").With("action", "resume_checkout")
+
+	sagas, err := c.sr.FetchIncomplete(ctx, time.Now().UTC().Add(-staleAfter))
+	if err != nil {
+		return fmt.Errorf("failed to fetch incomplete checkout sagas: %w", err)
+	}
+
+	for _, saga := range sagas {
+		if err := c.resume(ctx, saga); err != nil {
+			logger.Errorw("failed to resume checkout saga", "saga_id", saga.ID, "order_id", saga.OrderID, "err", err)
+			continue
+		}
+
+		logger.Infow("checkout saga resumed", "saga_id", saga.ID, "order_id", saga.OrderID, "step", saga.Step)
+	}
+
+	return nil
+}
+
+func (c *CheckoutOrchestrator) resume(ctx context.Context, saga *CheckoutSaga) error {
+	o, err := c.r.FetchByID(ctx, saga.OrderID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	// the process stopped after saving the order but before recording it
+	if o != nil && o.PaymentID != uuid.Nil && (saga.PaymentID == uuid.Nil || saga.PaymentID == o.PaymentID) {
+		saga.PaymentID = o.PaymentID
+		c.step(ctx, saga, SagaCompleted)
+
+		return nil
+	}
+
+	reason := saga.Reason
+	if reason == "" {
+		reason = "checkout interrupted"
+	}
+
+	return c.compensate(ctx, saga, reason)
+}
+
+// authorize holds the order total, with a split payment when the request has tenders
+func (c *CheckoutOrchestrator) authorize(ctx context.Context, req *pb.PaymentRequest) ([]*pb.PaymentConfirmation, error) {
+	if len(req.Tenders) > 0 {
+		resp, err := c.pc.AuthorizeSplit(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		return resp.Payments, nil
+	}
+
+	conf, err := c.pc.Authorize(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*pb.PaymentConfirmation{conf}, nil
+}
+
+func parsePaymentIDs(payments []*pb.PaymentConfirmation) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(payments))
+	for _, p := range payments {
+		id, err := uuid.Parse(p.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse payment confirmation id: %w", err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (c *CheckoutOrchestrator) complete(ctx context.Context, o *Order, paymentIDs []uuid.UUID) error {
+	if err := o.MarkPaid(paymentIDs...); err != nil {
+		return err
+	}
+
+	if err := c.w.Add(ctx, o); err != nil {
+		return fmt.Errorf("failed saving order: %w", err)
+	}
+
+	return nil
+}
+
+// compensate voids the payment of the saga. When it fails, the saga stays in the
+// compensating step and is retried on the next Resume.
+func (c *CheckoutOrchestrator) compensate(ctx context.Context, saga *CheckoutSaga, reason string) error {
+	saga.Reason = reason
+	c.step(ctx, saga, SagaCompensating)
+
+	req := &pb.VoidRequest{OrderID: saga.OrderID.String(), Reason: reason}
+	if saga.PaymentID != uuid.Nil {
+		req.ID = saga.PaymentID.String()
+	}
+
+	_, err := c.pc.Void(ctx, req)
+	switch {
+	case status.Code(err) == codes.NotFound:
+		// the customer was never charged
+		c.step(ctx, saga, SagaAborted)
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to void payment: %w", err)
+	}
+
+	c.step(ctx, saga, SagaCompensated)
+
+	return nil
+}
+
+// step records the progress of the saga. Failing to record a step doesn't fail the
+// checkout, at worst the saga is resumed and settled on the next start.
+func (c *CheckoutOrchestrator) step(ctx context.Context, saga *CheckoutSaga, step SagaStep) {
+	saga.Step = step
+	saga.UpdatedAt = time.Now().UTC()
+
+	if err := c.sw.Add(ctx, saga); err != nil {
+		log.WithContext(ctx).Named("orders").Errorw("failed to record checkout saga step",
+			"saga_id", saga.ID, "step", step, "err", err)
+	}
+}
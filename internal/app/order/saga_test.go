@@ -0,0 +1,327 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/italolelis/coffee-shop/internal/pkg/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakePaymentClient struct {
+	pb.PaymentClient
+	authorize      func(*pb.PaymentRequest) (*pb.PaymentConfirmation, error)
+	authorizeSplit func(*pb.PaymentRequest) (*pb.SplitConfirmation, error)
+	confirmations  map[string]*pb.PaymentConfirmation
+	voids          []*pb.VoidRequest
+	void           func(*pb.VoidRequest) error
+	refund         func(*pb.RefundRequest) (*pb.RefundConfirmation, error)
+	capture        func(*pb.CaptureRequest) (*pb.PaymentConfirmation, error)
+	getTipReport   func(*pb.TipReportRequest) (*pb.TipReport, error)
+}
+
+func (c *fakePaymentClient) GetTipReport(_ context.Context, r *pb.TipReportRequest, _ ...grpc.CallOption) (*pb.TipReport, error) {
+	return c.getTipReport(r)
+}
+
+func (c *fakePaymentClient) Capture(_ context.Context, r *pb.CaptureRequest, _ ...grpc.CallOption) (*pb.PaymentConfirmation, error) {
+	return c.capture(r)
+}
+
+func (c *fakePaymentClient) AuthorizeSplit(_ context.Context, r *pb.PaymentRequest, _ ...grpc.CallOption) (*pb.SplitConfirmation, error) {
+	return c.authorizeSplit(r)
+}
+
+func (c *fakePaymentClient) GetConfirmation(_ context.Context, r *pb.GetConfirmationRequest, _ ...grpc.CallOption) (*pb.PaymentConfirmation, error) {
+	conf, ok := c.confirmations[r.ID]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "payment confirmation not found")
+	}
+
+	return conf, nil
+}
+
+func (c *fakePaymentClient) Authorize(_ context.Context, r *pb.PaymentRequest, _ ...grpc.CallOption) (*pb.PaymentConfirmation, error) {
+	return c.authorize(r)
+}
+
+func (c *fakePaymentClient) Void(_ context.Context, r *pb.VoidRequest, _ ...grpc.CallOption) (*pb.PaymentConfirmation, error) {
+	c.voids = append(c.voids, r)
+	if c.void != nil {
+		if err := c.void(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return &pb.PaymentConfirmation{ID: r.ID, OrderID: r.OrderID, Status: "voided"}, nil
+}
+
+func (c *fakePaymentClient) Refund(_ context.Context, r *pb.RefundRequest, _ ...grpc.CallOption) (*pb.RefundConfirmation, error) {
+	return c.refund(r)
+}
+
+// fakeStore keeps orders and sagas in maps, failing to save orders when addErr is set
+type fakeStore struct {
+	orders map[uuid.UUID]*Order
+	sagas  map[uuid.UUID]CheckoutSaga
+	addErr error
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{orders: make(map[uuid.UUID]*Order), sagas: make(map[uuid.UUID]CheckoutSaga)}
+}
+
+func (s *fakeStore) FetchByID(_ context.Context, id uuid.UUID) (*Order, error) {
+	o, ok := s.orders[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return o, nil
+}
+
+func (s *fakeStore) FetchActiveByCustomer(context.Context, string) (*Order, error) {
+	return nil, ErrNotFound
+}
+
+func (s *fakeStore) Add(_ context.Context, o *Order) error {
+	if s.addErr != nil {
+		return s.addErr
+	}
+
+	s.orders[o.ID] = o
+
+	return nil
+}
+
+func (s *fakeStore) FetchIncomplete(_ context.Context, updatedBefore time.Time) ([]*CheckoutSaga, error) {
+	sagas := make([]*CheckoutSaga, 0)
+	for _, saga := range s.sagas {
+		if !saga.Step.Done() && saga.UpdatedAt.Before(updatedBefore) {
+			saga := saga
+			sagas = append(sagas, &saga)
+		}
+	}
+
+	return sagas, nil
+}
+
+type fakeSagaWriter struct{ *fakeStore }
+
+func (w fakeSagaWriter) Add(_ context.Context, saga *CheckoutSaga) error {
+	w.sagas[saga.ID] = *saga
+	return nil
+}
+
+func (s *fakeStore) onlySaga(t *testing.T) CheckoutSaga {
+	require.Len(t, s.sagas, 1)
+	for _, saga := range s.sagas {
+		return saga
+	}
+
+	return CheckoutSaga{}
+}
+
+func newCheckedOutOrder(t *testing.T) *Order {
+	o := New("anna")
+	require.NoError(t, o.AddItem(&Item{Name: "cappuccino", ServingSize: "L", Price: money.New(260, "EUR"), Qty: 1}))
+	require.NoError(t, o.Checkout())
+
+	return o
+}
+
+func TestCheckoutOrchestrator_Run(t *testing.T) {
+	t.Parallel()
+
+	paymentID := uuid.New()
+	authorized := func(*pb.PaymentRequest) (*pb.PaymentConfirmation, error) {
+		return &pb.PaymentConfirmation{ID: paymentID.String()}, nil
+	}
+
+	tests := []struct {
+		name          string
+		authorize     func(*pb.PaymentRequest) (*pb.PaymentConfirmation, error)
+		void          func(*pb.VoidRequest) error
+		addErr        error
+		expectedStep  SagaStep
+		expectedVoids int
+	}{
+		{
+			name:         "order paid and saved",
+			authorize:    authorized,
+			expectedStep: SagaCompleted,
+		},
+		{
+			name: "payment declined",
+			authorize: func(*pb.PaymentRequest) (*pb.PaymentConfirmation, error) {
+				st, err := status.New(codes.FailedPrecondition, "payment declined").
+					WithDetails(&errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{
+						{Type: pb.ViolationDeclined, Subject: "payment", Description: "card expired"},
+					}})
+				require.NoError(t, err)
+
+				return nil, st.Err()
+			},
+			expectedStep: SagaAborted,
+		},
+		{
+			name: "payment rejected as invalid",
+			authorize: func(*pb.PaymentRequest) (*pb.PaymentConfirmation, error) {
+				return nil, status.Error(codes.InvalidArgument, "payment method not supported")
+			},
+			expectedStep: SagaAborted,
+		},
+		{
+			name: "payment service fault",
+			authorize: func(*pb.PaymentRequest) (*pb.PaymentConfirmation, error) {
+				return nil, status.Error(codes.Internal, "failed to save payment confirmation")
+			},
+			expectedStep:  SagaCompensated,
+			expectedVoids: 1,
+		},
+		{
+			name: "payment failed for an unknown reason",
+			authorize: func(*pb.PaymentRequest) (*pb.PaymentConfirmation, error) {
+				return nil, status.Error(codes.Unknown, "connection reset")
+			},
+			expectedStep:  SagaCompensated,
+			expectedVoids: 1,
+		},
+		{
+			name: "payment outcome unknown",
+			authorize: func(*pb.PaymentRequest) (*pb.PaymentConfirmation, error) {
+				return nil, status.Error(codes.DeadlineExceeded, "timeout")
+			},
+			expectedStep:  SagaCompensated,
+			expectedVoids: 1,
+		},
+		{
+			name:          "saving the order fails",
+			authorize:     authorized,
+			addErr:        errors.New("disk full"),
+			expectedStep:  SagaCompensated,
+			expectedVoids: 1,
+		},
+		{
+			name:      "saving the order and voiding fail",
+			authorize: authorized,
+			addErr:    errors.New("disk full"),
+			void: func(*pb.VoidRequest) error {
+				return status.Error(codes.Unavailable, "connection refused")
+			},
+			expectedStep:  SagaCompensating,
+			expectedVoids: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := newFakeStore()
+			store.addErr = tt.addErr
+			pc := &fakePaymentClient{authorize: tt.authorize, void: tt.void}
+			co := NewCheckoutOrchestrator(store, store, pc, store, fakeSagaWriter{store})
+
+			o := newCheckedOutOrder(t)
+			err := co.Run(context.Background(), o, newPaymentRequest(o, "credit_card"))
+			if tt.expectedStep == SagaCompleted {
+				require.NoError(t, err)
+				assert.Equal(t, paymentID, store.orders[o.ID].PaymentID)
+			} else {
+				require.Error(t, err)
+			}
+
+			saga := store.onlySaga(t)
+			assert.Equal(t, tt.expectedStep, saga.Step)
+			assert.Len(t, pc.voids, tt.expectedVoids)
+		})
+	}
+}
+
+func TestCheckoutOrchestrator_RunSplit(t *testing.T) {
+	t.Parallel()
+
+	store := newFakeStore()
+	giftCard, creditCard := uuid.New(), uuid.New()
+	pc := &fakePaymentClient{authorizeSplit: func(r *pb.PaymentRequest) (*pb.SplitConfirmation, error) {
+		require.Len(t, r.Tenders, 2)
+		return &pb.SplitConfirmation{Payments: []*pb.PaymentConfirmation{
+			{ID: giftCard.String()}, {ID: creditCard.String()},
+		}}, nil
+	}}
+	co := NewCheckoutOrchestrator(store, store, pc, store, fakeSagaWriter{store})
+
+	o := newCheckedOutOrder(t)
+	req := newPaymentRequest(o, "")
+	req.Tenders = []*pb.Tender{{Method: "gift_card", Amount: 200, GiftCardNumber: "1234"}, {Method: "credit_card", Amount: 60}}
+
+	require.NoError(t, co.Run(context.Background(), o, req))
+	assert.Equal(t, giftCard, store.orders[o.ID].PaymentID)
+	assert.Equal(t, PaymentIDs{giftCard, creditCard}, store.orders[o.ID].PaymentIDs)
+	assert.Equal(t, SagaCompleted, store.onlySaga(t).Step)
+}
+
+func TestCheckoutOrchestrator_Resume(t *testing.T) {
+	t.Parallel()
+
+	store := newFakeStore()
+	pc := &fakePaymentClient{}
+	co := NewCheckoutOrchestrator(store, store, pc, store, fakeSagaWriter{store})
+	stale := time.Now().UTC().Add(-time.Hour)
+
+	// the order was saved but the process stopped before recording it
+	saved := newCheckedOutOrder(t)
+	savedPayment := uuid.New()
+	require.NoError(t, saved.MarkPaid(savedPayment))
+	store.orders[saved.ID] = saved
+
+	completed := NewCheckoutSaga(saved.ID)
+	completed.Step, completed.PaymentID, completed.UpdatedAt = SagaPaid, savedPayment, stale
+	store.sagas[completed.ID] = *completed
+
+	// the customer was charged but the order was never saved
+	unsaved := NewCheckoutSaga(uuid.New())
+	unsaved.Step, unsaved.PaymentID, unsaved.UpdatedAt = SagaPaid, uuid.New(), stale
+	store.sagas[unsaved.ID] = *unsaved
+
+	// the payment service never saw the payment
+	unpaid := NewCheckoutSaga(uuid.New())
+	unpaid.UpdatedAt = stale
+	store.sagas[unpaid.ID] = *unpaid
+
+	// another process is still running the checkout, its payment must be left alone
+	running := NewCheckoutSaga(uuid.New())
+	running.Step, running.PaymentID = SagaPaid, uuid.New()
+	store.sagas[running.ID] = *running
+	pc.void = func(r *pb.VoidRequest) error {
+		if r.OrderID == unpaid.OrderID.String() {
+			return status.Error(codes.NotFound, "payment confirmation not found")
+		}
+
+		return nil
+	}
+
+	require.NoError(t, co.Resume(context.Background(), time.Minute))
+
+	assert.Equal(t, SagaCompleted, store.sagas[completed.ID].Step)
+	assert.Equal(t, SagaCompensated, store.sagas[unsaved.ID].Step)
+	assert.Equal(t, SagaAborted, store.sagas[unpaid.ID].Step)
+	assert.Equal(t, SagaPaid, store.sagas[running.ID].Step)
+
+	require.Len(t, pc.voids, 2)
+	for _, v := range pc.voids {
+		assert.NotEqual(t, savedPayment.String(), v.ID)
+		assert.NotEqual(t, running.PaymentID.String(), v.ID)
+		assert.NotEqual(t, running.OrderID.String(), v.OrderID)
+	}
+}
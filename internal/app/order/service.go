@@ -4,16 +4,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/catalog"
+	"github.com/italolelis/coffee-shop/internal/pkg/idempotency"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
 	"github.com/italolelis/coffee-shop/internal/pkg/pb"
 	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
 )
 
-var ErrNotFound = errors.New("order not found")
+var (
+	ErrNotFound = errors.New("order not found")
+	ErrNotPaid  = errors.New("order is not paid")
+	// ErrConflict is returned when the order was changed by another request since it was
+	// fetched, or when the customer already has an open order
+	ErrConflict = errors.New("order was changed by another request")
+)
 
 type Reader interface {
 	FetchByID(context.Context, uuid.UUID) (*Order, error)
+	// FetchActiveByCustomer returns the open order (cart) of a customer
+	FetchActiveByCustomer(context.Context, string) (*Order, error)
 }
 
 type Writer interface {
@@ -24,65 +38,549 @@ type Service interface {
 	Checkout(context.Context, CheckoutCommand) (uuid.UUID, error)
 	AddToOrder(context.Context, AddToOrderCommand) (uuid.UUID, error)
 	Fetch(context.Context, uuid.UUID) (*Order, error)
+	FetchPayments(context.Context, uuid.UUID) ([]*Payment, error)
+	Refund(context.Context, RefundCommand) ([]*Refund, error)
+	UpdateStatus(context.Context, UpdateStatusCommand) (*Order, error)
+	RemoveItem(context.Context, RemoveItemCommand) (*Order, error)
+	UpdateItemQty(context.Context, UpdateItemQtyCommand) (*Order, error)
+	ApplyCoupon(context.Context, ApplyCouponCommand) (*Order, error)
+	PaymentMethods(context.Context) ([]PaymentMethod, error)
+	TipReport(context.Context, TipReportQuery) ([]*TipTotal, error)
+}
+
+const (
+	// PaymentMethodGiftCard is the payment method that redeems the gift card given on checkout
+	PaymentMethodGiftCard = "gift_card"
+	// PaymentMethodCash is the payment method for cash handed over to the barista on checkout
+	PaymentMethodCash = "cash"
+)
+
+// PaymentMethod is a way customers can pay their orders with
+type PaymentMethod struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// Payment is the confirmation of the payment of an order, as recorded by the payment service
+type Payment struct {
+	ID       uuid.UUID   `json:"id"`
+	OrderID  uuid.UUID   `json:"order_id"`
+	Method   string      `json:"method"`
+	Amount   money.Money `json:"amount"`
+	Refunded money.Money `json:"refunded"`
+	Status   string      `json:"status"`
+	PayedAt  time.Time   `json:"payed_at"`
+	// Tendered is the cash handed over and Change what was given back, they are only set for cash payments
+	Tendered *money.Money `json:"tendered,omitempty"`
+	Change   *money.Money `json:"change,omitempty"`
+	// Tip is the part of the amount given as a tip to the Barista, it's only set for tipped payments
+	Tip     *money.Money `json:"tip,omitempty"`
+	Barista string       `json:"barista,omitempty"`
 }
 
+// Refund is money given back to the customer for the payment of an order
+type Refund struct {
+	ID         uuid.UUID   `json:"id"`
+	PaymentID  uuid.UUID   `json:"payment_id"`
+	OrderID    uuid.UUID   `json:"order_id"`
+	Amount     money.Money `json:"amount"`
+	Reason     string      `json:"reason,omitempty"`
+	RefundedAt time.Time   `json:"refunded_at"`
+}
+
+// CheckoutCommand checks out the order with the given ID. When no ID is
+// given, the active cart of the customer is checked out.
 type CheckoutCommand struct {
-	CustomerName  string `json:"customer_name"`
-	PaymentMethod string `json:"payment_method"`
+	OrderID       uuid.UUID `json:"order_id"`
+	CustomerName  string    `json:"customer_name"`
+	PaymentMethod string    `json:"payment_method"`
+	// GiftCardNumber is the card to redeem when paying with a gift card
+	GiftCardNumber string `json:"gift_card_number,omitempty"`
+	// CashTendered is the cash handed over when paying with cash, it must cover the order
+	// total plus the tip
+	CashTendered *money.Money `json:"cash_tendered,omitempty"`
+	// Tenders split the payment between several methods instead of paying it all
+	// with PaymentMethod, they must add up to the order total plus the tip. The tip
+	// is charged to the last tender.
+	Tenders []Tender `json:"tenders,omitempty"`
+	// Tip is given on top of the order total, it's charged along with the order
+	Tip *TipRequest `json:"tip,omitempty"`
+	// Barista served the order, tips are reported by barista
+	Barista string `json:"barista,omitempty"`
+	// IdempotencyKey is set from the Idempotency-Key header
+	IdempotencyKey string `json:"-"`
+}
+
+// Tender is the part of the order total paid with a single payment method
+type Tender struct {
+	PaymentMethod  string      `json:"payment_method"`
+	Amount         money.Money `json:"amount"`
+	GiftCardNumber string      `json:"gift_card_number,omitempty"`
+	// CashTendered is the cash handed over for a cash tender, it must cover the tender amount
+	CashTendered *money.Money `json:"cash_tendered,omitempty"`
 }
 
+// AddToOrderCommand adds items to the order with the given ID. When no ID is
+// given, the items are added to the active cart of the customer, which is
+// created if the customer doesn't have one.
 type AddToOrderCommand struct {
-	CustomerName string `json:"customer_name"`
-	Items        Items  `json:"items"`
+	OrderID      uuid.UUID     `json:"order_id"`
+	CustomerName string        `json:"customer_name"`
+	Items        []ItemRequest `json:"items"`
+	// Store is where a new order is served, e.g. eat-in or take-away, it's ignored
+	// when adding to an existing order
+	Store string `json:"store,omitempty"`
+}
+
+// RefundCommand gives back part or all of the payment of an order. Without an
+// amount, everything that wasn't refunded yet is given back.
+type RefundCommand struct {
+	OrderID uuid.UUID    `json:"order_id"`
+	Amount  *money.Money `json:"amount"`
+	Reason  string       `json:"reason"`
+	// IdempotencyKey is set from the Idempotency-Key header, retries with the same
+	// key don't refund the payments again
+	IdempotencyKey string `json:"-"`
+}
+
+// UpdateStatusCommand moves a paid order through the preparation steps, up to
+// the customer picking it up
+type UpdateStatusCommand struct {
+	OrderID uuid.UUID `json:"order_id"`
+	Status  Status    `json:"status"`
+}
+
+// RemoveItemCommand removes a line from an open order
+type RemoveItemCommand struct {
+	OrderID uuid.UUID `json:"order_id"`
+	LineID  uuid.UUID `json:"line_id"`
+}
+
+// UpdateItemQtyCommand replaces the quantity of a line of an open order
+type UpdateItemQtyCommand struct {
+	OrderID uuid.UUID `json:"order_id"`
+	LineID  uuid.UUID `json:"line_id"`
+	Qty     int       `json:"qty"`
+}
+
+// ApplyCouponCommand applies a coupon to an open order, an empty coupon removes it
+type ApplyCouponCommand struct {
+	OrderID uuid.UUID `json:"order_id"`
+	Coupon  string    `json:"coupon"`
+}
+
+// ItemRequest is an item requested by the customer. The price is never taken
+// from the request, it's resolved from the catalog.
+type ItemRequest struct {
+	Name        string `json:"name"`
+	ServingSize string `json:"serving_size"`
+	Qty         int    `json:"qty"`
+	// Modifiers are the names of the catalog modifiers that customize the item,
+	// e.g. "oat milk" or "extra shot". Repeating a modifier chooses it several times.
+	Modifiers []string `json:"modifiers,omitempty"`
+}
+
+// Validate checks that the command identifies an order and a payment method
+func (cmd CheckoutCommand) Validate() error {
+	var errs ValidationErrors
+	if cmd.OrderID == uuid.Nil && cmd.CustomerName == "" {
+		errs = append(errs, &ValidationError{Field: "customer_name", Reason: "is required when no order id is given"})
+	}
+
+	switch {
+	case cmd.PaymentMethod == "" && len(cmd.Tenders) == 0:
+		errs = append(errs, &ValidationError{Field: "payment_method", Reason: "is required"})
+	case cmd.PaymentMethod != "" && len(cmd.Tenders) > 0:
+		errs = append(errs, &ValidationError{Field: "payment_method", Reason: "can't be combined with tenders"})
+	}
+
+	if cmd.PaymentMethod == PaymentMethodGiftCard && cmd.GiftCardNumber == "" {
+		errs = append(errs, &ValidationError{Field: "gift_card_number", Reason: "is required when paying with a gift card"})
+	}
+
+	if cmd.PaymentMethod == PaymentMethodCash {
+		if err := validateCashTendered("cash_tendered", cmd.CashTendered); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if cmd.Tip != nil {
+		errs = append(errs, cmd.Tip.validate()...)
+	}
+
+	for idx, t := range cmd.Tenders {
+		if t.PaymentMethod == "" {
+			errs = append(errs, &ValidationError{Field: fmt.Sprintf("tenders[%d].payment_method", idx), Reason: "is required"})
+		}
+
+		if t.Amount.IsZero() || t.Amount.IsNegative() {
+			errs = append(errs, &ValidationError{Field: fmt.Sprintf("tenders[%d].amount", idx), Reason: "must be positive"})
+		}
+
+		if t.PaymentMethod == PaymentMethodGiftCard && t.GiftCardNumber == "" {
+			errs = append(errs, &ValidationError{
+				Field:  fmt.Sprintf("tenders[%d].gift_card_number", idx),
+				Reason: "is required when paying with a gift card",
+			})
+		}
+
+		if t.PaymentMethod == PaymentMethodCash {
+			if err := validateCashTendered(fmt.Sprintf("tenders[%d].cash_tendered", idx), t.CashTendered); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errs.orNil()
+}
+
+func validateCashTendered(field string, tendered *money.Money) *ValidationError {
+	switch {
+	case tendered == nil:
+		return &ValidationError{Field: field, Reason: "is required when paying with cash"}
+	case tendered.IsZero() || tendered.IsNegative():
+		return &ValidationError{Field: field, Reason: "must be positive"}
+	}
+
+	return nil
+}
+
+// checkCashTendered makes sure the cash handed over is in the currency of the amount and covers it
+func checkCashTendered(field string, tendered *money.Money, amount money.Money) error {
+	if tendered == nil {
+		return nil
+	}
+
+	if !tendered.SameCurrency(amount) {
+		return &ValidationError{Field: field, Reason: fmt.Sprintf("must be in %s", amount.Currency), Err: money.ErrCurrencyMismatch}
+	}
+
+	if tendered.Amount < amount.Amount {
+		return &ValidationError{Field: field, Reason: fmt.Sprintf("must cover %s, got %s", amount, tendered)}
+	}
+
+	return nil
+}
+
+// fingerprint hashes the parts of the command that must match when its idempotency key is reused
+func (cmd CheckoutCommand) fingerprint() string {
+	parts := []string{cmd.OrderID.String(), cmd.CustomerName, cmd.PaymentMethod, cmd.GiftCardNumber, tenderedString(cmd.CashTendered), cmd.Barista}
+	if cmd.Tip != nil {
+		parts = append(parts, tenderedString(cmd.Tip.Amount), strconv.FormatInt(cmd.Tip.Percent, 10))
+	}
+
+	for _, t := range cmd.Tenders {
+		parts = append(parts, t.PaymentMethod, t.Amount.String(), t.GiftCardNumber, tenderedString(t.CashTendered))
+	}
+
+	return idempotency.Fingerprint(parts...)
+}
+
+func tenderedString(tendered *money.Money) string {
+	if tendered == nil {
+		return ""
+	}
+
+	return tendered.String()
+}
+
+// Validate checks that the command identifies an order and a preparation step
+func (cmd UpdateStatusCommand) Validate() error {
+	var errs ValidationErrors
+	if cmd.OrderID == uuid.Nil {
+		errs = append(errs, &ValidationError{Field: "order_id", Reason: "is required"})
+	}
+
+	switch cmd.Status {
+	case StatusPreparing, StatusReady, StatusPickedUp:
+	default:
+		errs = append(errs, &ValidationError{
+			Field:  "status",
+			Reason: fmt.Sprintf("must be one of %s, %s or %s", StatusPreparing, StatusReady, StatusPickedUp),
+		})
+	}
+
+	return errs.orNil()
+}
+
+// Validate checks that the command identifies an order and one of its lines
+func (cmd RemoveItemCommand) Validate() error {
+	return validateLine(cmd.OrderID, cmd.LineID).orNil()
+}
+
+// Validate checks that the command identifies a line of an order and a valid quantity
+func (cmd UpdateItemQtyCommand) Validate() error {
+	errs := validateLine(cmd.OrderID, cmd.LineID)
+	if err := validateQty("qty", cmd.Qty); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs.orNil()
+}
+
+func validateLine(orderID, lineID uuid.UUID) ValidationErrors {
+	var errs ValidationErrors
+	if orderID == uuid.Nil {
+		errs = append(errs, &ValidationError{Field: "order_id", Reason: "is required"})
+	}
+
+	if lineID == uuid.Nil {
+		errs = append(errs, &ValidationError{Field: "line_id", Reason: "is required"})
+	}
+
+	return errs
+}
+
+// Validate checks that the command identifies an order
+func (cmd ApplyCouponCommand) Validate() error {
+	var errs ValidationErrors
+	if cmd.OrderID == uuid.Nil {
+		errs = append(errs, &ValidationError{Field: "order_id", Reason: "is required"})
+	}
+
+	return errs.orNil()
+}
+
+// Validate checks that the command identifies an order and that the amount, if any, is positive
+func (cmd RefundCommand) Validate() error {
+	var errs ValidationErrors
+	if cmd.OrderID == uuid.Nil {
+		errs = append(errs, &ValidationError{Field: "order_id", Reason: "is required"})
+	}
+
+	if cmd.Amount != nil && (cmd.Amount.IsZero() || cmd.Amount.IsNegative()) {
+		errs = append(errs, &ValidationError{Field: "amount", Reason: "must be positive"})
+	}
+
+	return errs.orNil()
+}
+
+// Validate checks that the command identifies an order and that every requested item is complete
+func (cmd AddToOrderCommand) Validate() error {
+	var errs ValidationErrors
+	if cmd.OrderID == uuid.Nil && cmd.CustomerName == "" {
+		errs = append(errs, &ValidationError{Field: "customer_name", Reason: "is required when no order id is given"})
+	}
+
+	if len(cmd.Items) == 0 {
+		errs = append(errs, &ValidationError{Field: "items", Reason: "at least one item is required"})
+	}
+
+	for idx, i := range cmd.Items {
+		if i.Name == "" {
+			errs = append(errs, &ValidationError{Field: fmt.Sprintf("items[%d].name", idx), Reason: "can't be empty"})
+		}
+
+		if i.ServingSize == "" {
+			errs = append(errs, &ValidationError{Field: fmt.Sprintf("items[%d].serving_size", idx), Reason: "can't be empty"})
+		}
+
+		if err := validateQty(fmt.Sprintf("items[%d].qty", idx), i.Qty); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs.orNil()
 }
 
 type ServiceImp struct {
-	w  Writer
-	r  Reader
-	pc pb.PaymentClient
+	w    Writer
+	r    Reader
+	cr   catalog.Reader
+	pc   pb.PaymentClient
+	idem idempotency.Store
+	co   *CheckoutOrchestrator
+	pr   Promotions
+	tc   TaxCalculator
 }
 
-func NewService(w Writer, r Reader, pc pb.PaymentClient) *ServiceImp {
+// NewService creates the order service, orders aren't discounted when no promotions
+// are given and aren't taxed when no tax calculator is given
+func NewService(
+	w Writer,
+	r Reader,
+	cr catalog.Reader,
+	pc pb.PaymentClient,
+	idem idempotency.Store,
+	co *CheckoutOrchestrator,
+	pr Promotions,
+	tc TaxCalculator,
+) *ServiceImp {
 	return &ServiceImp{
-		w:  w,
-		r:  r,
-		pc: pc,
+		w:    w,
+		r:    r,
+		cr:   cr,
+		pc:   pc,
+		idem: idem,
+		co:   co,
+		pr:   pr,
+		tc:   tc,
 	}
 }
 
+// Checkout pays and closes the order. Commands with an idempotency key are processed
+// only once, repeated keys return the original order id.
 func (s *ServiceImp) Checkout(ctx context.Context, cmd CheckoutCommand) (uuid.UUID, error) {
 	ctx, span := tracing.Start(ctx, "service/order/checkout")
 	defer span.End()
 
-	o, err := s.r.FetchByID(ctx, uuid.NewSHA1(uuid.NameSpaceOID, []byte(cmd.CustomerName)))
+	if cmd.IdempotencyKey == "" {
+		return s.checkout(ctx, cmd)
+	}
+
+	rec, err := s.idem.Reserve(ctx, cmd.IdempotencyKey, cmd.fingerprint())
 	if err != nil {
 		return uuid.Nil, err
 	}
 
-	_, err = s.pc.Pay(ctx, &pb.PaymentRequest{
-		Method:  cmd.PaymentMethod,
-		OrderID: o.ID.String(),
-	})
+	if rec != nil {
+		return uuid.ParseBytes(rec.Response)
+	}
+
+	id, err := s.checkout(ctx, cmd)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("failed paying order: %w", err)
+		if rerr := s.idem.Release(ctx, cmd.IdempotencyKey); rerr != nil {
+			span.RecordError(ctx, rerr)
+		}
+
+		return uuid.Nil, err
 	}
 
-	if err := s.w.Add(ctx, o); err != nil {
-		return uuid.Nil, fmt.Errorf("failed saving order: %w", err)
+	// the order is already paid, so failing to store the key must not fail the checkout
+	if err := s.idem.Complete(ctx, cmd.IdempotencyKey, []byte(id.String())); err != nil {
+		span.RecordError(ctx, err)
+	}
+
+	return id, nil
+}
+
+func (s *ServiceImp) checkout(ctx context.Context, cmd CheckoutCommand) (uuid.UUID, error) {
+	if err := cmd.Validate(); err != nil {
+		return uuid.Nil, err
+	}
+
+	o, err := s.findOrder(ctx, cmd.OrderID, cmd.CustomerName)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	// promotions may have ended and tax rates changed since the order was last changed
+	if err := s.reprice(ctx, o); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := o.Checkout(); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to checkout order: %w", err)
+	}
+
+	if err := s.tip(o, cmd); err != nil {
+		return uuid.Nil, err
+	}
+
+	req := newPaymentRequest(o, cmd.PaymentMethod)
+	req.GiftCardNumber = cmd.GiftCardNumber
+
+	if cmd.PaymentMethod == PaymentMethodCash {
+		if err := checkCashTendered("cash_tendered", cmd.CashTendered, o.TotalWithTip()); err != nil {
+			return uuid.Nil, err
+		}
+
+		req.CashTendered = cmd.CashTendered.Amount
+	}
+
+	if len(cmd.Tenders) > 0 {
+		if req.Tenders, err = newTenders(o, cmd.Tenders); err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	if err := s.redeemCoupon(ctx, o); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := s.co.Run(ctx, o, req); err != nil {
+		s.releaseCoupon(ctx, o)
+		return uuid.Nil, err
 	}
 
 	return o.ID, nil
 }
 
+// tip works out the tip of the checkout and sets it on the order, so a checkout without
+// a tip clears the tip of an earlier checkout that failed
+func (s *ServiceImp) tip(o *Order, cmd CheckoutCommand) error {
+	if cmd.Tip == nil {
+		return o.AddTip(nil)
+	}
+
+	amount, err := cmd.Tip.amount(o.Subtotal())
+	if err != nil {
+		return err
+	}
+
+	return o.AddTip(&Tip{Amount: amount, Barista: cmd.Barista})
+}
+
+// redeemCoupon counts a use of the coupon of the order, if it was granted a discount
+func (s *ServiceImp) redeemCoupon(ctx context.Context, o *Order) error {
+	if s.pr == nil || !o.Discounts.coupon(o.Coupon) {
+		return nil
+	}
+
+	err := s.pr.Redeem(ctx, o.Coupon)
+	if errors.Is(err, ErrInvalidCoupon) {
+		return &ValidationError{Field: "coupon", Reason: err.Error(), Err: err}
+	} else if err != nil {
+		return fmt.Errorf("failed to redeem coupon: %w", err)
+	}
+
+	return nil
+}
+
+// releaseCoupon gives back the use of the coupon of a checkout that failed. The checkout
+// error is what the client needs to see, so failing to release is only recorded.
+func (s *ServiceImp) releaseCoupon(ctx context.Context, o *Order) {
+	if s.pr == nil || !o.Discounts.coupon(o.Coupon) {
+		return
+	}
+
+	ctx, span := tracing.Start(ctx, "service/order/release-coupon")
+	defer span.End()
+
+	if err := s.pr.Release(ctx, o.Coupon); err != nil {
+		span.RecordError(ctx, err)
+	}
+}
+
 func (s *ServiceImp) AddToOrder(ctx context.Context, cmd AddToOrderCommand) (uuid.UUID, error) {
 	ctx, span := tracing.Start(ctx, "service/order/add-to-order")
 	defer span.End()
 
-	o := New(cmd.CustomerName)
-	if err := o.AddItems(cmd.Items); err != nil {
+	if err := cmd.Validate(); err != nil {
+		return uuid.Nil, err
+	}
+
+	items, err := s.resolveItems(ctx, cmd.Items)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	o, err := s.findOrder(ctx, cmd.OrderID, cmd.CustomerName)
+	if errors.Is(err, ErrNotFound) && cmd.OrderID == uuid.Nil {
+		o = New(cmd.CustomerName)
+		o.Store = cmd.Store
+	} else if err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := o.AddItems(items); err != nil {
 		return uuid.Nil, fmt.Errorf("failed adding items to orders: %w", err)
 	}
 
+	if err := s.reprice(ctx, o); err != nil {
+		return uuid.Nil, err
+	}
+
 	if err := s.w.Add(ctx, o); err != nil {
 		return uuid.Nil, fmt.Errorf("failed saving order: %w", err)
 	}
@@ -90,6 +588,671 @@ func (s *ServiceImp) AddToOrder(ctx context.Context, cmd AddToOrderCommand) (uui
 	return o.ID, nil
 }
 
+// RemoveItem removes a line from an open order, e.g. an item added by mistake
+func (s *ServiceImp) RemoveItem(ctx context.Context, cmd RemoveItemCommand) (*Order, error) {
+	ctx, span := tracing.Start(ctx, "service/order/remove-item")
+	defer span.End()
+
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.changeItems(ctx, cmd.OrderID, func(o *Order) error {
+		return o.RemoveItem(cmd.LineID)
+	})
+}
+
+// UpdateItemQty replaces the quantity of a line of an open order
+func (s *ServiceImp) UpdateItemQty(ctx context.Context, cmd UpdateItemQtyCommand) (*Order, error) {
+	ctx, span := tracing.Start(ctx, "service/order/update-item-qty")
+	defer span.End()
+
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.changeItems(ctx, cmd.OrderID, func(o *Order) error {
+		return o.UpdateItemQty(cmd.LineID, cmd.Qty)
+	})
+}
+
+// changeItems applies the change to the order, reprices it and saves it
+func (s *ServiceImp) changeItems(ctx context.Context, orderID uuid.UUID, change func(*Order) error) (*Order, error) {
+	o, err := s.r.FetchByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := change(o); err != nil {
+		return nil, err
+	}
+
+	if err := s.reprice(ctx, o); err != nil {
+		return nil, err
+	}
+
+	if err := s.w.Add(ctx, o); err != nil {
+		return nil, fmt.Errorf("failed saving order: %w", err)
+	}
+
+	return o, nil
+}
+
+// ApplyCoupon applies a coupon to an open order, recomputing its discounts. Coupons
+// that can't be used are rejected, leaving the order as it was.
+func (s *ServiceImp) ApplyCoupon(ctx context.Context, cmd ApplyCouponCommand) (*Order, error) {
+	ctx, span := tracing.Start(ctx, "service/order/apply-coupon")
+	defer span.End()
+
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.changeItems(ctx, cmd.OrderID, func(o *Order) error {
+		return o.ApplyCoupon(cmd.Coupon)
+	})
+}
+
+// reprice recomputes the discounts of the order and then its taxes, which are worked
+// out on the discounted items
+func (s *ServiceImp) reprice(ctx context.Context, o *Order) error {
+	if err := s.applyPromotions(ctx, o); err != nil {
+		return err
+	}
+
+	if s.tc == nil {
+		return nil
+	}
+
+	taxes, err := s.tc.Taxes(ctx, o)
+	if err != nil {
+		return fmt.Errorf("failed to work out taxes: %w", err)
+	}
+
+	return o.ApplyTaxes(taxes)
+}
+
+// applyPromotions recomputes the discounts of the order
+func (s *ServiceImp) applyPromotions(ctx context.Context, o *Order) error {
+	if s.pr == nil {
+		return nil
+	}
+
+	discounts, err := s.pr.Discounts(ctx, o)
+	if errors.Is(err, ErrInvalidCoupon) {
+		return &ValidationError{Field: "coupon", Reason: err.Error(), Err: err}
+	} else if err != nil {
+		return fmt.Errorf("failed to work out discounts: %w", err)
+	}
+
+	return o.ApplyDiscounts(discounts)
+}
+
+// findOrder fetches the order by its ID or, when no ID is given, the active cart of the customer
+func (s *ServiceImp) findOrder(ctx context.Context, id uuid.UUID, customerName string) (*Order, error) {
+	if id != uuid.Nil {
+		return s.r.FetchByID(ctx, id)
+	}
+
+	return s.r.FetchActiveByCustomer(ctx, customerName)
+}
+
+// resolveItems looks up each requested item in the catalog and stamps its
+// authoritative price on the order item
+func (s *ServiceImp) resolveItems(ctx context.Context, reqs []ItemRequest) (Items, error) {
+	var (
+		items = make(Items, 0, len(reqs))
+		errs  ValidationErrors
+	)
+
+	for idx, req := range reqs {
+		p, err := s.cr.FetchByName(ctx, req.Name)
+		if errors.Is(err, catalog.ErrProductNotFound) {
+			errs = append(errs, &ValidationError{
+				Field:  fmt.Sprintf("items[%d].name", idx),
+				Reason: fmt.Sprintf("%q is not in the menu", req.Name),
+				Err:    err,
+			})
+
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to find %q in the catalog: %w", req.Name, err)
+		}
+
+		price, err := p.Price(req.ServingSize)
+		if err != nil {
+			errs = append(errs, &ValidationError{
+				Field:  fmt.Sprintf("items[%d].serving_size", idx),
+				Reason: fmt.Sprintf("%q is not available in size %q", p.Name, req.ServingSize),
+				Err:    err,
+			})
+
+			continue
+		}
+
+		modifiers, err := p.Customize(req.Modifiers...)
+		if err != nil {
+			errs = append(errs, &ValidationError{
+				Field:  fmt.Sprintf("items[%d].modifiers", idx),
+				Reason: err.Error(),
+				Err:    err,
+			})
+
+			continue
+		}
+
+		item := &Item{
+			Name:        p.Name,
+			ServingSize: req.ServingSize,
+			Category:    p.Category,
+			Price:       price,
+			Qty:         req.Qty,
+		}
+		for _, m := range modifiers {
+			item.Modifiers = append(item.Modifiers, &Modifier{Name: m.Name, Price: m.Price})
+		}
+
+		items = append(items, item)
+	}
+
+	if err := errs.orNil(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
 func (s *ServiceImp) Fetch(ctx context.Context, id uuid.UUID) (*Order, error) {
 	return s.r.FetchByID(ctx, id)
 }
+
+// FetchPayments returns the payments of the order, one for each tender
+func (s *ServiceImp) FetchPayments(ctx context.Context, orderID uuid.UUID) ([]*Payment, error) {
+	ctx, span := tracing.Start(ctx, "service/order/fetch-payments")
+	defer span.End()
+
+	o, err := s.r.FetchByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := o.Payments()
+	if len(ids) == 0 {
+		return nil, ErrNotPaid
+	}
+
+	payments := make([]*Payment, 0, len(ids))
+	for _, id := range ids {
+		c, err := s.pc.GetConfirmation(ctx, &pb.GetConfirmationRequest{ID: id.String()})
+		if err != nil {
+			return nil, fmt.Errorf("failed fetching payment confirmation: %w", err)
+		}
+
+		p, err := toPayment(c)
+		if err != nil {
+			return nil, err
+		}
+
+		payments = append(payments, p)
+	}
+
+	return payments, nil
+}
+
+// Refund asks the payment service to give back part or all of the payment of the order,
+// returning a refund for each tender that was refunded. The order is marked as refunded
+// once all of its payments are fully refunded.
+func (s *ServiceImp) Refund(ctx context.Context, cmd RefundCommand) ([]*Refund, error) {
+	ctx, span := tracing.Start(ctx, "service/order/refund")
+	defer span.End()
+
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	o, err := s.r.FetchByID(ctx, cmd.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments := o.Payments()
+	if len(payments) == 0 {
+		return nil, ErrNotPaid
+	}
+
+	if !o.Status.CanTransitionTo(StatusRefunded) {
+		return nil, fmt.Errorf("%w: %s order can't be refunded", ErrInvalidTransition, o.Status)
+	}
+
+	if cmd.Amount != nil && cmd.Amount.Currency != o.Currency() {
+		return nil, &ValidationError{
+			Field:  "amount",
+			Reason: fmt.Sprintf("must be in %s", o.Currency()),
+			Err:    money.ErrCurrencyMismatch,
+		}
+	}
+
+	// statuses holds the latest known status of each payment of the order
+	statuses := make(map[string]string, len(payments))
+
+	var reqs []*pb.RefundRequest
+	if len(payments) == 1 {
+		req := &pb.RefundRequest{ID: payments[0].String(), Reason: cmd.Reason}
+		if cmd.Amount != nil {
+			req.Amount, req.Currency = cmd.Amount.Amount, cmd.Amount.Currency
+		}
+
+		reqs = append(reqs, req)
+	} else if reqs, err = s.splitRefund(ctx, payments, cmd, statuses); err != nil {
+		return nil, err
+	}
+
+	refunds := make([]*Refund, 0, len(reqs))
+	for _, req := range reqs {
+		if cmd.IdempotencyKey != "" {
+			req.IdempotencyKey = cmd.IdempotencyKey + "/" + req.ID
+		}
+
+		rc, err := s.pc.Refund(ctx, req)
+		if err != nil {
+			return nil, classifyRefundError(err)
+		}
+
+		rf, err := toRefund(rc)
+		if err != nil {
+			return nil, err
+		}
+
+		statuses[req.ID] = rc.Payment.GetStatus()
+		refunds = append(refunds, rf)
+	}
+
+	if fullyRefunded(payments, statuses) {
+		if err := o.Refund(); err != nil {
+			return nil, err
+		}
+
+		if err := s.w.Add(ctx, o); err != nil {
+			return nil, fmt.Errorf("failed saving order: %w", err)
+		}
+	}
+
+	return refunds, nil
+}
+
+// splitRefund spreads the refund over the tenders of a split payment, starting from the
+// last tender. Everything is checked before refunding anything, so a refund that can't
+// be made as a whole is rejected without refunding a single tender.
+func (s *ServiceImp) splitRefund(
+	ctx context.Context,
+	payments []uuid.UUID,
+	cmd RefundCommand,
+	statuses map[string]string,
+) ([]*pb.RefundRequest, error) {
+	confs := make([]*pb.PaymentConfirmation, 0, len(payments))
+	for _, id := range payments {
+		c, err := s.pc.GetConfirmation(ctx, &pb.GetConfirmationRequest{ID: id.String()})
+		if err != nil {
+			return nil, fmt.Errorf("failed fetching payment confirmation: %w", err)
+		}
+
+		statuses[c.ID] = c.Status
+		confs = append(confs, c)
+	}
+
+	reqs := make([]*pb.RefundRequest, 0, len(confs))
+	if cmd.Amount == nil {
+		for _, c := range confs {
+			if refundable(c) > 0 {
+				reqs = append(reqs, &pb.RefundRequest{ID: c.ID, Reason: cmd.Reason})
+			}
+		}
+
+		if len(reqs) == 0 {
+			return nil, fmt.Errorf("%w: the payment was already refunded", ErrRefundRejected)
+		}
+
+		return reqs, nil
+	}
+
+	remaining := cmd.Amount.Amount
+	for i := len(confs) - 1; i >= 0 && remaining > 0; i-- {
+		c := confs[i]
+
+		amount := refundable(c)
+		if amount == 0 {
+			continue
+		}
+
+		if amount > remaining {
+			amount = remaining
+		}
+
+		// authorized tenders weren't charged yet, they can only be released as a whole
+		if c.Status == "authorized" && amount != c.Amount {
+			return nil, &ValidationError{
+				Field:  "amount",
+				Reason: "must cover whole tenders until the order is picked up",
+			}
+		}
+
+		reqs = append(reqs, &pb.RefundRequest{ID: c.ID, Amount: amount, Currency: c.Currency, Reason: cmd.Reason})
+		remaining -= amount
+	}
+
+	if remaining > 0 {
+		return nil, fmt.Errorf("%w: only %s can be refunded",
+			ErrRefundRejected, money.New(cmd.Amount.Amount-remaining, cmd.Amount.Currency))
+	}
+
+	return reqs, nil
+}
+
+// refundable returns what can still be refunded of a payment
+func refundable(c *pb.PaymentConfirmation) int64 {
+	switch c.Status {
+	case "authorized", "captured", "partially_refunded":
+		return c.Amount - c.Refunded
+	default:
+		return 0
+	}
+}
+
+// fullyRefunded checks that every payment of the order was refunded, released
+// authorizations are refunded in full as nothing was charged
+func fullyRefunded(payments []uuid.UUID, statuses map[string]string) bool {
+	for _, id := range payments {
+		if status := statuses[id.String()]; status != "refunded" && status != "released" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// UpdateStatus moves the order to the next preparation step. The authorized payment
+// is captured when the order is picked up, and the order is only picked up once
+// its payment is captured.
+func (s *ServiceImp) UpdateStatus(ctx context.Context, cmd UpdateStatusCommand) (*Order, error) {
+	ctx, span := tracing.Start(ctx, "service/order/update-status")
+	defer span.End()
+
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	o, err := s.r.FetchByID(ctx, cmd.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cmd.Status {
+	case StatusPreparing:
+		err = o.StartPreparing()
+	case StatusReady:
+		err = o.MarkReady()
+	case StatusPickedUp:
+		err = s.pickUp(ctx, o)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.w.Add(ctx, o); err != nil {
+		return nil, fmt.Errorf("failed saving order: %w", err)
+	}
+
+	return o, nil
+}
+
+func (s *ServiceImp) pickUp(ctx context.Context, o *Order) error {
+	if !o.Status.CanTransitionTo(StatusPickedUp) {
+		return fmt.Errorf("%w: from %s to %s", ErrInvalidTransition, o.Status, StatusPickedUp)
+	}
+
+	if !o.PaymentCaptured {
+		// captures are idempotent, so tenders captured by a failed pick up are skipped on retry
+		for _, id := range o.Payments() {
+			if _, err := s.pc.Capture(ctx, &pb.CaptureRequest{ID: id.String()}); err != nil {
+				return classifyPaymentError(err)
+			}
+		}
+
+		if err := o.MarkCaptured(); err != nil {
+			return err
+		}
+	}
+
+	return o.PickUp()
+}
+
+// PaymentMethods returns the payment methods currently enabled in the payment service
+func (s *ServiceImp) PaymentMethods(ctx context.Context) ([]PaymentMethod, error) {
+	ctx, span := tracing.Start(ctx, "service/order/payment-methods")
+	defer span.End()
+
+	resp, err := s.pc.ListMethods(ctx, &pb.ListMethodsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment methods: %w", err)
+	}
+
+	methods := make([]PaymentMethod, 0, len(resp.Methods))
+	for _, m := range resp.Methods {
+		methods = append(methods, PaymentMethod{Name: m.Name, DisplayName: m.DisplayName})
+	}
+
+	return methods, nil
+}
+
+// TipReport sums the tips of the payments made in the period of the query by barista and shift
+func (s *ServiceImp) TipReport(ctx context.Context, q TipReportQuery) ([]*TipTotal, error) {
+	ctx, span := tracing.Start(ctx, "service/order/tip-report")
+	defer span.End()
+
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	req := &pb.TipReportRequest{Barista: q.Barista}
+	if !q.From.IsZero() {
+		from, err := ptypes.TimestampProto(q.From)
+		if err != nil {
+			return nil, &ValidationError{Field: "from", Reason: err.Error(), Err: err}
+		}
+
+		req.From = from
+	}
+
+	if !q.To.IsZero() {
+		to, err := ptypes.TimestampProto(q.To)
+		if err != nil {
+			return nil, &ValidationError{Field: "to", Reason: err.Error(), Err: err}
+		}
+
+		req.To = to
+	}
+
+	resp, err := s.pc.GetTipReport(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the tip report: %w", err)
+	}
+
+	totals := make([]*TipTotal, 0, len(resp.Totals))
+	for _, t := range resp.Totals {
+		total := &TipTotal{
+			Barista:  t.Barista,
+			Amount:   money.New(t.Amount, t.Currency),
+			Payments: int(t.Payments),
+		}
+
+		if t.ShiftID != "" {
+			if total.ShiftID, err = uuid.Parse(t.ShiftID); err != nil {
+				return nil, fmt.Errorf("failed to parse shift id: %w", err)
+			}
+		}
+
+		totals = append(totals, total)
+	}
+
+	return totals, nil
+}
+
+func toPayment(c *pb.PaymentConfirmation) (*Payment, error) {
+	id, err := uuid.Parse(c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse payment id: %w", err)
+	}
+
+	orderID, err := uuid.Parse(c.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse order id: %w", err)
+	}
+
+	payedAt, err := ptypes.Timestamp(c.PayedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse payment date: %w", err)
+	}
+
+	p := &Payment{
+		ID:       id,
+		OrderID:  orderID,
+		Method:   c.Method,
+		Amount:   money.New(c.Amount, c.Currency),
+		Refunded: money.New(c.Refunded, c.Currency),
+		Status:   c.Status,
+		PayedAt:  payedAt,
+	}
+
+	if c.Tendered != 0 {
+		tendered, change := money.New(c.Tendered, c.Currency), money.New(c.Change, c.Currency)
+		p.Tendered, p.Change = &tendered, &change
+	}
+
+	if c.Tip != 0 {
+		tip := money.New(c.Tip, c.Currency)
+		p.Tip, p.Barista = &tip, c.Barista
+	}
+
+	return p, nil
+}
+
+func toRefund(rc *pb.RefundConfirmation) (*Refund, error) {
+	id, err := uuid.Parse(rc.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse refund id: %w", err)
+	}
+
+	paymentID, err := uuid.Parse(rc.PaymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse payment id: %w", err)
+	}
+
+	orderID, err := uuid.Parse(rc.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse order id: %w", err)
+	}
+
+	refundedAt, err := ptypes.Timestamp(rc.RefundedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse refund date: %w", err)
+	}
+
+	return &Refund{
+		ID:         id,
+		PaymentID:  paymentID,
+		OrderID:    orderID,
+		Amount:     money.New(rc.Amount, rc.Currency),
+		Reason:     rc.Reason,
+		RefundedAt: refundedAt,
+	}, nil
+}
+
+// newTenders converts the tenders of the checkout, which must add up to the order total plus the tip
+func newTenders(o *Order, tenders []Tender) ([]*pb.Tender, error) {
+	var (
+		total = o.TotalWithTip()
+		sum   = money.New(0, total.Currency)
+		req   = make([]*pb.Tender, 0, len(tenders))
+	)
+
+	for idx, t := range tenders {
+		if !t.Amount.SameCurrency(total) {
+			return nil, &ValidationError{
+				Field:  fmt.Sprintf("tenders[%d].amount", idx),
+				Reason: fmt.Sprintf("must be in %s", total.Currency),
+				Err:    money.ErrCurrencyMismatch,
+			}
+		}
+
+		sum.Amount += t.Amount.Amount
+		pt := &pb.Tender{Method: t.PaymentMethod, Amount: t.Amount.Amount, GiftCardNumber: t.GiftCardNumber}
+
+		if t.PaymentMethod == PaymentMethodCash {
+			if err := checkCashTendered(fmt.Sprintf("tenders[%d].cash_tendered", idx), t.CashTendered, t.Amount); err != nil {
+				return nil, err
+			}
+
+			pt.CashTendered = t.CashTendered.Amount
+		}
+
+		req = append(req, pt)
+	}
+
+	if sum != total {
+		what := "the order total"
+		if o.Tip != nil {
+			what = "the order total plus the tip"
+		}
+
+		return nil, &ValidationError{
+			Field:  "tenders",
+			Reason: fmt.Sprintf("must add up to %s of %s, got %s", what, total, sum),
+		}
+	}
+
+	// the tip is charged to the last tender, which must cover it
+	if tip := o.TipAmount(); !tip.IsZero() && req[len(req)-1].Amount <= tip.Amount {
+		return nil, &ValidationError{
+			Field:  fmt.Sprintf("tenders[%d].amount", len(req)-1),
+			Reason: fmt.Sprintf("must be more than the tip of %s", tip),
+		}
+	}
+
+	return req, nil
+}
+
+// newPaymentRequest builds the payment request for the order total, its items, discount and
+// taxes. The tip is sent apart from the total, so it's reported apart from the sales.
+func newPaymentRequest(o *Order, method string) *pb.PaymentRequest {
+	total := o.Total()
+
+	req := &pb.PaymentRequest{
+		OrderID:  o.ID.String(),
+		Method:   method,
+		Amount:   total.Amount,
+		Currency: total.Currency,
+		Items:    make([]*pb.LineItem, 0, len(o.Items)),
+		Discount: o.DiscountTotal().Amount,
+		Tax:      o.TaxTotal().Amount,
+	}
+	if o.Tip != nil {
+		req.Tip, req.Barista = o.Tip.Amount.Amount, o.Tip.Barista
+	}
+
+	for _, i := range o.Items {
+		li := &pb.LineItem{
+			Name:        i.Name,
+			ServingSize: i.ServingSize,
+			Qty:         int32(i.Qty),
+			UnitAmount:  i.UnitPrice().Amount,
+		}
+		for _, m := range i.Modifiers {
+			li.Modifiers = append(li.Modifiers, m.Name)
+		}
+
+		req.Items = append(req.Items, li)
+	}
+
+	return req
+}
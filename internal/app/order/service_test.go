@@ -0,0 +1,1023 @@
+package order
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/catalog"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/italolelis/coffee-shop/internal/pkg/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAddToOrderCommand_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		cmd            AddToOrderCommand
+		expectedFields []string
+	}{
+		{
+			name: "valid customer cart",
+			cmd: AddToOrderCommand{
+				CustomerName: "anna",
+				Items:        []ItemRequest{{Name: "latte", ServingSize: "M", Qty: 1}},
+			},
+		},
+		{
+			name: "valid explicit order",
+			cmd: AddToOrderCommand{
+				OrderID: uuid.New(),
+				Items:   []ItemRequest{{Name: "latte", ServingSize: "M", Qty: 1}},
+			},
+		},
+		{
+			name:           "missing order and items",
+			cmd:            AddToOrderCommand{},
+			expectedFields: []string{"customer_name", "items"},
+		},
+		{
+			name: "invalid items",
+			cmd: AddToOrderCommand{
+				CustomerName: "anna",
+				Items: []ItemRequest{
+					{Name: "latte", ServingSize: "M", Qty: 1},
+					{Name: "", ServingSize: "", Qty: -1},
+				},
+			},
+			expectedFields: []string{"items[1].name", "items[1].serving_size", "items[1].qty"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.cmd.Validate()
+			if len(tt.expectedFields) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var verrs ValidationErrors
+			require.True(t, errors.As(err, &verrs))
+
+			fields := make([]string, 0, len(verrs))
+			for _, verr := range verrs {
+				fields = append(fields, verr.Field)
+			}
+			assert.Equal(t, tt.expectedFields, fields)
+		})
+	}
+}
+
+// fakeCatalog serves the products of the default menu
+type fakeCatalog map[string]*catalog.Product
+
+func newFakeCatalog() fakeCatalog {
+	c := make(fakeCatalog)
+	for _, p := range catalog.DefaultMenu() {
+		c[p.Name] = p
+	}
+
+	return c
+}
+
+func (c fakeCatalog) FetchByName(_ context.Context, name string) (*catalog.Product, error) {
+	p, ok := c[name]
+	if !ok {
+		return nil, catalog.ErrProductNotFound
+	}
+
+	return p, nil
+}
+
+func TestServiceImp_AddToOrder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		items          []ItemRequest
+		expectedErr    error
+		expectedLines  int
+		expectedTotal  money.Money
+		expectedFields []string
+	}{
+		{
+			name: "price stamped from the catalog",
+			items: []ItemRequest{
+				{Name: "latte", ServingSize: "M", Qty: 2},
+				{Name: "espresso", ServingSize: "S", Qty: 1},
+			},
+			expectedLines: 2,
+			expectedTotal: money.New(700, "EUR"),
+		},
+		{
+			name: "unknown product",
+			items: []ItemRequest{
+				{Name: "latte", ServingSize: "M", Qty: 1},
+				{Name: "mocha", ServingSize: "M", Qty: 1},
+			},
+			expectedErr:    catalog.ErrProductNotFound,
+			expectedFields: []string{"items[1].name"},
+		},
+		{
+			name: "unknown size",
+			items: []ItemRequest{
+				{Name: "espresso", ServingSize: "L", Qty: 1},
+			},
+			expectedErr:    catalog.ErrSizeNotAvailable,
+			expectedFields: []string{"items[0].serving_size"},
+		},
+		{
+			name: "modifiers priced from the catalog",
+			items: []ItemRequest{
+				{Name: "latte", ServingSize: "M", Qty: 2, Modifiers: []string{"oat milk", "extra shot", "extra shot"}},
+			},
+			expectedLines: 1,
+			expectedTotal: money.New(800, "EUR"),
+		},
+		{
+			name: "customized items are separate lines",
+			items: []ItemRequest{
+				{Name: "latte", ServingSize: "M", Qty: 1},
+				{Name: "latte", ServingSize: "M", Qty: 1, Modifiers: []string{"oat milk"}},
+				{Name: "latte", ServingSize: "M", Qty: 1, Modifiers: []string{"oat milk"}},
+			},
+			expectedLines: 2,
+			expectedTotal: money.New(860, "EUR"),
+		},
+		{
+			name: "modifier not offered",
+			items: []ItemRequest{
+				{Name: "espresso", ServingSize: "S", Qty: 1, Modifiers: []string{"oat milk"}},
+			},
+			expectedErr:    catalog.ErrModifierNotAvailable,
+			expectedFields: []string{"items[0].modifiers"},
+		},
+		{
+			name: "two milks",
+			items: []ItemRequest{
+				{Name: "latte", ServingSize: "M", Qty: 1, Modifiers: []string{"oat milk", "soy milk"}},
+			},
+			expectedErr:    catalog.ErrConflictingModifiers,
+			expectedFields: []string{"items[0].modifiers"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := newFakeStore()
+			s := NewService(store, store, newFakeCatalog(), nil, nil, nil, nil, nil)
+
+			id, err := s.AddToOrder(context.Background(), AddToOrderCommand{CustomerName: "anna", Items: tt.items})
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+
+				var verrs ValidationErrors
+				require.True(t, errors.As(err, &verrs))
+				require.Len(t, verrs, len(tt.expectedFields))
+				for idx, field := range tt.expectedFields {
+					assert.Equal(t, field, verrs[idx].Field)
+				}
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, store.orders[id].Items, tt.expectedLines)
+			assert.Equal(t, tt.expectedTotal, store.orders[id].Total())
+		})
+	}
+}
+
+func TestServiceImp_AddToOrderIgnoresClientPrice(t *testing.T) {
+	t.Parallel()
+
+	var cmd AddToOrderCommand
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"customer_name": "anna",
+		"items": [{"name": "latte", "serving_size": "M", "qty": 1, "price": {"amount": "0.01", "currency": "EUR"}}]
+	}`), &cmd))
+
+	store := newFakeStore()
+	s := NewService(store, store, newFakeCatalog(), nil, nil, nil, nil, nil)
+
+	id, err := s.AddToOrder(context.Background(), cmd)
+	require.NoError(t, err)
+	require.Len(t, store.orders[id].Items, 1)
+	assert.Equal(t, money.New(260, "EUR"), store.orders[id].Items[0].Price)
+	assert.Equal(t, money.New(260, "EUR"), store.orders[id].Total())
+}
+
+func TestServiceImp_UpdateItemQty(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newFakeStore()
+	s := NewService(store, store, newFakeCatalog(), nil, nil, nil, nil, nil)
+
+	id, err := s.AddToOrder(ctx, AddToOrderCommand{CustomerName: "anna", Items: []ItemRequest{
+		{Name: "latte", ServingSize: "M", Qty: 2},
+		{Name: "espresso", ServingSize: "S", Qty: 1},
+	}})
+	require.NoError(t, err)
+	latte, espresso := store.orders[id].Items[0].ID, store.orders[id].Items[1].ID
+
+	o, err := s.UpdateItemQty(ctx, UpdateItemQtyCommand{OrderID: id, LineID: latte, Qty: 3})
+	require.NoError(t, err)
+	assert.Equal(t, money.New(960, "EUR"), o.Total())
+
+	o, err = s.RemoveItem(ctx, RemoveItemCommand{OrderID: id, LineID: espresso})
+	require.NoError(t, err)
+	assert.Equal(t, money.New(780, "EUR"), store.orders[id].Total())
+	assert.Len(t, o.Items, 1)
+
+	_, err = s.UpdateItemQty(ctx, UpdateItemQtyCommand{OrderID: id, LineID: latte, Qty: -1})
+	var verrs ValidationErrors
+	assert.True(t, errors.As(err, &verrs))
+
+	_, err = s.RemoveItem(ctx, RemoveItemCommand{OrderID: uuid.New(), LineID: latte})
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+// fakePromotions takes 10% off every order, and 1.00 more off orders with the SAVE1 coupon
+type fakePromotions struct {
+	redeemed []string
+	released []string
+}
+
+func (p *fakePromotions) Discounts(_ context.Context, o *Order) (Discounts, error) {
+	discounts := Discounts{{Promotion: "ten percent", Amount: o.Subtotal().MulFrac(10, 100, money.RoundHalfUp)}}
+
+	switch o.Coupon {
+	case "":
+	case "SAVE1":
+		discounts = append(discounts, &Discount{Promotion: "one off", Code: o.Coupon, Amount: money.New(100, o.Currency())})
+	default:
+		return nil, fmt.Errorf("%w: not found", ErrInvalidCoupon)
+	}
+
+	return discounts, nil
+}
+
+func (p *fakePromotions) Redeem(_ context.Context, code string) error {
+	p.redeemed = append(p.redeemed, code)
+	return nil
+}
+
+func (p *fakePromotions) Release(_ context.Context, code string) error {
+	p.released = append(p.released, code)
+	return nil
+}
+
+func TestServiceImp_Promotions(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = context.Background()
+		store    = newFakeStore()
+		pr       = &fakePromotions{}
+		requests = make(map[string]*pb.PaymentRequest)
+		declined = make(map[string]bool)
+	)
+
+	pc := &fakePaymentClient{authorize: func(r *pb.PaymentRequest) (*pb.PaymentConfirmation, error) {
+		if declined[r.OrderID] {
+			return nil, status.Error(codes.FailedPrecondition, "card expired")
+		}
+
+		requests[r.OrderID] = r
+		return &pb.PaymentConfirmation{ID: uuid.New().String(), Status: "authorized"}, nil
+	}}
+	co := NewCheckoutOrchestrator(store, store, pc, store, fakeSagaWriter{store})
+	s := NewService(store, store, newFakeCatalog(), pc, nil, co, pr, nil)
+
+	newOrder := func(customer string) uuid.UUID {
+		id, err := s.AddToOrder(ctx, AddToOrderCommand{CustomerName: customer, Items: []ItemRequest{
+			{Name: "latte", ServingSize: "M", Qty: 2},
+		}})
+		require.NoError(t, err)
+
+		return id
+	}
+
+	id := newOrder("anna")
+	assert.Equal(t, money.New(52, "EUR"), store.orders[id].DiscountTotal())
+	assert.Equal(t, money.New(468, "EUR"), store.orders[id].Total())
+
+	_, err := s.ApplyCoupon(ctx, ApplyCouponCommand{OrderID: id, Coupon: "nope"})
+	assert.True(t, errors.Is(err, ErrInvalidCoupon))
+	var verr *ValidationError
+	require.True(t, errors.As(err, &verr))
+	assert.Equal(t, "coupon", verr.Field)
+
+	o, err := s.ApplyCoupon(ctx, ApplyCouponCommand{OrderID: id, Coupon: "save1"})
+	require.NoError(t, err)
+	assert.Equal(t, "SAVE1", o.Coupon)
+	assert.Equal(t, money.New(368, "EUR"), o.Total())
+
+	// discounts follow the items of the order
+	o, err = s.UpdateItemQty(ctx, UpdateItemQtyCommand{OrderID: id, LineID: o.Items[0].ID, Qty: 1})
+	require.NoError(t, err)
+	assert.Equal(t, money.New(134, "EUR"), o.Total())
+
+	_, err = s.Checkout(ctx, CheckoutCommand{OrderID: id, PaymentMethod: "credit_card"})
+	require.NoError(t, err)
+	req := requests[id.String()]
+	require.NotNil(t, req)
+	assert.Equal(t, int64(134), req.Amount)
+	assert.Equal(t, int64(126), req.Discount)
+	assert.Equal(t, []string{"SAVE1"}, pr.redeemed)
+	assert.Empty(t, pr.released)
+
+	// a failed checkout gives the coupon use back
+	id = newOrder("ben")
+	declined[id.String()] = true
+	_, err = s.ApplyCoupon(ctx, ApplyCouponCommand{OrderID: id, Coupon: "SAVE1"})
+	require.NoError(t, err)
+
+	_, err = s.Checkout(ctx, CheckoutCommand{OrderID: id, PaymentMethod: "credit_card"})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"SAVE1", "SAVE1"}, pr.redeemed)
+	assert.Equal(t, []string{"SAVE1"}, pr.released)
+}
+
+// fakeTaxes adds 10% to take-away orders, on top of the discounted items
+type fakeTaxes struct{}
+
+func (fakeTaxes) Taxes(_ context.Context, o *Order) (Taxes, error) {
+	if o.Store != "take-away" {
+		return nil, nil
+	}
+
+	base := o.Subtotal()
+	base.Amount -= o.DiscountTotal().Amount
+
+	return Taxes{{Name: "Tax 10%", Rate: 1000, Base: base, Amount: base.MulFrac(10, 100, money.RoundHalfUp)}}, nil
+}
+
+func TestServiceImp_Taxes(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx   = context.Background()
+		store = newFakeStore()
+		req   *pb.PaymentRequest
+	)
+
+	pc := &fakePaymentClient{authorize: func(r *pb.PaymentRequest) (*pb.PaymentConfirmation, error) {
+		req = r
+		return &pb.PaymentConfirmation{ID: uuid.New().String(), Status: "authorized"}, nil
+	}}
+	co := NewCheckoutOrchestrator(store, store, pc, store, fakeSagaWriter{store})
+	s := NewService(store, store, newFakeCatalog(), pc, nil, co, &fakePromotions{}, fakeTaxes{})
+
+	id, err := s.AddToOrder(ctx, AddToOrderCommand{CustomerName: "anna", Store: "take-away", Items: []ItemRequest{
+		{Name: "latte", ServingSize: "M", Qty: 2},
+	}})
+	require.NoError(t, err)
+
+	o := store.orders[id]
+	assert.Equal(t, "take-away", o.Store)
+	assert.Equal(t, "drinks", o.Items[0].Category)
+	// 5.20 minus the 10% discount, plus 10% tax
+	assert.Equal(t, money.New(47, "EUR"), o.TaxTotal())
+	assert.Equal(t, money.New(515, "EUR"), o.Total())
+
+	_, err = s.Checkout(ctx, CheckoutCommand{OrderID: id, PaymentMethod: "credit_card"})
+	require.NoError(t, err)
+	require.NotNil(t, req)
+	assert.Equal(t, int64(515), req.Amount)
+	assert.Equal(t, int64(52), req.Discount)
+	assert.Equal(t, int64(47), req.Tax)
+}
+
+func TestCheckoutCommand_Validate(t *testing.T) {
+	t.Parallel()
+
+	tendered := money.New(1000, "EUR")
+
+	tests := []struct {
+		name           string
+		cmd            CheckoutCommand
+		expectedFields []string
+	}{
+		{
+			name: "valid",
+			cmd:  CheckoutCommand{CustomerName: "anna", PaymentMethod: "credit_card"},
+		},
+		{
+			name: "valid gift card",
+			cmd:  CheckoutCommand{CustomerName: "anna", PaymentMethod: PaymentMethodGiftCard, GiftCardNumber: "1234"},
+		},
+		{
+			name:           "missing order and method",
+			cmd:            CheckoutCommand{},
+			expectedFields: []string{"customer_name", "payment_method"},
+		},
+		{
+			name: "valid tenders",
+			cmd: CheckoutCommand{CustomerName: "anna", Tenders: []Tender{
+				{PaymentMethod: PaymentMethodGiftCard, Amount: money.New(200, "EUR"), GiftCardNumber: "1234"},
+				{PaymentMethod: "credit_card", Amount: money.New(60, "EUR")},
+			}},
+		},
+		{
+			name: "method and tenders",
+			cmd: CheckoutCommand{CustomerName: "anna", PaymentMethod: "credit_card", Tenders: []Tender{
+				{PaymentMethod: "credit_card", Amount: money.New(260, "EUR")},
+			}},
+			expectedFields: []string{"payment_method"},
+		},
+		{
+			name: "invalid tender",
+			cmd: CheckoutCommand{CustomerName: "anna", Tenders: []Tender{
+				{PaymentMethod: PaymentMethodGiftCard, Amount: money.New(0, "EUR")},
+			}},
+			expectedFields: []string{"tenders[0].amount", "tenders[0].gift_card_number"},
+		},
+		{
+			name:           "gift card without number",
+			cmd:            CheckoutCommand{OrderID: uuid.New(), PaymentMethod: PaymentMethodGiftCard},
+			expectedFields: []string{"gift_card_number"},
+		},
+		{
+			name: "valid cash",
+			cmd:  CheckoutCommand{CustomerName: "anna", PaymentMethod: PaymentMethodCash, CashTendered: &tendered},
+		},
+		{
+			name:           "cash without tendered amount",
+			cmd:            CheckoutCommand{CustomerName: "anna", PaymentMethod: PaymentMethodCash},
+			expectedFields: []string{"cash_tendered"},
+		},
+		{
+			name: "cash tender without tendered amount",
+			cmd: CheckoutCommand{CustomerName: "anna", Tenders: []Tender{
+				{PaymentMethod: PaymentMethodCash, Amount: money.New(200, "EUR")},
+			}},
+			expectedFields: []string{"tenders[0].cash_tendered"},
+		},
+		{
+			name: "valid tip",
+			cmd:  CheckoutCommand{CustomerName: "anna", PaymentMethod: "credit_card", Tip: &TipRequest{Percent: 10}, Barista: "ben"},
+		},
+		{
+			name:           "tip amount and percentage",
+			cmd:            CheckoutCommand{CustomerName: "anna", PaymentMethod: "credit_card", Tip: &TipRequest{Amount: &tendered, Percent: 10}},
+			expectedFields: []string{"tip"},
+		},
+		{
+			name:           "empty tip",
+			cmd:            CheckoutCommand{CustomerName: "anna", PaymentMethod: "credit_card", Tip: &TipRequest{}},
+			expectedFields: []string{"tip.percent"},
+		},
+		{
+			name:           "tip percentage too high",
+			cmd:            CheckoutCommand{CustomerName: "anna", PaymentMethod: "credit_card", Tip: &TipRequest{Percent: 150}},
+			expectedFields: []string{"tip.percent"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.cmd.Validate()
+			if len(tt.expectedFields) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var verrs ValidationErrors
+			require.True(t, errors.As(err, &verrs))
+
+			fields := make([]string, 0, len(verrs))
+			for _, verr := range verrs {
+				fields = append(fields, verr.Field)
+			}
+			assert.Equal(t, tt.expectedFields, fields)
+		})
+	}
+}
+
+func TestServiceImp_CheckoutTip(t *testing.T) {
+	t.Parallel()
+
+	var (
+		fixed    = money.New(100, "EUR")
+		dollars  = money.New(100, "USD")
+		tendered = money.New(600, "EUR")
+	)
+
+	tests := []struct {
+		name          string
+		cmd           CheckoutCommand
+		expectedErr   string
+		expectedTip   int64
+		expectedTotal money.Money
+	}{
+		{
+			name:          "percentage of the subtotal",
+			cmd:           CheckoutCommand{PaymentMethod: "credit_card", Tip: &TipRequest{Percent: 15}, Barista: "ben"},
+			expectedTip:   78,
+			expectedTotal: money.New(598, "EUR"),
+		},
+		{
+			name:          "fixed amount",
+			cmd:           CheckoutCommand{PaymentMethod: "credit_card", Tip: &TipRequest{Amount: &fixed}, Barista: "ben"},
+			expectedTip:   100,
+			expectedTotal: money.New(620, "EUR"),
+		},
+		{
+			name:          "no tip",
+			cmd:           CheckoutCommand{PaymentMethod: "credit_card"},
+			expectedTotal: money.New(520, "EUR"),
+		},
+		{
+			name:        "fixed amount in another currency",
+			cmd:         CheckoutCommand{PaymentMethod: "credit_card", Tip: &TipRequest{Amount: &dollars}},
+			expectedErr: "tip.amount",
+		},
+		{
+			name:        "cash doesn't cover the tip",
+			cmd:         CheckoutCommand{PaymentMethod: PaymentMethodCash, CashTendered: &tendered, Tip: &TipRequest{Amount: &fixed}},
+			expectedErr: "cash_tendered",
+		},
+		{
+			name: "tenders don't cover the tip",
+			cmd: CheckoutCommand{Tip: &TipRequest{Amount: &fixed}, Tenders: []Tender{
+				{PaymentMethod: "credit_card", Amount: money.New(300, "EUR")},
+				{PaymentMethod: "apple_pay", Amount: money.New(220, "EUR")},
+			}},
+			expectedErr: "tenders",
+		},
+		{
+			name: "last tender doesn't cover the tip",
+			cmd: CheckoutCommand{Tip: &TipRequest{Amount: &fixed}, Tenders: []Tender{
+				{PaymentMethod: "credit_card", Amount: money.New(560, "EUR")},
+				{PaymentMethod: "apple_pay", Amount: money.New(60, "EUR")},
+			}},
+			expectedErr: "tenders[1].amount",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				ctx   = context.Background()
+				store = newFakeStore()
+				req   *pb.PaymentRequest
+			)
+
+			pc := &fakePaymentClient{authorize: func(r *pb.PaymentRequest) (*pb.PaymentConfirmation, error) {
+				req = r
+				return &pb.PaymentConfirmation{ID: uuid.New().String(), Status: "authorized"}, nil
+			}}
+			co := NewCheckoutOrchestrator(store, store, pc, store, fakeSagaWriter{store})
+			s := NewService(store, store, newFakeCatalog(), pc, nil, co, nil, nil)
+
+			id, err := s.AddToOrder(ctx, AddToOrderCommand{CustomerName: "anna", Items: []ItemRequest{
+				{Name: "latte", ServingSize: "M", Qty: 2},
+			}})
+			require.NoError(t, err)
+
+			tt.cmd.OrderID = id
+			_, err = s.Checkout(ctx, tt.cmd)
+			if tt.expectedErr != "" {
+				var verr *ValidationError
+				require.True(t, errors.As(err, &verr), "got %v", err)
+				assert.Equal(t, tt.expectedErr, verr.Field)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, req)
+			assert.Equal(t, int64(520), req.Amount)
+			assert.Equal(t, tt.expectedTip, req.Tip)
+			assert.Equal(t, tt.cmd.Barista, req.Barista)
+
+			o := store.orders[id]
+			assert.Equal(t, money.New(520, "EUR"), o.Total())
+			assert.Equal(t, tt.expectedTotal, o.TotalWithTip())
+		})
+	}
+}
+
+func TestServiceImp_TipReport(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		shiftID = uuid.New()
+		to      = time.Now().UTC()
+		from    = to.Add(-8 * time.Hour)
+		req     *pb.TipReportRequest
+	)
+
+	pc := &fakePaymentClient{getTipReport: func(r *pb.TipReportRequest) (*pb.TipReport, error) {
+		req = r
+		return &pb.TipReport{Totals: []*pb.TipTotal{
+			{Barista: "ben", Amount: 100, Currency: "EUR", Payments: 1},
+			{Barista: "ben", ShiftID: shiftID.String(), Amount: 250, Currency: "EUR", Payments: 3},
+		}}, nil
+	}}
+	s := NewService(nil, nil, nil, pc, nil, nil, nil, nil)
+
+	totals, err := s.TipReport(ctx, TipReportQuery{From: from, To: to, Barista: "ben"})
+	require.NoError(t, err)
+	assert.Equal(t, []*TipTotal{
+		{Barista: "ben", Amount: money.New(100, "EUR"), Payments: 1},
+		{Barista: "ben", ShiftID: shiftID, Amount: money.New(250, "EUR"), Payments: 3},
+	}, totals)
+
+	require.NotNil(t, req)
+	assert.Equal(t, "ben", req.Barista)
+	assert.Equal(t, from.Unix(), req.From.Seconds)
+	assert.Equal(t, to.Unix(), req.To.Seconds)
+
+	_, err = s.TipReport(ctx, TipReportQuery{From: to, To: from})
+	var verrs ValidationErrors
+	require.True(t, errors.As(err, &verrs))
+	assert.Equal(t, "from", verrs[0].Field)
+}
+
+func TestClassifyPaymentError(t *testing.T) {
+	t.Parallel()
+
+	declined, err := status.New(codes.FailedPrecondition, "payment declined").
+		WithDetails(&errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{
+			{Type: pb.ViolationDeclined, Subject: "payment", Description: "insufficient funds"},
+		}})
+	require.NoError(t, err)
+
+	otherViolation, err := status.New(codes.FailedPrecondition, "not voidable").
+		WithDetails(&errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{
+			{Type: "STATE", Subject: "payment", Description: "payment is refunded"},
+		}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name              string
+		err               error
+		expectedKind      error
+		expectedReason    string
+		expectedRetryable bool
+	}{
+		{
+			name:              "payment service down",
+			err:               status.Error(codes.Unavailable, "connection refused"),
+			expectedKind:      ErrPaymentUnavailable,
+			expectedReason:    "connection refused",
+			expectedRetryable: true,
+		},
+		{
+			name:           "declined with reason",
+			err:            declined.Err(),
+			expectedKind:   ErrPaymentDeclined,
+			expectedReason: "insufficient funds",
+		},
+		{
+			name:           "unsupported method",
+			err:            status.Error(codes.InvalidArgument, "payment method not supported"),
+			expectedKind:   ErrPaymentInvalid,
+			expectedReason: "payment method not supported",
+		},
+		{
+			name:           "payment service fault",
+			err:            status.Error(codes.Internal, "disk full"),
+			expectedKind:   ErrPaymentServiceFault,
+			expectedReason: "disk full",
+		},
+		{
+			name:           "unknown failure",
+			err:            status.Error(codes.Unknown, "connection reset"),
+			expectedKind:   ErrPaymentServiceFault,
+			expectedReason: "connection reset",
+		},
+		{
+			name:           "method not implemented",
+			err:            status.Error(codes.Unimplemented, "unknown method Authorize"),
+			expectedKind:   ErrPaymentServiceFault,
+			expectedReason: "unknown method Authorize",
+		},
+		{
+			name:           "client not authenticated",
+			err:            status.Error(codes.Unauthenticated, "missing credentials"),
+			expectedKind:   ErrPaymentServiceFault,
+			expectedReason: "missing credentials",
+		},
+		{
+			name:           "not a gRPC error",
+			err:            errors.New("connection reset"),
+			expectedKind:   ErrPaymentServiceFault,
+			expectedReason: "connection reset",
+		},
+		{
+			name:           "payment not found",
+			err:            status.Error(codes.NotFound, "payment not found"),
+			expectedKind:   ErrPaymentFailed,
+			expectedReason: "payment not found",
+		},
+		{
+			name:           "authorization expired",
+			err:            status.Error(codes.FailedPrecondition, "authorization expired"),
+			expectedKind:   ErrPaymentConflict,
+			expectedReason: "authorization expired",
+		},
+		{
+			name:           "not capturable",
+			err:            status.Error(codes.FailedPrecondition, "payment can't be captured: payment is voided"),
+			expectedKind:   ErrPaymentConflict,
+			expectedReason: "payment can't be captured: payment is voided",
+		},
+		{
+			name:           "cash drawer closed",
+			err:            status.Error(codes.FailedPrecondition, "cash drawer is closed"),
+			expectedKind:   ErrPaymentConflict,
+			expectedReason: "cash drawer is closed",
+		},
+		{
+			name:           "precondition with another violation",
+			err:            otherViolation.Err(),
+			expectedKind:   ErrPaymentConflict,
+			expectedReason: "not voidable",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			perr := classifyPaymentError(tt.err)
+			assert.True(t, errors.Is(perr, ErrPaymentFailed))
+			assert.True(t, errors.Is(perr, tt.expectedKind))
+			assert.Equal(t, tt.expectedReason, perr.Reason)
+			assert.Equal(t, tt.expectedRetryable, perr.Retryable)
+		})
+	}
+}
+
+func TestServiceImp_Refund(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		amount         *money.Money
+		refundErr      error
+		paymentStatus  string
+		expectedErr    error
+		expectedStatus Status
+	}{
+		{
+			name:           "full refund",
+			paymentStatus:  "refunded",
+			expectedStatus: StatusRefunded,
+		},
+		{
+			name:           "partial refund",
+			amount:         &money.Money{Amount: 100, Currency: "EUR"},
+			paymentStatus:  "partially_refunded",
+			expectedStatus: StatusPaid,
+		},
+		{
+			name:        "refund in another currency",
+			amount:      &money.Money{Amount: 100, Currency: "USD"},
+			expectedErr: money.ErrCurrencyMismatch,
+		},
+		{
+			name:        "refund rejected",
+			amount:      &money.Money{Amount: 10000, Currency: "EUR"},
+			refundErr:   status.Error(codes.FailedPrecondition, "refund exceeds the payment amount"),
+			expectedErr: ErrRefundRejected,
+		},
+		{
+			name:        "payment service down",
+			refundErr:   status.Error(codes.Unavailable, "connection refused"),
+			expectedErr: ErrPaymentUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			o := newCheckedOutOrder(t)
+			require.NoError(t, o.MarkPaid(uuid.New()))
+
+			store := newFakeStore()
+			store.orders[o.ID] = o
+
+			pc := &fakePaymentClient{refund: func(r *pb.RefundRequest) (*pb.RefundConfirmation, error) {
+				// every payment is refunded with its own key, derived from the key of the request
+				assert.Equal(t, "refund-key/"+r.ID, r.IdempotencyKey)
+
+				if tt.refundErr != nil {
+					return nil, tt.refundErr
+				}
+
+				return &pb.RefundConfirmation{
+					ID:         uuid.New().String(),
+					PaymentID:  r.ID,
+					OrderID:    o.ID.String(),
+					Amount:     r.Amount,
+					Currency:   r.Currency,
+					RefundedAt: ptypes.TimestampNow(),
+					Payment:    &pb.PaymentConfirmation{ID: r.ID, Status: tt.paymentStatus},
+				}, nil
+			}}
+
+			s := NewService(store, store, nil, pc, nil, nil, nil, nil)
+			_, err := s.Refund(context.Background(), RefundCommand{OrderID: o.ID, Amount: tt.amount, IdempotencyKey: "refund-key"})
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, store.orders[o.ID].Status)
+		})
+	}
+}
+
+func TestServiceImp_RefundSplit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		amount          *money.Money
+		creditCard      *pb.PaymentConfirmation
+		expectedErr     error
+		expectedField   string
+		expectedRefunds map[string]int64
+		expectedStatus  Status
+	}{
+		{
+			name:            "full refund",
+			creditCard:      &pb.PaymentConfirmation{Amount: 60, Currency: "EUR", Status: "captured"},
+			expectedRefunds: map[string]int64{"gift_card": 0, "credit_card": 0},
+			expectedStatus:  StatusRefunded,
+		},
+		{
+			name:            "partial refund starts from the last tender",
+			amount:          &money.Money{Amount: 100, Currency: "EUR"},
+			creditCard:      &pb.PaymentConfirmation{Amount: 60, Currency: "EUR", Status: "captured"},
+			expectedRefunds: map[string]int64{"credit_card": 60, "gift_card": 40},
+			expectedStatus:  StatusPaid,
+		},
+		{
+			name:          "partial refund of an authorized tender",
+			amount:        &money.Money{Amount: 30, Currency: "EUR"},
+			creditCard:    &pb.PaymentConfirmation{Amount: 60, Currency: "EUR", Status: "authorized"},
+			expectedField: "amount",
+		},
+		{
+			name:        "more than what is left",
+			amount:      &money.Money{Amount: 250, Currency: "EUR"},
+			creditCard:  &pb.PaymentConfirmation{Amount: 60, Refunded: 20, Currency: "EUR", Status: "partially_refunded"},
+			expectedErr: ErrRefundRejected,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			giftCard, creditCard := uuid.New(), uuid.New()
+			o := newCheckedOutOrder(t)
+			require.NoError(t, o.MarkPaid(giftCard, creditCard))
+
+			store := newFakeStore()
+			store.orders[o.ID] = o
+
+			tt.creditCard.ID, tt.creditCard.Method = creditCard.String(), "credit_card"
+			confirmations := map[string]*pb.PaymentConfirmation{
+				giftCard.String():   {ID: giftCard.String(), Method: "gift_card", Amount: 200, Currency: "EUR", Status: "captured"},
+				creditCard.String(): tt.creditCard,
+			}
+
+			refunds := make(map[string]int64)
+			pc := &fakePaymentClient{confirmations: confirmations, refund: func(r *pb.RefundRequest) (*pb.RefundConfirmation, error) {
+				c := confirmations[r.ID]
+				refunds[c.Method] = r.Amount
+
+				status := "partially_refunded"
+				if r.Amount == 0 || r.Amount == c.Amount-c.Refunded {
+					status = "refunded"
+				}
+
+				return &pb.RefundConfirmation{
+					ID:         uuid.New().String(),
+					PaymentID:  r.ID,
+					OrderID:    o.ID.String(),
+					Amount:     r.Amount,
+					Currency:   r.Currency,
+					RefundedAt: ptypes.TimestampNow(),
+					Payment:    &pb.PaymentConfirmation{ID: r.ID, Status: status},
+				}, nil
+			}}
+
+			s := NewService(store, store, nil, pc, nil, nil, nil, nil)
+			rfs, err := s.Refund(context.Background(), RefundCommand{OrderID: o.ID, Amount: tt.amount})
+			if tt.expectedField != "" {
+				var verr *ValidationError
+				require.True(t, errors.As(err, &verr))
+				assert.Equal(t, tt.expectedField, verr.Field)
+				assert.Empty(t, refunds)
+				return
+			}
+
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+				assert.Empty(t, refunds)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, rfs, len(tt.expectedRefunds))
+			assert.Equal(t, tt.expectedRefunds, refunds)
+			assert.Equal(t, tt.expectedStatus, store.orders[o.ID].Status)
+		})
+	}
+}
+
+func TestServiceImp_UpdateStatus(t *testing.T) {
+	t.Parallel()
+
+	var verrs ValidationErrors
+	err := UpdateStatusCommand{OrderID: uuid.New(), Status: StatusRefunded}.Validate()
+	require.True(t, errors.As(err, &verrs))
+	assert.Equal(t, "status", verrs[0].Field)
+
+	tests := []struct {
+		name             string
+		from             Status
+		to               Status
+		captureErr       error
+		expectedErr      error
+		expectedStatus   Status
+		expectedCaptures int
+	}{
+		{name: "start preparing", from: StatusPaid, to: StatusPreparing, expectedStatus: StatusPreparing},
+		{name: "ready", from: StatusPreparing, to: StatusReady, expectedStatus: StatusReady},
+		{name: "pick up captures the payment", from: StatusReady, to: StatusPickedUp, expectedStatus: StatusPickedUp, expectedCaptures: 1},
+		{
+			name:             "authorization expired",
+			from:             StatusReady,
+			to:               StatusPickedUp,
+			captureErr:       status.Error(codes.FailedPrecondition, "authorization expired"),
+			expectedErr:      ErrPaymentConflict,
+			expectedStatus:   StatusReady,
+			expectedCaptures: 1,
+		},
+		{name: "pick up before ready", from: StatusPreparing, to: StatusPickedUp, expectedErr: ErrInvalidTransition, expectedStatus: StatusPreparing},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			o := newCheckedOutOrder(t)
+			require.NoError(t, o.MarkPaid(uuid.New()))
+			o.Status = tt.from
+
+			store := newFakeStore()
+			store.orders[o.ID] = o
+
+			var captures int
+			pc := &fakePaymentClient{capture: func(r *pb.CaptureRequest) (*pb.PaymentConfirmation, error) {
+				captures++
+				assert.Equal(t, o.PaymentID.String(), r.ID)
+
+				return &pb.PaymentConfirmation{ID: r.ID, Status: "captured"}, tt.captureErr
+			}}
+
+			s := NewService(store, store, nil, pc, nil, nil, nil, nil)
+			_, err := s.UpdateStatus(context.Background(), UpdateStatusCommand{OrderID: o.ID, Status: tt.to})
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr), err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.expectedStatus, store.orders[o.ID].Status)
+			assert.Equal(t, tt.expectedStatus == StatusPickedUp, store.orders[o.ID].PaymentCaptured)
+			assert.Equal(t, tt.expectedCaptures, captures)
+		})
+	}
+}
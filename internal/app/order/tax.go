@@ -0,0 +1,100 @@
+package order
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+)
+
+// TaxCalculator works out the taxes of orders
+type TaxCalculator interface {
+	// Taxes returns the tax lines of the order, worked out on the discounted items
+	Taxes(ctx context.Context, o *Order) (Taxes, error)
+}
+
+type (
+	// TaxLine is the tax charged at a single rate on part of the order
+	TaxLine struct {
+		Name string `json:"name"`
+		// Rate is in basis points, e.g. 1900 is 19%
+		Rate int64 `json:"rate"`
+		// Inclusive tells if the tax is already part of the item prices, exclusive
+		// taxes are added on top of them
+		Inclusive bool        `json:"inclusive"`
+		Base      money.Money `json:"base"`
+		Amount    money.Money `json:"amount"`
+	}
+
+	Taxes []*TaxLine
+)
+
+// ApplyTaxes replaces the tax lines of the order
+func (o *Order) ApplyTaxes(taxes Taxes) error {
+	if o.Status != StatusOpen && o.Status != StatusCheckedOut {
+		return fmt.Errorf("can't tax a %s order: %w", o.Status, ErrNotOpen)
+	}
+
+	currency := o.Currency()
+	for _, t := range taxes {
+		if t.Amount.Currency != currency || t.Base.Currency != currency {
+			return fmt.Errorf("%w: tax %q is in %s, the order in %s", money.ErrCurrencyMismatch, t.Name, t.Amount.Currency, currency)
+		}
+
+		if t.Amount.IsNegative() {
+			return fmt.Errorf("tax %q can't be negative, got %s", t.Name, t.Amount)
+		}
+	}
+
+	o.Taxes = taxes
+
+	return nil
+}
+
+// TaxTotal sums the taxes added on top of the item prices, which is what the
+// taxes add to the order total
+func (o *Order) TaxTotal() money.Money {
+	tax := money.New(0, o.Currency())
+	for _, t := range o.Taxes {
+		if !t.Inclusive {
+			tax.Amount += t.Amount.Amount
+		}
+	}
+
+	return tax
+}
+
+// TaxIncluded sums the taxes that are part of the item prices
+func (o *Order) TaxIncluded() money.Money {
+	tax := money.New(0, o.Currency())
+	for _, t := range o.Taxes {
+		if t.Inclusive {
+			tax.Amount += t.Amount.Amount
+		}
+	}
+
+	return tax
+}
+
+// Value return a driver.Value representation of the tax lines
+func (p Taxes) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(p)
+}
+
+// Scan scans a database json representation into Taxes
+func (p *Taxes) Scan(src interface{}) error {
+	v := reflect.ValueOf(src)
+	if !v.IsValid() || v.IsNil() {
+		return nil
+	}
+	if data, ok := src.([]byte); ok {
+		return json.Unmarshal(data, &p)
+	}
+	return fmt.Errorf("could not not decode type %T -> %T", src, p)
+}
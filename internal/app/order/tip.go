@@ -0,0 +1,156 @@
+package order
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+)
+
+// MaxTipPercent is the largest tip that can be given as a percentage of the subtotal
+const MaxTipPercent = 100
+
+type (
+	// Tip is given by the customer on top of the order total. It's charged along with
+	// the order but reported apart from the sales.
+	Tip struct {
+		Amount money.Money `json:"amount"`
+		// Barista is who the tip goes to
+		Barista string `json:"barista,omitempty"`
+	}
+
+	// TipRequest is the tip the customer gives on checkout, either a fixed amount or
+	// a percentage of the subtotal of the order
+	TipRequest struct {
+		Amount  *money.Money `json:"amount,omitempty"`
+		Percent int64        `json:"percent,omitempty"`
+	}
+
+	// TipReportQuery sums the tips given in [From, To), only to the Barista when it's
+	// set. The payment service reports the last 24 hours when no period is given.
+	TipReportQuery struct {
+		From    time.Time
+		To      time.Time
+		Barista string
+	}
+
+	// TipTotal sums the tips a barista was given during a shift, which is the cash
+	// drawer session open when the tips were paid
+	TipTotal struct {
+		Barista string `json:"barista"`
+		// ShiftID is nil for tips given while no cash drawer was open
+		ShiftID  uuid.UUID   `json:"shift_id"`
+		Amount   money.Money `json:"amount"`
+		Payments int         `json:"payments"`
+	}
+)
+
+// Validate checks that the period of the query starts before it ends
+func (q TipReportQuery) Validate() error {
+	var errs ValidationErrors
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		errs = append(errs, &ValidationError{Field: "from", Reason: "must be before to"})
+	}
+
+	return errs.orNil()
+}
+
+// validate checks that the request is either a positive amount or a percentage up to MaxTipPercent
+func (r *TipRequest) validate() ValidationErrors {
+	var errs ValidationErrors
+
+	switch {
+	case r.Amount != nil && r.Percent != 0:
+		errs = append(errs, &ValidationError{Field: "tip", Reason: "must be either an amount or a percentage"})
+	case r.Amount != nil:
+		if r.Amount.IsZero() || r.Amount.IsNegative() {
+			errs = append(errs, &ValidationError{Field: "tip.amount", Reason: "must be positive"})
+		}
+	case r.Percent <= 0:
+		errs = append(errs, &ValidationError{Field: "tip.percent", Reason: "must be positive"})
+	case r.Percent > MaxTipPercent:
+		errs = append(errs, &ValidationError{Field: "tip.percent", Reason: fmt.Sprintf("can't be more than %d", MaxTipPercent)})
+	}
+
+	return errs
+}
+
+// amount works out the tip on the subtotal of the order, fixed amounts must be in its currency
+func (r *TipRequest) amount(subtotal money.Money) (money.Money, error) {
+	if r.Amount == nil {
+		return subtotal.MulFrac(r.Percent, 100, money.RoundHalfUp), nil
+	}
+
+	if !r.Amount.SameCurrency(subtotal) {
+		return money.Money{}, &ValidationError{
+			Field:  "tip.amount",
+			Reason: fmt.Sprintf("must be in %s", subtotal.Currency),
+			Err:    money.ErrCurrencyMismatch,
+		}
+	}
+
+	return *r.Amount, nil
+}
+
+// AddTip sets the tip of an order being checked out, replacing the tip of an earlier
+// checkout that failed. A nil tip removes it.
+func (o *Order) AddTip(t *Tip) error {
+	if o.Status != StatusOpen && o.Status != StatusCheckedOut {
+		return fmt.Errorf("can't tip a %s order: %w", o.Status, ErrNotOpen)
+	}
+
+	if t != nil {
+		if !t.Amount.SameCurrency(o.Total()) {
+			return fmt.Errorf("%w: tip is in %s, the order in %s", money.ErrCurrencyMismatch, t.Amount.Currency, o.Currency())
+		}
+
+		if t.Amount.IsNegative() {
+			return fmt.Errorf("tip can't be negative, got %s", t.Amount)
+		}
+	}
+
+	o.Tip = t
+
+	return nil
+}
+
+// TipAmount returns the tip of the order, zero when it wasn't tipped
+func (o *Order) TipAmount() money.Money {
+	if o.Tip == nil {
+		return money.New(0, o.Currency())
+	}
+
+	return o.Tip.Amount
+}
+
+// TotalWithTip is what the customer is charged, the order total plus the tip
+func (o *Order) TotalWithTip() money.Money {
+	total := o.Total()
+	total.Amount += o.TipAmount().Amount
+
+	return total
+}
+
+// Value return a driver.Value representation of the tip
+func (t *Tip) Value() (driver.Value, error) {
+	if t == nil {
+		return nil, nil
+	}
+	return json.Marshal(t)
+}
+
+// Scan scans a database json representation into a Tip
+func (t *Tip) Scan(src interface{}) error {
+	v := reflect.ValueOf(src)
+	if !v.IsValid() || v.IsNil() {
+		return nil
+	}
+	if data, ok := src.([]byte); ok {
+		return json.Unmarshal(data, t)
+	}
+	return fmt.Errorf("could not not decode type %T -> %T", src, t)
+}
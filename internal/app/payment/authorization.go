@@ -0,0 +1,103 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	StatusAuthorized Status = "authorized"
+	StatusReleased   Status = "released"
+	StatusExpired    Status = "expired"
+)
+
+var (
+	// ErrNotCapturable is returned when capturing a payment that isn't authorized
+	ErrNotCapturable = errors.New("payment can't be captured")
+	// ErrNotReleasable is returned when releasing a payment that isn't authorized
+	ErrNotReleasable = errors.New("authorization can't be released")
+	// ErrAuthorizationExpired is returned when capturing an authorization after it expired
+	ErrAuthorizationExpired = errors.New("authorization expired")
+)
+
+// NewAuthorization creates a confirmation for funds that are held, but not charged yet
+func NewAuthorization(method string, o OrderRequest, ttl time.Duration) *Confirmation {
+	c := NewConfirmation(method, o)
+	c.Status = StatusAuthorized
+	c.ExpiresAt = c.PayedAt.Add(ttl)
+
+	return c
+}
+
+// Capture charges the authorized funds. Capturing an already captured payment is a
+// no-op, so captures can be safely retried.
+func (c *Confirmation) Capture(now time.Time) error {
+	switch c.Status {
+	case StatusCaptured:
+		return nil
+	case StatusAuthorized:
+		if !now.Before(c.ExpiresAt) {
+			c.Status = StatusExpired
+			return ErrAuthorizationExpired
+		}
+
+		c.Status = StatusCaptured
+		c.PayedAt = now.UTC()
+
+		return nil
+	case StatusExpired:
+		return ErrAuthorizationExpired
+	default:
+		return fmt.Errorf("%w: payment is %s", ErrNotCapturable, c.Status)
+	}
+}
+
+// Release gives the held funds back without charging them. Releasing twice is a no-op.
+func (c *Confirmation) Release() error {
+	switch c.Status {
+	case StatusReleased:
+		return nil
+	case StatusAuthorized:
+		c.Status = StatusReleased
+		return nil
+	default:
+		return fmt.Errorf("%w: payment is %s", ErrNotReleasable, c.Status)
+	}
+}
+
+// Expire marks the authorization as expired when its expiry time passed
+func (c *Confirmation) Expire(now time.Time) bool {
+	if c.Status != StatusAuthorized || now.Before(c.ExpiresAt) {
+		return false
+	}
+
+	c.Status = StatusExpired
+
+	return true
+}
+
+// ExpireAuthorizations marks every authorization that expired before now as expired,
+// returning how many were expired
+func ExpireAuthorizations(ctx context.Context, r Reader, w Writer, now time.Time) (int, error) {
+	auths, err := r.FetchExpiredAuthorizations(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch expired authorizations: %w", err)
+	}
+
+	var expired int
+	for _, c := range auths {
+		if !c.Expire(now) {
+			continue
+		}
+
+		if err := w.Add(ctx, c); err != nil {
+			return expired, fmt.Errorf("failed to expire authorization %s: %w", c.ID, err)
+		}
+
+		expired++
+	}
+
+	return expired, nil
+}
@@ -0,0 +1,75 @@
+package payment
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmation_Capture(t *testing.T) {
+	t.Parallel()
+
+	o := OrderRequest{OrderID: uuid.New(), Total: money.New(780, "EUR")}
+
+	tests := []struct {
+		name           string
+		capturedAfter  time.Duration
+		expectedErr    error
+		expectedStatus Status
+	}{
+		{name: "capture before expiry", capturedAfter: time.Hour, expectedStatus: StatusCaptured},
+		{name: "capture after expiry", capturedAfter: 2 * time.Hour, expectedErr: ErrAuthorizationExpired, expectedStatus: StatusExpired},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := NewAuthorization("credit_card", o, 90*time.Minute)
+			require.Equal(t, StatusAuthorized, c.Status)
+
+			err := c.Capture(c.PayedAt.Add(tt.capturedAfter))
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.expectedStatus, c.Status)
+		})
+	}
+}
+
+func TestConfirmation_Release(t *testing.T) {
+	t.Parallel()
+
+	c := NewAuthorization("credit_card", OrderRequest{OrderID: uuid.New(), Total: money.New(780, "EUR")}, time.Hour)
+
+	require.NoError(t, c.Release())
+	assert.Equal(t, StatusReleased, c.Status)
+	assert.NoError(t, c.Release())
+	assert.True(t, errors.Is(c.Capture(time.Now()), ErrNotCapturable))
+
+	captured := NewConfirmation("credit_card", OrderRequest{OrderID: uuid.New(), Total: money.New(780, "EUR")})
+	assert.True(t, errors.Is(captured.Release(), ErrNotReleasable))
+	assert.NoError(t, captured.Capture(time.Now()))
+}
+
+func TestConfirmation_ApplyRefund_Authorized(t *testing.T) {
+	t.Parallel()
+
+	c := NewAuthorization("credit_card", OrderRequest{OrderID: uuid.New(), Total: money.New(780, "EUR")}, time.Hour)
+
+	_, err := c.ApplyRefund(RefundRequest{Amount: money.New(100, "EUR")})
+	assert.True(t, errors.Is(err, ErrInvalidRefund))
+
+	rf, err := c.ApplyRefund(RefundRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, money.New(780, "EUR"), rf.Amount)
+	assert.Equal(t, StatusReleased, c.Status)
+}
@@ -0,0 +1,232 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+)
+
+var (
+	// ErrDrawerClosed is returned when taking cash while no drawer session is open
+	ErrDrawerClosed = errors.New("cash drawer is closed")
+	// ErrDrawerAlreadyOpen is returned when opening a drawer session while another one is open
+	ErrDrawerAlreadyOpen = errors.New("cash drawer is already open")
+	ErrDrawerNotFound    = errors.New("cash drawer session not found")
+	// ErrInvalidCashAmount is returned when a cash amount is negative or not in the drawer currency
+	ErrInvalidCashAmount = errors.New("invalid cash amount")
+)
+
+// DrawerStatus is the state of a cash drawer session
+type DrawerStatus string
+
+const (
+	DrawerOpen   DrawerStatus = "open"
+	DrawerClosed DrawerStatus = "closed"
+)
+
+// DrawerSession tracks the cash in the drawer during a shift, from the float it's
+// opened with to the cash counted when it's closed. Only one session is open at a time.
+type DrawerSession struct {
+	ID     uuid.UUID    `json:"id" db:"id"`
+	Status DrawerStatus `json:"status" db:"status"`
+	// Float is the cash in the drawer when the session was opened
+	Float money.Money `json:"float" db:"float"`
+	// Sales is the cash taken for payments, net of the change given back
+	Sales money.Money `json:"sales" db:"sales"`
+	// PaidOut is the cash given back for refunds and voided payments
+	PaidOut money.Money `json:"paid_out" db:"paid_out"`
+	// Payments is how many cash payments were taken
+	Payments int `json:"payments" db:"payments"`
+	// Counted is the cash counted when the session was closed
+	Counted  money.Money `json:"counted" db:"counted"`
+	OpenedAt time.Time   `json:"opened_at" db:"opened_at"`
+	ClosedAt time.Time   `json:"closed_at" db:"closed_at"`
+}
+
+// OpenDrawer starts a drawer session with the given float
+func OpenDrawer(float money.Money) (*DrawerSession, error) {
+	if _, err := money.Exponent(float.Currency); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCashAmount, err)
+	}
+
+	if float.IsNegative() {
+		return nil, fmt.Errorf("%w: float can't be negative, got %s", ErrInvalidCashAmount, float)
+	}
+
+	zero := money.New(0, float.Currency)
+
+	return &DrawerSession{
+		ID:       uuid.New(),
+		Status:   DrawerOpen,
+		Float:    float,
+		Sales:    zero,
+		PaidOut:  zero,
+		Counted:  zero,
+		OpenedAt: time.Now().UTC(),
+	}, nil
+}
+
+// TakePayment records cash taken for a payment
+func (d *DrawerSession) TakePayment(amount money.Money) error {
+	if err := d.checkAmount(amount); err != nil {
+		return err
+	}
+
+	d.Sales = money.New(d.Sales.Amount+amount.Amount, d.Sales.Currency)
+	d.Payments++
+
+	return nil
+}
+
+// PayOut records cash given back to a customer
+func (d *DrawerSession) PayOut(amount money.Money) error {
+	if err := d.checkAmount(amount); err != nil {
+		return err
+	}
+
+	d.PaidOut = money.New(d.PaidOut.Amount+amount.Amount, d.PaidOut.Currency)
+
+	return nil
+}
+
+// Expected returns the cash that should be in the drawer
+func (d *DrawerSession) Expected() money.Money {
+	return money.New(d.Float.Amount+d.Sales.Amount-d.PaidOut.Amount, d.Float.Currency)
+}
+
+// Difference returns how much the counted cash is over, or when negative short of, the expected cash
+func (d *DrawerSession) Difference() money.Money {
+	return money.New(d.Counted.Amount-d.Expected().Amount, d.Float.Currency)
+}
+
+// Close ends the session with the cash counted in the drawer
+func (d *DrawerSession) Close(counted money.Money) error {
+	if d.Status != DrawerOpen {
+		return ErrDrawerClosed
+	}
+
+	if !counted.SameCurrency(d.Float) || counted.IsNegative() {
+		return fmt.Errorf("%w: counted cash must be a positive amount in %s, got %s", ErrInvalidCashAmount, d.Float.Currency, counted)
+	}
+
+	d.Counted = counted
+	d.Status = DrawerClosed
+	d.ClosedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (d *DrawerSession) checkAmount(amount money.Money) error {
+	if d.Status != DrawerOpen {
+		return ErrDrawerClosed
+	}
+
+	if !amount.SameCurrency(d.Float) {
+		return fmt.Errorf("%w: drawer is in %s, got %s", ErrInvalidCashAmount, d.Float.Currency, amount.Currency)
+	}
+
+	if amount.IsNegative() {
+		return fmt.Errorf("%w: amount can't be negative, got %s", ErrInvalidCashAmount, amount)
+	}
+
+	return nil
+}
+
+// Cash pays orders with cash handed over to the barista, which goes into the open
+// drawer session. Cash can't be held, so authorizations are captured right away.
+type Cash struct {
+	cfg     MethodConfig
+	drawers DrawerWriter
+}
+
+func NewCash(drawers DrawerWriter) func(MethodConfig) Method {
+	return func(cfg MethodConfig) Method {
+		return &Cash{cfg: cfg, drawers: drawers}
+	}
+}
+
+func (c *Cash) Process(ctx context.Context, o OrderRequest) (*Confirmation, error) {
+	if o.CashTendered.IsZero() {
+		return nil, fmt.Errorf("%w: tendered cash is required", ErrInvalidRequest)
+	}
+
+	if !o.CashTendered.SameCurrency(o.Total) {
+		return nil, fmt.Errorf("%w: tendered cash must be in %s", ErrInvalidRequest, o.Total.Currency)
+	}
+
+	if o.CashTendered.Amount < o.Total.Amount {
+		return nil, &DeclineError{Reason: fmt.Sprintf("tendered cash %s doesn't cover %s", o.CashTendered, o.Total)}
+	}
+
+	err := c.drawers.UpdateOpen(ctx, func(d *DrawerSession) error {
+		return d.TakePayment(o.Total)
+	})
+
+	switch {
+	case errors.Is(err, ErrDrawerClosed):
+		return nil, &DeclineError{Reason: "cash drawer is closed"}
+	case err != nil:
+		return nil, fmt.Errorf("failed to record cash payment: %w", err)
+	}
+
+	conf := NewConfirmation("cash", o)
+	conf.Tendered = o.CashTendered
+	conf.Change = money.New(o.CashTendered.Amount-o.Total.Amount, o.Total.Currency)
+
+	return conf, nil
+}
+
+func (c *Cash) Authorize(ctx context.Context, o OrderRequest) (*Confirmation, error) {
+	return c.Process(ctx, o)
+}
+
+func (c *Cash) Capture(ctx context.Context, conf *Confirmation) error {
+	return conf.Capture(time.Now())
+}
+
+func (c *Cash) ReleaseAuthorization(ctx context.Context, conf *Confirmation) error {
+	// cash payments are never left authorized, they are voided instead
+	return conf.Release()
+}
+
+func (c *Cash) Refund(ctx context.Context, conf *Confirmation, r RefundRequest) (*Refund, error) {
+	rf, err := conf.ApplyRefund(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.payOut(ctx, rf.Amount); err != nil {
+		return nil, err
+	}
+
+	return rf, nil
+}
+
+func (c *Cash) Void(ctx context.Context, conf *Confirmation) error {
+	if conf.Status == StatusVoided {
+		return nil
+	}
+
+	remaining := conf.Refundable()
+	if err := conf.Void(); err != nil {
+		return err
+	}
+
+	return c.payOut(ctx, remaining)
+}
+
+// payOut takes the cash given back to the customer from the open drawer session
+func (c *Cash) payOut(ctx context.Context, amount money.Money) error {
+	err := c.drawers.UpdateOpen(ctx, func(d *DrawerSession) error {
+		return d.PayOut(amount)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to pay out cash: %w", err)
+	}
+
+	return nil
+}
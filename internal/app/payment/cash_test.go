@@ -0,0 +1,127 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDrawer holds a single drawer session, applying updates to a copy like the real stores
+type fakeDrawer struct {
+	session *DrawerSession
+}
+
+func (f *fakeDrawer) Open(_ context.Context, d *DrawerSession) error {
+	if f.session != nil && f.session.Status == DrawerOpen {
+		return ErrDrawerAlreadyOpen
+	}
+
+	dc := *d
+	f.session = &dc
+
+	return nil
+}
+
+func (f *fakeDrawer) UpdateOpen(_ context.Context, fn func(*DrawerSession) error) error {
+	if f.session == nil || f.session.Status != DrawerOpen {
+		return ErrDrawerClosed
+	}
+
+	dc := *f.session
+	if err := fn(&dc); err != nil {
+		return err
+	}
+
+	f.session = &dc
+
+	return nil
+}
+
+func TestDrawerSession_Close(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name               string
+		counted            money.Money
+		expectedErr        error
+		expectedDifference money.Money
+	}{
+		{name: "balanced", counted: money.New(10520, "EUR"), expectedDifference: money.New(0, "EUR")},
+		{name: "short", counted: money.New(10500, "EUR"), expectedDifference: money.New(-20, "EUR")},
+		{name: "over", counted: money.New(10600, "EUR"), expectedDifference: money.New(80, "EUR")},
+		{name: "other currency", counted: money.New(10520, "USD"), expectedErr: ErrInvalidCashAmount},
+		{name: "negative", counted: money.New(-1, "EUR"), expectedErr: ErrInvalidCashAmount},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := OpenDrawer(money.New(10000, "EUR"))
+			require.NoError(t, err)
+			require.NoError(t, d.TakePayment(money.New(780, "EUR")))
+			require.NoError(t, d.PayOut(money.New(260, "EUR")))
+			assert.Equal(t, money.New(10520, "EUR"), d.Expected())
+
+			err = d.Close(tt.counted)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+				assert.Equal(t, DrawerOpen, d.Status)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, DrawerClosed, d.Status)
+			assert.Equal(t, tt.expectedDifference, d.Difference())
+			assert.True(t, errors.Is(d.TakePayment(money.New(100, "EUR")), ErrDrawerClosed))
+		})
+	}
+}
+
+func TestCash(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	drawer := &fakeDrawer{}
+	m := NewCash(drawer)(MethodConfig{Enabled: true})
+	o := OrderRequest{OrderID: uuid.New(), Total: money.New(780, "EUR"), CashTendered: money.New(1000, "EUR")}
+
+	// without an open drawer there is nowhere to put the cash
+	_, err := m.Process(ctx, o)
+	assert.True(t, errors.Is(err, ErrDeclined))
+
+	d, err := OpenDrawer(money.New(10000, "EUR"))
+	require.NoError(t, err)
+	require.NoError(t, drawer.Open(ctx, d))
+
+	c, err := m.Authorize(ctx, o)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCaptured, c.Status)
+	assert.Equal(t, money.New(1000, "EUR"), c.Tendered)
+	assert.Equal(t, money.New(220, "EUR"), c.Change)
+	assert.Equal(t, money.New(780, "EUR"), drawer.session.Sales)
+	assert.Equal(t, 1, drawer.session.Payments)
+
+	_, err = m.Process(ctx, OrderRequest{OrderID: uuid.New(), Total: money.New(780, "EUR"), CashTendered: money.New(500, "EUR")})
+	assert.True(t, errors.Is(err, ErrDeclined))
+
+	_, err = m.Process(ctx, OrderRequest{OrderID: uuid.New(), Total: money.New(780, "EUR")})
+	assert.True(t, errors.Is(err, ErrInvalidRequest))
+
+	_, err = m.Refund(ctx, c, RefundRequest{Amount: money.New(260, "EUR")})
+	require.NoError(t, err)
+	assert.Equal(t, money.New(260, "EUR"), drawer.session.PaidOut)
+
+	// voiding gives all the cash back
+	voided, err := m.Process(ctx, OrderRequest{OrderID: uuid.New(), Total: money.New(780, "EUR"), CashTendered: money.New(780, "EUR")})
+	require.NoError(t, err)
+	assert.Equal(t, money.New(0, "EUR"), voided.Change)
+	require.NoError(t, m.Void(ctx, voided))
+	assert.Equal(t, money.New(1040, "EUR"), drawer.session.PaidOut)
+	assert.Equal(t, money.New(10520, "EUR"), drawer.session.Expected())
+}
@@ -0,0 +1,215 @@
+package payment
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+)
+
+var (
+	ErrGiftCardNotFound = errors.New("gift card not found")
+	// ErrGiftCardExists is returned when issuing a gift card with a number that is already taken
+	ErrGiftCardExists = errors.New("gift card already exists")
+	// ErrInvalidGiftCardAmount is returned when the amount is not positive or not in the card currency
+	ErrInvalidGiftCardAmount = errors.New("invalid gift card amount")
+	// ErrInsufficientBalance is returned when redeeming more than is left on the card
+	ErrInsufficientBalance = errors.New("insufficient gift card balance")
+)
+
+// giftCardDigits is the length of gift card numbers
+const giftCardDigits = 16
+
+// GiftCardAccount is a prepaid balance that customers pay with by giving the card number
+type GiftCardAccount struct {
+	Number    string      `json:"number" db:"number"`
+	Balance   money.Money `json:"balance" db:"balance"`
+	IssuedAt  time.Time   `json:"issued_at" db:"issued_at"`
+	UpdatedAt time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// IssueGiftCard creates a gift card with a random number, loaded with the given amount
+func IssueGiftCard(amount money.Money) (*GiftCardAccount, error) {
+	if _, err := money.Exponent(amount.Currency); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGiftCardAmount, err)
+	}
+
+	if amount.IsZero() || amount.IsNegative() {
+		return nil, fmt.Errorf("%w: amount must be positive, got %s", ErrInvalidGiftCardAmount, amount)
+	}
+
+	number, err := newGiftCardNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	return &GiftCardAccount{
+		Number:    number,
+		Balance:   amount,
+		IssuedAt:  now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// TopUp adds the amount to the balance of the card. Refunds of gift card payments
+// also put the money back this way.
+func (g *GiftCardAccount) TopUp(amount money.Money) error {
+	if err := g.checkAmount(amount); err != nil {
+		return err
+	}
+
+	g.Balance = money.New(g.Balance.Amount+amount.Amount, g.Balance.Currency)
+	g.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+// Redeem takes the amount from the balance of the card. Cards can be redeemed
+// partially, as long as the amount doesn't exceed the balance.
+func (g *GiftCardAccount) Redeem(amount money.Money) error {
+	if err := g.checkAmount(amount); err != nil {
+		return err
+	}
+
+	if amount.Amount > g.Balance.Amount {
+		return fmt.Errorf("%w: %s available, got %s", ErrInsufficientBalance, g.Balance, amount)
+	}
+
+	g.Balance = money.New(g.Balance.Amount-amount.Amount, g.Balance.Currency)
+	g.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (g *GiftCardAccount) checkAmount(amount money.Money) error {
+	if !amount.SameCurrency(g.Balance) {
+		return fmt.Errorf("%w: card is in %s, got %s", ErrInvalidGiftCardAmount, g.Balance.Currency, amount.Currency)
+	}
+
+	if amount.IsZero() || amount.IsNegative() {
+		return fmt.Errorf("%w: amount must be positive, got %s", ErrInvalidGiftCardAmount, amount)
+	}
+
+	return nil
+}
+
+func newGiftCardNumber() (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(giftCardDigits), nil)
+
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate gift card number: %w", err)
+	}
+
+	return fmt.Sprintf("%0*d", giftCardDigits, n), nil
+}
+
+// GiftCard pays orders with the balance of a gift card. There is no provider holding
+// the funds for us, so authorizations redeem the card right away and captures
+// only confirm them.
+type GiftCard struct {
+	cfg   MethodConfig
+	cards GiftCardWriter
+}
+
+func NewGiftCard(cards GiftCardWriter) func(MethodConfig) Method {
+	return func(cfg MethodConfig) Method {
+		return &GiftCard{cfg: cfg, cards: cards}
+	}
+}
+
+func (g *GiftCard) Process(ctx context.Context, o OrderRequest) (*Confirmation, error) {
+	if err := g.redeem(ctx, o); err != nil {
+		return nil, err
+	}
+
+	c := NewConfirmation("gift_card", o)
+	c.GiftCardNumber = o.GiftCardNumber
+
+	return c, nil
+}
+
+func (g *GiftCard) Authorize(ctx context.Context, o OrderRequest) (*Confirmation, error) {
+	// the card is redeemed right away, so there is nothing left to expire
+	return g.Process(ctx, o)
+}
+
+func (g *GiftCard) Capture(ctx context.Context, conf *Confirmation) error {
+	return conf.Capture(time.Now())
+}
+
+func (g *GiftCard) ReleaseAuthorization(ctx context.Context, conf *Confirmation) error {
+	// gift card payments are never left authorized, they are voided instead
+	return conf.Release()
+}
+
+func (g *GiftCard) Refund(ctx context.Context, conf *Confirmation, r RefundRequest) (*Refund, error) {
+	rf, err := conf.ApplyRefund(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.credit(ctx, conf.GiftCardNumber, rf.Amount); err != nil {
+		return nil, err
+	}
+
+	return rf, nil
+}
+
+func (g *GiftCard) Void(ctx context.Context, conf *Confirmation) error {
+	if conf.Status == StatusVoided {
+		return nil
+	}
+
+	remaining := conf.Refundable()
+	if err := conf.Void(); err != nil {
+		return err
+	}
+
+	if remaining.IsZero() {
+		return nil
+	}
+
+	return g.credit(ctx, conf.GiftCardNumber, remaining)
+}
+
+// redeem takes the order total from the card, declining the payment when the card
+// is unknown or its balance doesn't cover the total
+func (g *GiftCard) redeem(ctx context.Context, o OrderRequest) error {
+	if o.GiftCardNumber == "" {
+		return fmt.Errorf("%w: gift card number is required", ErrInvalidRequest)
+	}
+
+	err := g.cards.Update(ctx, o.GiftCardNumber, func(card *GiftCardAccount) error {
+		return card.Redeem(o.Total)
+	})
+
+	switch {
+	case errors.Is(err, ErrGiftCardNotFound):
+		return &DeclineError{Reason: "unknown gift card"}
+	case errors.Is(err, ErrInsufficientBalance), errors.Is(err, ErrInvalidGiftCardAmount):
+		return &DeclineError{Reason: err.Error()}
+	case err != nil:
+		return fmt.Errorf("failed to redeem gift card: %w", err)
+	}
+
+	return nil
+}
+
+// credit puts money of a voided or refunded payment back on the card
+func (g *GiftCard) credit(ctx context.Context, number string, amount money.Money) error {
+	err := g.cards.Update(ctx, number, func(card *GiftCardAccount) error {
+		return card.TopUp(amount)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to credit gift card: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGiftCards keeps gift cards in a map, applying updates to a copy like the real stores
+type fakeGiftCards map[string]GiftCardAccount
+
+func (f fakeGiftCards) Add(_ context.Context, card *GiftCardAccount) error {
+	f[card.Number] = *card
+	return nil
+}
+
+func (f fakeGiftCards) Update(_ context.Context, number string, fn func(*GiftCardAccount) error) error {
+	card, ok := f[number]
+	if !ok {
+		return ErrGiftCardNotFound
+	}
+
+	if err := fn(&card); err != nil {
+		return err
+	}
+
+	f[number] = card
+
+	return nil
+}
+
+func TestGiftCardAccount_Redeem(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		amount          money.Money
+		expectedErr     error
+		expectedBalance money.Money
+	}{
+		{name: "partial amount", amount: money.New(260, "EUR"), expectedBalance: money.New(740, "EUR")},
+		{name: "whole balance", amount: money.New(1000, "EUR"), expectedBalance: money.New(0, "EUR")},
+		{name: "more than the balance", amount: money.New(1001, "EUR"), expectedErr: ErrInsufficientBalance},
+		{name: "other currency", amount: money.New(260, "USD"), expectedErr: ErrInvalidGiftCardAmount},
+		{name: "zero amount", amount: money.New(0, "EUR"), expectedErr: ErrInvalidGiftCardAmount},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			card, err := IssueGiftCard(money.New(1000, "EUR"))
+			require.NoError(t, err)
+
+			err = card.Redeem(tt.amount)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+				assert.Equal(t, money.New(1000, "EUR"), card.Balance)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedBalance, card.Balance)
+		})
+	}
+}
+
+func TestIssueGiftCard(t *testing.T) {
+	t.Parallel()
+
+	card, err := IssueGiftCard(money.New(2500, "EUR"))
+	require.NoError(t, err)
+	assert.Len(t, card.Number, giftCardDigits)
+	assert.Equal(t, money.New(2500, "EUR"), card.Balance)
+
+	require.NoError(t, card.TopUp(money.New(500, "EUR")))
+	assert.Equal(t, money.New(3000, "EUR"), card.Balance)
+
+	_, err = IssueGiftCard(money.New(-100, "EUR"))
+	assert.True(t, errors.Is(err, ErrInvalidGiftCardAmount))
+}
+
+func TestGiftCard(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cards := fakeGiftCards{}
+
+	card, err := IssueGiftCard(money.New(1000, "EUR"))
+	require.NoError(t, err)
+	require.NoError(t, cards.Add(ctx, card))
+
+	m := NewGiftCard(cards)(MethodConfig{Enabled: true})
+	o := OrderRequest{OrderID: uuid.New(), Total: money.New(780, "EUR"), GiftCardNumber: card.Number}
+
+	c, err := m.Authorize(ctx, o)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCaptured, c.Status)
+	assert.Equal(t, card.Number, c.GiftCardNumber)
+	assert.Equal(t, money.New(220, "EUR"), cards[card.Number].Balance)
+	assert.NoError(t, m.Capture(ctx, c))
+
+	// the balance left doesn't cover a second order, which is declined without redeeming anything
+	_, err = m.Process(ctx, o)
+	assert.True(t, errors.Is(err, ErrDeclined))
+	assert.Equal(t, money.New(220, "EUR"), cards[card.Number].Balance)
+
+	_, err = m.Process(ctx, OrderRequest{OrderID: uuid.New(), Total: money.New(100, "EUR"), GiftCardNumber: "unknown"})
+	assert.True(t, errors.Is(err, ErrDeclined))
+
+	_, err = m.Refund(ctx, c, RefundRequest{Amount: money.New(260, "EUR")})
+	require.NoError(t, err)
+	assert.Equal(t, money.New(480, "EUR"), cards[card.Number].Balance)
+
+	// voiding gives back what wasn't refunded yet
+	require.NoError(t, m.Void(ctx, &Confirmation{
+		ID: uuid.New(), Amount: money.New(780, "EUR"), Refunded: money.New(0, "EUR"),
+		Status: StatusCaptured, GiftCardNumber: card.Number,
+	}))
+	assert.Equal(t, money.New(1260, "EUR"), cards[card.Number].Balance)
+}
@@ -1,54 +1,283 @@
 package payment
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
 )
 
+var (
+	ErrMethodNotSupported = errors.New("payment method not supported")
+	ErrInvalidRequest     = errors.New("invalid payment request")
+	// ErrDeclined is returned when the payment provider refuses the payment
+	ErrDeclined = errors.New("payment declined")
+	// ErrNotVoidable is returned when voiding a payment that isn't captured or authorized
+	ErrNotVoidable = errors.New("payment can't be voided")
+)
+
+// Status is the state of a payment
+type Status string
+
+const (
+	StatusCaptured          Status = "captured"
+	StatusVoided            Status = "voided"
+	StatusPartiallyRefunded Status = "partially_refunded"
+	StatusRefunded          Status = "refunded"
+)
+
+// DeclineError is returned when the payment provider refuses the payment for a known reason
+type DeclineError struct {
+	Reason string
+}
+
+func (e *DeclineError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrDeclined, e.Reason)
+}
+
+// Is makes errors.Is(err, ErrDeclined) match any decline
+func (e *DeclineError) Is(target error) bool {
+	return target == ErrDeclined
+}
+
 type OrderRequest struct {
 	OrderID uuid.UUID
-	Total   float64
+	// Total is what is charged, the order total plus the tip
+	Total money.Money
+	Items []LineItem
+	// Discount is what promotions took off the sum of the items
+	Discount money.Money
+	// Tax is added to the sum of the items, taxes included in the item prices aren't part of it
+	Tax money.Money
+	// Tip is given by the customer on top of the order total, it's reported apart from the sales
+	Tip money.Money
+	// Barista served the order and ShiftID is the cash drawer session open when it was paid,
+	// they tell whom the tip goes to
+	Barista string
+	ShiftID uuid.UUID
+	// GiftCardNumber is the card to redeem when paying with a gift card
+	GiftCardNumber string
+	// CashTendered is the cash handed over when paying with cash
+	CashTendered money.Money
 }
 
+// LineItem summarizes an item of the order being paid
+type LineItem struct {
+	Name        string
+	ServingSize string
+	Qty         int
+	// UnitPrice includes the price of the modifiers
+	UnitPrice money.Money
+	Modifiers []string
+}
+
+// Validate checks that the order request can be charged, which means it has a
+// positive total in a known currency that matches the sum of its line items,
+// minus its discount plus its tax and tip
+func (o OrderRequest) Validate() error {
+	if o.OrderID == uuid.Nil {
+		return fmt.Errorf("%w: order id is required", ErrInvalidRequest)
+	}
+
+	if _, err := money.Exponent(o.Total.Currency); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+
+	if o.Total.IsNegative() || o.Total.IsZero() {
+		return fmt.Errorf("%w: order total must be positive, got %s", ErrInvalidRequest, o.Total)
+	}
+
+	if o.Discount.IsNegative() {
+		return fmt.Errorf("%w: discount can't be negative, got %s", ErrInvalidRequest, o.Discount)
+	}
+
+	if o.Tax.IsNegative() {
+		return fmt.Errorf("%w: tax can't be negative, got %s", ErrInvalidRequest, o.Tax)
+	}
+
+	if o.Tip.IsNegative() {
+		return fmt.Errorf("%w: tip can't be negative, got %s", ErrInvalidRequest, o.Tip)
+	}
+
+	if len(o.Items) == 0 {
+		return nil
+	}
+
+	sum := money.New(0, o.Total.Currency)
+	for _, i := range o.Items {
+		var err error
+		if sum, err = sum.Add(i.UnitPrice.Mul(int64(i.Qty))); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+		}
+	}
+
+	if o.Discount.Amount != 0 {
+		var err error
+		if sum, err = sum.Sub(o.Discount); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+		}
+	}
+
+	for _, added := range []money.Money{o.Tax, o.Tip} {
+		if added.Amount == 0 {
+			continue
+		}
+
+		var err error
+		if sum, err = sum.Add(added); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+		}
+	}
+
+	if sum != o.Total {
+		return fmt.Errorf("%w: order total %s doesn't match the sum of its items minus discounts plus taxes and tip %s", ErrInvalidRequest, o.Total, sum)
+	}
+
+	return nil
+}
+
+// Method is a payment provider
 type Method interface {
-	Process(OrderRequest) (*Confirmation, error)
+	Process(context.Context, OrderRequest) (*Confirmation, error)
+	// Authorize holds the order total without charging it
+	Authorize(context.Context, OrderRequest) (*Confirmation, error)
+	// Capture charges a previously authorized payment
+	Capture(context.Context, *Confirmation) error
+	// ReleaseAuthorization gives the funds held by an authorization back
+	ReleaseAuthorization(context.Context, *Confirmation) error
+	// Refund gives back part or all of a captured payment
+	Refund(context.Context, *Confirmation, RefundRequest) (*Refund, error)
+	// Void cancels a captured payment before it's settled
+	Void(context.Context, *Confirmation) error
 }
 
 type Confirmation struct {
-	ID      uuid.UUID `json:"id" db:"id"`
-	OrderID uuid.UUID `json:"order_id" db:"order_id"`
+	ID      uuid.UUID   `json:"id" db:"id"`
+	OrderID uuid.UUID   `json:"order_id" db:"order_id"`
+	Method  string      `json:"method" db:"method"`
+	Amount  money.Money `json:"amount" db:"amount"`
+	// Refunded is the sum of all refunds of the payment
+	Refunded money.Money `json:"refunded" db:"refunded"`
+	Status   Status      `json:"status" db:"status"`
+	// PayedAt is when the payment was captured or, for authorizations, when it was authorized
 	PayedAt time.Time `json:"payed_at" db:"payed_at"`
+	// ExpiresAt is when an authorization can't be captured anymore
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+	// GiftCardNumber is the card that was redeemed, refunds and voids are credited back to it
+	GiftCardNumber string `json:"gift_card_number,omitempty" db:"gift_card_number"`
+	// Tendered is the cash handed over for cash payments and Change what was given back
+	Tendered money.Money `json:"tendered" db:"tendered"`
+	Change   money.Money `json:"change" db:"change"`
+	// Tip is the part of the amount given as a tip to the Barista during the shift
+	Tip     money.Money `json:"tip" db:"tip"`
+	Barista string      `json:"barista,omitempty" db:"barista"`
+	ShiftID uuid.UUID   `json:"shift_id" db:"shift_id"`
 }
 
-func NewConfirmation(orderID uuid.UUID) *Confirmation {
-	return &Confirmation{ID: uuid.New(), OrderID: orderID, PayedAt: time.Now()}
+func NewConfirmation(method string, o OrderRequest) *Confirmation {
+	return &Confirmation{
+		ID:       uuid.New(),
+		OrderID:  o.OrderID,
+		Method:   method,
+		Amount:   o.Total,
+		Refunded: money.New(0, o.Total.Currency),
+		Status:   StatusCaptured,
+		PayedAt:  time.Now().UTC(),
+		Tip:      money.New(o.Tip.Amount, o.Total.Currency),
+		Barista:  o.Barista,
+		ShiftID:  o.ShiftID,
+	}
 }
 
-type MethodFactory struct{}
-
-func NewMethodFactory(method string) (Method, error) {
-	switch method {
-	case "credit_card":
-		return &CreditCard{}, nil
-	case "apple_pay":
-		return &ApplePay{}, nil
+// Void cancels a captured or authorized payment. Voiding an already voided payment
+// is a no-op, so compensations can be safely retried.
+func (c *Confirmation) Void() error {
+	switch c.Status {
+	case StatusVoided:
+		return nil
+	case StatusCaptured, StatusAuthorized:
+		c.Status = StatusVoided
+		return nil
 	default:
-		return nil, errors.New("payment method not supported")
+		return fmt.Errorf("%w: payment is %s", ErrNotVoidable, c.Status)
 	}
 }
 
-type CreditCard struct{}
+type CreditCard struct {
+	cfg MethodConfig
+}
 
-func (c *CreditCard) Process(o OrderRequest) (*Confirmation, error) {
+func NewCreditCard(cfg MethodConfig) Method {
+	return &CreditCard{cfg: cfg}
+}
+
+func (c *CreditCard) Process(ctx context.Context, o OrderRequest) (*Confirmation, error) {
 	// pretend to connect to some credit card provider
-	return NewConfirmation(o.OrderID), nil
+	return NewConfirmation("credit_card", o), nil
+}
+
+func (c *CreditCard) Authorize(ctx context.Context, o OrderRequest) (*Confirmation, error) {
+	// pretend to ask the credit card provider to hold the funds
+	return NewAuthorization("credit_card", o, c.cfg.AuthorizationTTL), nil
 }
 
-type ApplePay struct{}
+func (c *CreditCard) Capture(ctx context.Context, conf *Confirmation) error {
+	// pretend to ask the credit card provider to settle the authorization
+	return conf.Capture(time.Now())
+}
+
+func (c *CreditCard) ReleaseAuthorization(ctx context.Context, conf *Confirmation) error {
+	// pretend to cancel the authorization with the credit card provider
+	return conf.Release()
+}
+
+func (c *CreditCard) Refund(ctx context.Context, conf *Confirmation, r RefundRequest) (*Refund, error) {
+	// pretend to ask the credit card provider for a chargeback
+	return conf.ApplyRefund(r)
+}
+
+func (c *CreditCard) Void(ctx context.Context, conf *Confirmation) error {
+	// pretend to cancel the authorization with the credit card provider
+	return conf.Void()
+}
+
+type ApplePay struct {
+	cfg MethodConfig
+}
+
+func NewApplePay(cfg MethodConfig) Method {
+	return &ApplePay{cfg: cfg}
+}
+
+func (a *ApplePay) Process(ctx context.Context, o OrderRequest) (*Confirmation, error) {
+	// pretend to connect to apple pay
+	return NewConfirmation("apple_pay", o), nil
+}
+
+func (a *ApplePay) Authorize(ctx context.Context, o OrderRequest) (*Confirmation, error) {
+	// pretend to connect to apple pay
+	return NewAuthorization("apple_pay", o, a.cfg.AuthorizationTTL), nil
+}
+
+func (a *ApplePay) Capture(ctx context.Context, conf *Confirmation) error {
+	// pretend to connect to apple pay
+	return conf.Capture(time.Now())
+}
+
+func (a *ApplePay) ReleaseAuthorization(ctx context.Context, conf *Confirmation) error {
+	// pretend to connect to apple pay
+	return conf.Release()
+}
+
+func (a *ApplePay) Refund(ctx context.Context, conf *Confirmation, r RefundRequest) (*Refund, error) {
+	// pretend to connect to apple pay
+	return conf.ApplyRefund(r)
+}
 
-func (a *ApplePay) Process(o OrderRequest) (*Confirmation, error) {
+func (a *ApplePay) Void(ctx context.Context, conf *Confirmation) error {
 	// pretend to connect to apple pay
-	return NewConfirmation(o.OrderID), nil
+	return conf.Void()
 }
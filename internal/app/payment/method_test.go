@@ -0,0 +1,142 @@
+package payment
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		req           OrderRequest
+		errorExpected bool
+	}{
+		{
+			name: "valid request",
+			req: OrderRequest{
+				OrderID: uuid.New(),
+				Total:   money.New(780, "EUR"),
+				Items: []LineItem{
+					{Name: "cappuccino", ServingSize: "L", Qty: 3, UnitPrice: money.New(260, "EUR")},
+				},
+			},
+		},
+		{
+			name: "valid discounted request",
+			req: OrderRequest{
+				OrderID:  uuid.New(),
+				Total:    money.New(520, "EUR"),
+				Discount: money.New(260, "EUR"),
+				Items: []LineItem{
+					{Name: "cappuccino", ServingSize: "L", Qty: 3, UnitPrice: money.New(260, "EUR")},
+				},
+			},
+		},
+		{
+			name: "total doesn't match discounted items",
+			req: OrderRequest{
+				OrderID:  uuid.New(),
+				Total:    money.New(780, "EUR"),
+				Discount: money.New(260, "EUR"),
+				Items: []LineItem{
+					{Name: "cappuccino", ServingSize: "L", Qty: 3, UnitPrice: money.New(260, "EUR")},
+				},
+			},
+			errorExpected: true,
+		},
+		{
+			name: "valid taxed request",
+			req: OrderRequest{
+				OrderID:  uuid.New(),
+				Total:    money.New(619, "EUR"),
+				Discount: money.New(260, "EUR"),
+				Tax:      money.New(99, "EUR"),
+				Items: []LineItem{
+					{Name: "cappuccino", ServingSize: "L", Qty: 3, UnitPrice: money.New(260, "EUR")},
+				},
+			},
+		},
+		{
+			name: "total without the tax",
+			req: OrderRequest{
+				OrderID: uuid.New(),
+				Total:   money.New(780, "EUR"),
+				Tax:     money.New(148, "EUR"),
+				Items: []LineItem{
+					{Name: "cappuccino", ServingSize: "L", Qty: 3, UnitPrice: money.New(260, "EUR")},
+				},
+			},
+			errorExpected: true,
+		},
+		{
+			name:          "negative tax",
+			req:           OrderRequest{OrderID: uuid.New(), Total: money.New(780, "EUR"), Tax: money.New(-1, "EUR")},
+			errorExpected: true,
+		},
+		{
+			name:          "negative discount",
+			req:           OrderRequest{OrderID: uuid.New(), Total: money.New(780, "EUR"), Discount: money.New(-1, "EUR")},
+			errorExpected: true,
+		},
+		{
+			name:          "missing order id",
+			req:           OrderRequest{Total: money.New(780, "EUR")},
+			errorExpected: true,
+		},
+		{
+			name:          "unknown currency",
+			req:           OrderRequest{OrderID: uuid.New(), Total: money.New(780, "")},
+			errorExpected: true,
+		},
+		{
+			name:          "zero total",
+			req:           OrderRequest{OrderID: uuid.New(), Total: money.New(0, "EUR")},
+			errorExpected: true,
+		},
+		{
+			name: "total doesn't match items",
+			req: OrderRequest{
+				OrderID: uuid.New(),
+				Total:   money.New(1, "EUR"),
+				Items: []LineItem{
+					{Name: "cappuccino", ServingSize: "L", Qty: 3, UnitPrice: money.New(260, "EUR")},
+				},
+			},
+			errorExpected: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.req.Validate()
+			if tt.errorExpected {
+				assert.True(t, errors.Is(err, ErrInvalidRequest))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConfirmation_Void(t *testing.T) {
+	t.Parallel()
+
+	c := NewConfirmation("credit_card", OrderRequest{OrderID: uuid.New(), Total: money.New(780, "EUR")})
+
+	assert.NoError(t, c.Void())
+	assert.Equal(t, StatusVoided, c.Status)
+
+	// retried compensations must not fail
+	assert.NoError(t, c.Void())
+
+	c.Status = "unknown"
+	assert.True(t, errors.Is(c.Void(), ErrNotVoidable))
+}
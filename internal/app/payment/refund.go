@@ -0,0 +1,108 @@
+package payment
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+)
+
+var (
+	// ErrNotRefundable is returned when refunding a payment that isn't captured
+	ErrNotRefundable = errors.New("payment can't be refunded")
+	// ErrInvalidRefund is returned when the refund amount is not positive or not in the payment currency
+	ErrInvalidRefund = errors.New("invalid refund")
+	// ErrRefundExceedsPayment is returned when the refunds would give back more than was paid
+	ErrRefundExceedsPayment = errors.New("refund exceeds the payment amount")
+)
+
+// RefundRequest asks for part of a payment back. A zero amount refunds whatever
+// wasn't refunded yet.
+type RefundRequest struct {
+	Amount money.Money
+	Reason string
+}
+
+// Refund records money given back to the customer for a payment
+type Refund struct {
+	ID         uuid.UUID   `json:"id" db:"id"`
+	PaymentID  uuid.UUID   `json:"payment_id" db:"payment_id"`
+	OrderID    uuid.UUID   `json:"order_id" db:"order_id"`
+	Amount     money.Money `json:"amount" db:"amount"`
+	Reason     string      `json:"reason" db:"reason"`
+	RefundedAt time.Time   `json:"refunded_at" db:"refunded_at"`
+}
+
+// Refundable returns the amount that can still be refunded
+func (c *Confirmation) Refundable() money.Money {
+	remaining, err := c.Amount.Sub(c.Refunded)
+	if err != nil {
+		// a confirmation without refunds has no currency on its refunded amount
+		return c.Amount
+	}
+
+	return remaining
+}
+
+// ApplyRefund gives back part or all of the payment, returning the record of the refund.
+// Authorizations weren't charged yet, so they can only be refunded in full by releasing them.
+func (c *Confirmation) ApplyRefund(r RefundRequest) (*Refund, error) {
+	if c.Status == StatusAuthorized {
+		if !r.Amount.IsZero() && r.Amount != c.Amount {
+			return nil, fmt.Errorf("%w: authorized payments can only be refunded in full", ErrInvalidRefund)
+		}
+
+		if err := c.Release(); err != nil {
+			return nil, err
+		}
+
+		c.Refunded = c.Amount
+
+		return newRefund(c, c.Amount, r.Reason), nil
+	}
+
+	if c.Status != StatusCaptured && c.Status != StatusPartiallyRefunded {
+		return nil, fmt.Errorf("%w: payment is %s", ErrNotRefundable, c.Status)
+	}
+
+	remaining := c.Refundable()
+
+	amount := r.Amount
+	if amount.IsZero() {
+		amount = remaining
+	}
+
+	if !amount.SameCurrency(c.Amount) {
+		return nil, fmt.Errorf("%w: payment is in %s, got %s", ErrInvalidRefund, c.Amount.Currency, amount.Currency)
+	}
+
+	if amount.IsNegative() {
+		return nil, fmt.Errorf("%w: amount must be positive, got %s", ErrInvalidRefund, amount)
+	}
+
+	if amount.Amount > remaining.Amount {
+		return nil, fmt.Errorf("%w: only %s can be refunded, got %s", ErrRefundExceedsPayment, remaining, amount)
+	}
+
+	c.Refunded = money.New(c.Amount.Amount-remaining.Amount+amount.Amount, c.Amount.Currency)
+	if c.Refunded == c.Amount {
+		c.Status = StatusRefunded
+	} else {
+		c.Status = StatusPartiallyRefunded
+	}
+
+	return newRefund(c, amount, r.Reason), nil
+}
+
+func newRefund(c *Confirmation, amount money.Money, reason string) *Refund {
+	return &Refund{
+		ID:         uuid.New(),
+		PaymentID:  c.ID,
+		OrderID:    c.OrderID,
+		Amount:     amount,
+		Reason:     reason,
+		RefundedAt: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,42 @@
+package payment
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmation_ApplyRefund(t *testing.T) {
+	t.Parallel()
+
+	c := NewConfirmation("credit_card", OrderRequest{OrderID: uuid.New(), Total: money.New(780, "EUR")})
+
+	rf, err := c.ApplyRefund(RefundRequest{Amount: money.New(260, "EUR"), Reason: "cold coffee"})
+	require.NoError(t, err)
+	assert.Equal(t, c.ID, rf.PaymentID)
+	assert.Equal(t, money.New(260, "EUR"), rf.Amount)
+	assert.Equal(t, StatusPartiallyRefunded, c.Status)
+	assert.Equal(t, money.New(520, "EUR"), c.Refundable())
+
+	assert.True(t, errors.Is(c.Void(), ErrNotVoidable))
+
+	_, err = c.ApplyRefund(RefundRequest{Amount: money.New(600, "EUR")})
+	assert.True(t, errors.Is(err, ErrRefundExceedsPayment))
+
+	_, err = c.ApplyRefund(RefundRequest{Amount: money.New(100, "USD")})
+	assert.True(t, errors.Is(err, ErrInvalidRefund))
+
+	// a zero amount refunds the rest of the payment
+	rf, err = c.ApplyRefund(RefundRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, money.New(520, "EUR"), rf.Amount)
+	assert.Equal(t, StatusRefunded, c.Status)
+	assert.Equal(t, money.New(780, "EUR"), c.Refunded)
+
+	_, err = c.ApplyRefund(RefundRequest{})
+	assert.True(t, errors.Is(err, ErrNotRefundable))
+}
@@ -0,0 +1,114 @@
+package payment
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrMethodAlreadyRegistered is returned when registering two methods with the same name
+var ErrMethodAlreadyRegistered = errors.New("payment method already registered")
+
+// MethodConfig is the configuration of a payment method, usually loaded from the environment
+type MethodConfig struct {
+	// Enabled makes the method available to customers, disabled methods reject new payments
+	// but can still settle the payments they already made
+	Enabled bool `default:"true"`
+	// DisplayName is shown to customers when choosing how to pay
+	DisplayName string `split_words:"true"`
+	// AuthorizationTTL is how long the provider holds authorized funds before the
+	// authorization expires and can't be captured anymore
+	AuthorizationTTL time.Duration `split_words:"true" default:"168h"`
+}
+
+// MethodInfo describes a registered payment method
+type MethodInfo struct {
+	Name        string
+	DisplayName string
+	Enabled     bool
+}
+
+type registration struct {
+	method Method
+	cfg    MethodConfig
+}
+
+// Registry holds the payment methods supported by the payment service
+type Registry struct {
+	mux     *sync.RWMutex
+	methods map[string]registration
+	// names keeps the registration order, which is the order methods are listed in
+	names []string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		mux:     &sync.RWMutex{},
+		methods: make(map[string]registration, 0),
+	}
+}
+
+// Register adds a payment method, created with its own configuration, under the given name
+func (r *Registry) Register(name string, cfg MethodConfig, newMethod func(MethodConfig) Method) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if _, ok := r.methods[name]; ok {
+		return fmt.Errorf("%w: %q", ErrMethodAlreadyRegistered, name)
+	}
+
+	if cfg.DisplayName == "" {
+		cfg.DisplayName = name
+	}
+
+	r.methods[name] = registration{method: newMethod(cfg), cfg: cfg}
+	r.names = append(r.names, name)
+
+	return nil
+}
+
+// Method returns an enabled payment method to make new payments with
+func (r *Registry) Method(name string) (Method, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	reg, ok := r.methods[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrMethodNotSupported, name)
+	}
+
+	if !reg.cfg.Enabled {
+		return nil, fmt.Errorf("%w: %q is disabled", ErrMethodNotSupported, name)
+	}
+
+	return reg.method, nil
+}
+
+// Settler returns the payment method that made an existing payment, even if the
+// method was disabled since, so its payments can still be captured, refunded or voided
+func (r *Registry) Settler(name string) (Method, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	reg, ok := r.methods[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrMethodNotSupported, name)
+	}
+
+	return reg.method, nil
+}
+
+// List returns the registered payment methods in registration order
+func (r *Registry) List() []MethodInfo {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	infos := make([]MethodInfo, 0, len(r.names))
+	for _, name := range r.names {
+		reg := r.methods[name]
+		infos = append(infos, MethodInfo{Name: name, DisplayName: reg.cfg.DisplayName, Enabled: reg.cfg.Enabled})
+	}
+
+	return infos
+}
@@ -0,0 +1,41 @@
+package payment
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	require.NoError(t, r.Register("credit_card", MethodConfig{Enabled: true, DisplayName: "Credit card", AuthorizationTTL: time.Hour}, NewCreditCard))
+	require.NoError(t, r.Register("apple_pay", MethodConfig{Enabled: false}, NewApplePay))
+
+	err := r.Register("credit_card", MethodConfig{Enabled: true}, NewCreditCard)
+	assert.True(t, errors.Is(err, ErrMethodAlreadyRegistered))
+
+	m, err := r.Method("credit_card")
+	require.NoError(t, err)
+	assert.IsType(t, &CreditCard{}, m)
+
+	_, err = r.Method("apple_pay")
+	assert.True(t, errors.Is(err, ErrMethodNotSupported))
+
+	_, err = r.Method("cash")
+	assert.True(t, errors.Is(err, ErrMethodNotSupported))
+
+	// payments made before the method was disabled can still be settled
+	m, err = r.Settler("apple_pay")
+	require.NoError(t, err)
+	assert.IsType(t, &ApplePay{}, m)
+
+	assert.Equal(t, []MethodInfo{
+		{Name: "credit_card", DisplayName: "Credit card", Enabled: true},
+		{Name: "apple_pay", DisplayName: "apple_pay", Enabled: false},
+	}, r.List())
+}
@@ -0,0 +1,65 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrConfirmationNotFound = errors.New("payment confirmation not found")
+
+type Reader interface {
+	FetchByID(context.Context, uuid.UUID) (*Confirmation, error)
+	// FetchByOrderID returns the latest payment of the order
+	FetchByOrderID(context.Context, uuid.UUID) (*Confirmation, error)
+	// FetchAllByOrderID returns every payment of the order, e.g. the tenders of a split payment, oldest first
+	FetchAllByOrderID(context.Context, uuid.UUID) ([]*Confirmation, error)
+	// FetchExpiredAuthorizations returns the authorizations that expired before the given time
+	FetchExpiredAuthorizations(context.Context, time.Time) ([]*Confirmation, error)
+	// FetchPaidBetween returns the payments made from the first time until, but not including, the second
+	FetchPaidBetween(ctx context.Context, from, to time.Time) ([]*Confirmation, error)
+}
+
+type Writer interface {
+	Add(context.Context, *Confirmation) error
+}
+
+type RefundReader interface {
+	// FetchByPaymentID returns the refunds of a payment, oldest first
+	FetchByPaymentID(context.Context, uuid.UUID) ([]*Refund, error)
+}
+
+type RefundWriter interface {
+	Add(context.Context, *Refund) error
+	// Refund locks the payment while fn refunds it, then saves the refund fn returns
+	// together with the payment, so concurrent refunds can't give back more than was paid
+	Refund(ctx context.Context, paymentID uuid.UUID, fn func(*Confirmation) (*Refund, error)) (*Confirmation, *Refund, error)
+}
+
+type GiftCardReader interface {
+	FetchByNumber(context.Context, string) (*GiftCardAccount, error)
+}
+
+type GiftCardWriter interface {
+	// Add stores a newly issued gift card, failing with ErrGiftCardExists if its number is taken
+	Add(context.Context, *GiftCardAccount) error
+	// Update applies the change to the stored gift card and saves it, so concurrent
+	// redemptions can't spend the same balance twice
+	Update(ctx context.Context, number string, fn func(*GiftCardAccount) error) error
+}
+
+type DrawerReader interface {
+	FetchByID(context.Context, uuid.UUID) (*DrawerSession, error)
+	// FetchOpen returns the open drawer session, failing with ErrDrawerClosed when none is open
+	FetchOpen(context.Context) (*DrawerSession, error)
+}
+
+type DrawerWriter interface {
+	// Open stores a newly opened session, failing with ErrDrawerAlreadyOpen if another session is open
+	Open(context.Context, *DrawerSession) error
+	// UpdateOpen applies the change to the open session and saves it, failing with
+	// ErrDrawerClosed when no session is open
+	UpdateOpen(ctx context.Context, fn func(*DrawerSession) error) error
+}
@@ -0,0 +1,65 @@
+package payment
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+)
+
+// TipTotal sums the tips a barista was given during a shift
+type TipTotal struct {
+	Barista string
+	// ShiftID is the cash drawer session the tips were given in, it's nil for tips
+	// given while no drawer was open
+	ShiftID  uuid.UUID
+	Amount   money.Money
+	Payments int
+}
+
+// TipTotals sums the tips of the payments by barista, shift and currency, sorted by
+// barista and shift. Tips of payments that were given back in full don't count.
+func TipTotals(confirmations []*Confirmation) []*TipTotal {
+	type key struct {
+		barista  string
+		shiftID  uuid.UUID
+		currency string
+	}
+
+	var (
+		totals = make([]*TipTotal, 0)
+		byKey  = make(map[key]*TipTotal)
+	)
+
+	for _, c := range confirmations {
+		if c.Tip.IsZero() {
+			continue
+		}
+
+		switch c.Status {
+		case StatusVoided, StatusReleased, StatusExpired, StatusRefunded:
+			continue
+		}
+
+		k := key{barista: c.Barista, shiftID: c.ShiftID, currency: c.Tip.Currency}
+		t, ok := byKey[k]
+		if !ok {
+			t = &TipTotal{Barista: c.Barista, ShiftID: c.ShiftID, Amount: money.New(0, c.Tip.Currency)}
+			byKey[k] = t
+			totals = append(totals, t)
+		}
+
+		t.Amount.Amount += c.Tip.Amount
+		t.Payments++
+	}
+
+	sort.SliceStable(totals, func(i, j int) bool {
+		if totals[i].Barista != totals[j].Barista {
+			return totals[i].Barista < totals[j].Barista
+		}
+
+		return totals[i].ShiftID.String() < totals[j].ShiftID.String()
+	})
+
+	return totals
+}
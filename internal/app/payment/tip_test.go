@@ -0,0 +1,36 @@
+package payment
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTipTotals(t *testing.T) {
+	t.Parallel()
+
+	shift := uuid.New()
+	tipped := func(barista string, shiftID uuid.UUID, tip money.Money, status Status) *Confirmation {
+		return &Confirmation{Barista: barista, ShiftID: shiftID, Tip: tip, Status: status}
+	}
+
+	totals := TipTotals([]*Confirmation{
+		tipped("ben", shift, money.New(80, "EUR"), StatusCaptured),
+		tipped("anna", shift, money.New(50, "EUR"), StatusCaptured),
+		tipped("anna", shift, money.New(120, "EUR"), StatusPartiallyRefunded),
+		tipped("anna", uuid.Nil, money.New(100, "EUR"), StatusCaptured),
+		tipped("anna", shift, money.New(30, "USD"), StatusCaptured),
+		tipped("anna", shift, money.New(0, "EUR"), StatusCaptured),
+		tipped("ben", shift, money.New(200, "EUR"), StatusVoided),
+		tipped("ben", shift, money.New(200, "EUR"), StatusRefunded),
+	})
+
+	assert.Equal(t, []*TipTotal{
+		{Barista: "anna", ShiftID: uuid.Nil, Amount: money.New(100, "EUR"), Payments: 1},
+		{Barista: "anna", ShiftID: shift, Amount: money.New(170, "EUR"), Payments: 2},
+		{Barista: "anna", ShiftID: shift, Amount: money.New(30, "USD"), Payments: 1},
+		{Barista: "ben", ShiftID: shift, Amount: money.New(80, "EUR"), Payments: 1},
+	}, totals)
+}
@@ -0,0 +1,86 @@
+package promotion
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/italolelis/coffee-shop/internal/app/order"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+)
+
+// Engine works out the discounts of orders from the stored promotions
+type Engine struct {
+	r   Reader
+	w   Writer
+	loc *time.Location
+	now func() time.Time
+}
+
+// NewEngine creates an engine that evaluates time windows, like happy hours, in the given location
+func NewEngine(r Reader, w Writer, loc *time.Location) *Engine {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	return &Engine{r: r, w: w, loc: loc, now: time.Now}
+}
+
+// Discounts returns a discount for every automatic promotion the order is eligible to,
+// followed by the discount of its coupon
+func (e *Engine) Discounts(ctx context.Context, o *order.Order) (order.Discounts, error) {
+	ctx, span := tracing.Start(ctx, "promotion/discounts")
+	defer span.End()
+
+	promotions, err := e.r.FetchAutomatic(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch promotions: %w", err)
+	}
+
+	if o.Coupon != "" {
+		p, err := e.r.FetchByCode(ctx, normalizeCode(o.Coupon))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.Usable(e.now()); err != nil {
+			return nil, err
+		}
+
+		promotions = append(promotions, p)
+	}
+
+	discounts := make(order.Discounts, 0, len(promotions))
+	for _, p := range promotions {
+		amount := p.Discount(o, e.loc)
+		if amount.IsZero() {
+			continue
+		}
+
+		discounts = append(discounts, &order.Discount{Promotion: p.Name, Code: p.Code, Amount: amount})
+	}
+
+	return discounts, nil
+}
+
+// Redeem counts a use of the coupon
+func (e *Engine) Redeem(ctx context.Context, code string) error {
+	ctx, span := tracing.Start(ctx, "promotion/redeem")
+	defer span.End()
+
+	return e.w.Redeem(ctx, normalizeCode(code), e.now())
+}
+
+// Release gives back a use of the coupon
+func (e *Engine) Release(ctx context.Context, code string) error {
+	ctx, span := tracing.Start(ctx, "promotion/release")
+	defer span.End()
+
+	return e.w.Release(ctx, normalizeCode(code))
+}
+
+// normalizeCode makes coupon codes case insensitive
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,120 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/italolelis/coffee-shop/internal/app/order"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore keeps the promotions in a slice, coupons are looked up by their exact code
+type fakeStore struct {
+	promotions []*Promotion
+}
+
+func (s *fakeStore) FetchAutomatic(context.Context) ([]*Promotion, error) {
+	var promotions []*Promotion
+	for _, p := range s.promotions {
+		if p.Code == "" {
+			promotions = append(promotions, p)
+		}
+	}
+
+	return promotions, nil
+}
+
+func (s *fakeStore) FetchByCode(_ context.Context, code string) (*Promotion, error) {
+	for _, p := range s.promotions {
+		if p.Code != "" && p.Code == code {
+			return p, nil
+		}
+	}
+
+	return nil, ErrCouponNotFound
+}
+
+func (s *fakeStore) Add(_ context.Context, p *Promotion) error {
+	s.promotions = append(s.promotions, p)
+	return nil
+}
+
+func (s *fakeStore) Redeem(ctx context.Context, code string, now time.Time) error {
+	p, err := s.FetchByCode(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	if err := p.Usable(now); err != nil {
+		return err
+	}
+
+	p.Uses++
+
+	return nil
+}
+
+func (s *fakeStore) Release(ctx context.Context, code string) error {
+	p, err := s.FetchByCode(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	p.Uses--
+
+	return nil
+}
+
+func TestEngine(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx   = context.Background()
+		now   = time.Date(2020, 6, 1, 15, 30, 0, 0, time.UTC)
+		store = &fakeStore{}
+	)
+
+	for _, p := range DefaultPromotions() {
+		require.NoError(t, store.Add(ctx, p))
+	}
+	require.NoError(t, store.Add(ctx, &Promotion{Name: "june", Kind: KindFixed, Amount: money.New(100, "EUR"), Code: "JUNE", ExpiresAt: now}))
+
+	e := NewEngine(store, store, time.UTC)
+	e.now = func() time.Time { return now }
+
+	o := newOrder(t, now, &order.Item{Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 3})
+
+	discounts, err := e.Discounts(ctx, o)
+	require.NoError(t, err)
+	assert.Equal(t, order.Discounts{
+		{Promotion: "happy hour", Amount: money.New(117, "EUR")},
+		{Promotion: "buy 2 lattes get 1 free", Amount: money.New(260, "EUR")},
+	}, discounts)
+
+	require.NoError(t, o.ApplyCoupon("welcome10"))
+	discounts, err = e.Discounts(ctx, o)
+	require.NoError(t, err)
+	require.Len(t, discounts, 3)
+	assert.Equal(t, &order.Discount{Promotion: "welcome", Code: "WELCOME10", Amount: money.New(78, "EUR")}, discounts[2])
+
+	require.NoError(t, e.Redeem(ctx, "welcome10"))
+	welcome, err := store.FetchByCode(ctx, "WELCOME10")
+	require.NoError(t, err)
+	assert.Equal(t, 1, welcome.Uses)
+
+	require.NoError(t, e.Release(ctx, "WELCOME10"))
+	assert.Equal(t, 0, welcome.Uses)
+
+	require.NoError(t, o.ApplyCoupon("june"))
+	_, err = e.Discounts(ctx, o)
+	assert.True(t, errors.Is(err, ErrCouponExpired))
+	assert.True(t, errors.Is(e.Redeem(ctx, "june"), ErrCouponExpired))
+
+	require.NoError(t, o.ApplyCoupon("unknown"))
+	_, err = e.Discounts(ctx, o)
+	assert.True(t, errors.Is(err, ErrCouponNotFound))
+}
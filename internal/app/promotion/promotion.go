@@ -0,0 +1,228 @@
+package promotion
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/italolelis/coffee-shop/internal/app/order"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+)
+
+// The coupon errors wrap order.ErrInvalidCoupon, so orders can tell them from storage failures
+var (
+	ErrCouponNotFound = fmt.Errorf("%w: not found", order.ErrInvalidCoupon)
+	ErrCouponExpired  = fmt.Errorf("%w: expired", order.ErrInvalidCoupon)
+	// ErrCouponUsedUp is returned when a coupon reached its usage limit
+	ErrCouponUsedUp = fmt.Errorf("%w: usage limit reached", order.ErrInvalidCoupon)
+)
+
+type Reader interface {
+	// FetchAutomatic returns the promotions that apply without a coupon
+	FetchAutomatic(context.Context) ([]*Promotion, error)
+	FetchByCode(context.Context, string) (*Promotion, error)
+}
+
+type Writer interface {
+	Add(context.Context, *Promotion) error
+	// Redeem counts a use of the coupon, failing when it's expired or used up at the given time
+	Redeem(ctx context.Context, code string, now time.Time) error
+	// Release gives back a use of the coupon
+	Release(ctx context.Context, code string) error
+}
+
+// Kind is how a promotion takes money off the order
+type Kind string
+
+const (
+	// KindPercentage takes a percentage off the eligible items
+	KindPercentage Kind = "percentage"
+	// KindFixed takes a fixed amount off the eligible items
+	KindFixed Kind = "fixed"
+	// KindBuyXGetY gives the cheapest Free items away for every Buy items bought
+	KindBuyXGetY Kind = "buy_x_get_y"
+)
+
+type (
+	// Promotion is a discount rule. Promotions with a code are coupons, which only
+	// apply to orders the customer applied the code to, the others apply automatically.
+	Promotion struct {
+		Name string `json:"name" db:"name"`
+		Kind Kind   `json:"kind" db:"kind"`
+		// Percent is taken off by percentage promotions
+		Percent int64 `json:"percent,omitempty" db:"percent"`
+		// Amount is taken off by fixed promotions
+		Amount money.Money `json:"amount,omitempty" db:"amount"`
+		// Buy and Free are the number of items bought and given away by buy X get Y promotions
+		Buy  int `json:"buy,omitempty" db:"buy"`
+		Free int `json:"free,omitempty" db:"free"`
+		// Products limits the promotion to some products, it applies to every product when empty
+		Products []string `json:"products,omitempty" db:"products"`
+		// Hours limits the promotion to orders created in a daily window, e.g. a happy hour
+		Hours *Window `json:"hours,omitempty" db:"hours"`
+		Code  string  `json:"code,omitempty" db:"code"`
+		// MaxUses is how many times the coupon can be redeemed, 0 means unlimited
+		MaxUses int `json:"max_uses,omitempty" db:"max_uses"`
+		Uses    int `json:"uses" db:"uses"`
+		// ExpiresAt is when the promotion ends, the zero time means it never does
+		ExpiresAt time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	}
+
+	// Window is a daily time window, as offsets from midnight. The window includes
+	// From but not To.
+	Window struct {
+		From time.Duration `json:"from" db:"from"`
+		To   time.Duration `json:"to" db:"to"`
+	}
+)
+
+// NewWindow creates a daily window between two times of the day, e.g. "15:00" and "17:00"
+func NewWindow(from, to string) (*Window, error) {
+	f, err := parseTimeOfDay(from)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := parseTimeOfDay(to)
+	if err != nil {
+		return nil, err
+	}
+
+	if t <= f {
+		return nil, fmt.Errorf("window must end after it starts, got %s-%s", from, to)
+	}
+
+	return &Window{From: f, To: t}, nil
+}
+
+// MustWindow is like NewWindow but panics on invalid times, it's meant for static promotions
+func MustWindow(from, to string) *Window {
+	w, err := NewWindow(from, to)
+	if err != nil {
+		panic(err)
+	}
+
+	return w
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q: %w", s, err)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains checks if the time of the day of t is in the window
+func (w *Window) Contains(t time.Time) bool {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := t.Sub(midnight)
+
+	return offset >= w.From && offset < w.To
+}
+
+// Usable checks that the coupon didn't expire and isn't used up at the given time
+func (p *Promotion) Usable(now time.Time) error {
+	if !p.ExpiresAt.IsZero() && !now.Before(p.ExpiresAt) {
+		return fmt.Errorf("%w: %s expired at %s", ErrCouponExpired, p.Code, p.ExpiresAt.Format(time.RFC3339))
+	}
+
+	if p.MaxUses > 0 && p.Uses >= p.MaxUses {
+		return fmt.Errorf("%w: %s was used %d times", ErrCouponUsedUp, p.Code, p.Uses)
+	}
+
+	return nil
+}
+
+// Discount works out how much the promotion takes off the order, which is zero
+// when the order isn't eligible
+func (p *Promotion) Discount(o *order.Order, loc *time.Location) money.Money {
+	zero := money.New(0, o.Currency())
+
+	if !p.ExpiresAt.IsZero() && !o.CreatedAt.Before(p.ExpiresAt) {
+		return zero
+	}
+
+	if p.Hours != nil && !p.Hours.Contains(o.CreatedAt.In(loc)) {
+		return zero
+	}
+
+	items := p.eligible(o.Items)
+	if len(items) == 0 {
+		return zero
+	}
+
+	switch p.Kind {
+	case KindPercentage:
+		subtotal := zero
+		for _, i := range items {
+			subtotal.Amount += i.Subtotal().Amount
+		}
+
+		return subtotal.MulFrac(p.Percent, 100, money.RoundHalfUp)
+	case KindFixed:
+		if !p.Amount.SameCurrency(zero) {
+			return zero
+		}
+
+		return p.Amount
+	case KindBuyXGetY:
+		return p.freeItems(items, zero)
+	default:
+		return zero
+	}
+}
+
+// freeItems sums the price of the cheapest Free items of every Buy+Free items
+func (p *Promotion) freeItems(items order.Items, zero money.Money) money.Money {
+	if p.Buy <= 0 || p.Free <= 0 {
+		return zero
+	}
+
+	var prices []int64
+	for _, i := range items {
+		for n := 0; n < i.Qty; n++ {
+			prices = append(prices, i.UnitPrice().Amount)
+		}
+	}
+
+	free := len(prices) / (p.Buy + p.Free) * p.Free
+	sort.Slice(prices, func(a, b int) bool { return prices[a] < prices[b] })
+
+	discount := zero
+	for _, price := range prices[:free] {
+		discount.Amount += price
+	}
+
+	return discount
+}
+
+func (p *Promotion) eligible(items order.Items) order.Items {
+	if len(p.Products) == 0 {
+		return items
+	}
+
+	eligible := make(order.Items, 0, len(items))
+	for _, i := range items {
+		for _, product := range p.Products {
+			if strings.EqualFold(i.Name, product) {
+				eligible = append(eligible, i)
+				break
+			}
+		}
+	}
+
+	return eligible
+}
+
+// DefaultPromotions returns the promotions run by default in the coffee shop
+func DefaultPromotions() []*Promotion {
+	return []*Promotion{
+		{Name: "happy hour", Kind: KindPercentage, Percent: 15, Hours: MustWindow("15:00", "17:00")},
+		{Name: "buy 2 lattes get 1 free", Kind: KindBuyXGetY, Buy: 2, Free: 1, Products: []string{"latte"}},
+		{Name: "welcome", Kind: KindPercentage, Percent: 10, Code: "WELCOME10", MaxUses: 1000},
+	}
+}
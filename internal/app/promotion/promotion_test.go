@@ -0,0 +1,179 @@
+package promotion
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/italolelis/coffee-shop/internal/app/order"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOrder(t *testing.T, createdAt time.Time, items ...*order.Item) *order.Order {
+	o := order.New("anna")
+	o.CreatedAt = createdAt
+	require.NoError(t, o.AddItems(items))
+
+	return o
+}
+
+func TestPromotion_Discount(t *testing.T) {
+	t.Parallel()
+
+	var (
+		afternoon = time.Date(2020, 6, 1, 15, 30, 0, 0, time.UTC)
+		evening   = time.Date(2020, 6, 1, 17, 0, 0, 0, time.UTC)
+		latte     = func(qty int) *order.Item {
+			return &order.Item{Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: qty}
+		}
+		espresso = &order.Item{Name: "espresso", ServingSize: "S", Price: money.New(180, "EUR"), Qty: 1}
+	)
+
+	tests := []struct {
+		name             string
+		promotion        *Promotion
+		order            *order.Order
+		expectedDiscount money.Money
+	}{
+		{
+			name:             "percentage",
+			promotion:        &Promotion{Name: "15 off", Kind: KindPercentage, Percent: 15},
+			order:            newOrder(t, afternoon, latte(2), espresso),
+			expectedDiscount: money.New(105, "EUR"),
+		},
+		{
+			name:             "percentage of some products",
+			promotion:        &Promotion{Name: "espresso 50 off", Kind: KindPercentage, Percent: 50, Products: []string{"Espresso"}},
+			order:            newOrder(t, afternoon, latte(2), espresso),
+			expectedDiscount: money.New(90, "EUR"),
+		},
+		{
+			name:             "no eligible products",
+			promotion:        &Promotion{Name: "mocha 50 off", Kind: KindPercentage, Percent: 50, Products: []string{"mocha"}},
+			order:            newOrder(t, afternoon, latte(2)),
+			expectedDiscount: money.New(0, "EUR"),
+		},
+		{
+			name:             "fixed",
+			promotion:        &Promotion{Name: "one off", Kind: KindFixed, Amount: money.New(100, "EUR")},
+			order:            newOrder(t, afternoon, espresso),
+			expectedDiscount: money.New(100, "EUR"),
+		},
+		{
+			name:             "fixed in another currency",
+			promotion:        &Promotion{Name: "one off", Kind: KindFixed, Amount: money.New(100, "USD")},
+			order:            newOrder(t, afternoon, espresso),
+			expectedDiscount: money.New(0, "EUR"),
+		},
+		{
+			name:             "buy 2 get 1",
+			promotion:        &Promotion{Name: "3 for 2", Kind: KindBuyXGetY, Buy: 2, Free: 1},
+			order:            newOrder(t, afternoon, latte(2), espresso),
+			expectedDiscount: money.New(180, "EUR"),
+		},
+		{
+			name:             "buy 2 get 1 twice",
+			promotion:        &Promotion{Name: "3 for 2", Kind: KindBuyXGetY, Buy: 2, Free: 1, Products: []string{"latte"}},
+			order:            newOrder(t, afternoon, latte(7), espresso),
+			expectedDiscount: money.New(520, "EUR"),
+		},
+		{
+			name:             "buy 2 get 1 without enough items",
+			promotion:        &Promotion{Name: "3 for 2", Kind: KindBuyXGetY, Buy: 2, Free: 1},
+			order:            newOrder(t, afternoon, latte(2)),
+			expectedDiscount: money.New(0, "EUR"),
+		},
+		{
+			name:             "happy hour",
+			promotion:        &Promotion{Name: "happy hour", Kind: KindPercentage, Percent: 10, Hours: MustWindow("15:00", "17:00")},
+			order:            newOrder(t, afternoon, latte(1)),
+			expectedDiscount: money.New(26, "EUR"),
+		},
+		{
+			name:             "after happy hour",
+			promotion:        &Promotion{Name: "happy hour", Kind: KindPercentage, Percent: 10, Hours: MustWindow("15:00", "17:00")},
+			order:            newOrder(t, evening, latte(1)),
+			expectedDiscount: money.New(0, "EUR"),
+		},
+		{
+			name:             "ended",
+			promotion:        &Promotion{Name: "june", Kind: KindPercentage, Percent: 10, ExpiresAt: afternoon},
+			order:            newOrder(t, evening, latte(1)),
+			expectedDiscount: money.New(0, "EUR"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expectedDiscount, tt.promotion.Discount(tt.order, time.UTC))
+		})
+	}
+}
+
+func TestPromotion_DiscountInLocation(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// 13:30 UTC is 15:30 in Berlin during summer time
+	o := newOrder(t, time.Date(2020, 6, 1, 13, 30, 0, 0, time.UTC),
+		&order.Item{Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 1})
+	p := &Promotion{Name: "happy hour", Kind: KindPercentage, Percent: 10, Hours: MustWindow("15:00", "17:00")}
+
+	assert.Equal(t, money.New(26, "EUR"), p.Discount(o, berlin))
+	assert.Equal(t, money.New(0, "EUR"), p.Discount(o, time.UTC))
+}
+
+func TestPromotion_Usable(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		promotion   *Promotion
+		expectedErr error
+	}{
+		{name: "unlimited", promotion: &Promotion{Code: "FREE", Uses: 100}},
+		{name: "uses left", promotion: &Promotion{Code: "FREE", MaxUses: 2, Uses: 1, ExpiresAt: now.Add(time.Hour)}},
+		{name: "used up", promotion: &Promotion{Code: "FREE", MaxUses: 2, Uses: 2}, expectedErr: ErrCouponUsedUp},
+		{name: "expired", promotion: &Promotion{Code: "FREE", ExpiresAt: now}, expectedErr: ErrCouponExpired},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.promotion.Usable(now)
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, errors.Is(err, tt.expectedErr))
+			assert.True(t, errors.Is(err, order.ErrInvalidCoupon))
+		})
+	}
+}
+
+func TestNewWindow(t *testing.T) {
+	t.Parallel()
+
+	w, err := NewWindow("15:00", "17:00")
+	require.NoError(t, err)
+	assert.True(t, w.Contains(time.Date(2020, 6, 1, 15, 0, 0, 0, time.UTC)))
+	assert.True(t, w.Contains(time.Date(2020, 6, 1, 16, 59, 59, 0, time.UTC)))
+	assert.False(t, w.Contains(time.Date(2020, 6, 1, 17, 0, 0, 0, time.UTC)))
+	assert.False(t, w.Contains(time.Date(2020, 6, 1, 14, 59, 0, 0, time.UTC)))
+
+	_, err = NewWindow("17:00", "15:00")
+	assert.Error(t, err)
+
+	_, err = NewWindow("3pm", "5pm")
+	assert.Error(t, err)
+}
@@ -0,0 +1,44 @@
+package bolt
+
+import (
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	ordersBucket = []byte("orders")
+	// orderVersionsBucket keeps the version of each order, which isn't part of its JSON
+	orderVersionsBucket = []byte("order_versions")
+	cartsBucket         = []byte("carts")
+	sagasBucket         = []byte("checkout_sagas")
+	idemBucket          = []byte("idempotency_keys")
+	// promotionsBucket holds the automatic promotions, couponsBucket the ones with a code
+	promotionsBucket = []byte("promotions")
+	couponsBucket    = []byte("coupons")
+)
+
+// Open opens the database file, creating it and its buckets when needed
+func Open(path string) (*bolt.DB, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database file: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{ordersBucket, orderVersionsBucket, cartsBucket, sagasBucket, idemBucket, promotionsBucket, couponsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create buckets: %w", err)
+	}
+
+	return db, nil
+}
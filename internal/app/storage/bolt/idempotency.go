@@ -0,0 +1,128 @@
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/italolelis/coffee-shop/internal/pkg/idempotency"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+	bolt "go.etcd.io/bbolt"
+)
+
+// IdempotencyStore keeps the idempotency keys as JSON in the same file as the orders,
+// so a retried request is still recognized after a restart
+type IdempotencyStore struct {
+	db     *bolt.DB
+	window time.Duration
+}
+
+func NewIdempotencyStore(db *bolt.DB, window time.Duration) *IdempotencyStore {
+	return &IdempotencyStore{db: db, window: window}
+}
+
+func (s *IdempotencyStore) Reserve(ctx context.Context, key string, fingerprint string) (*idempotency.Record, error) {
+	ctx, span := tracing.Start(ctx, "storage/idempotency/reserve")
+	defer span.End()
+
+	now := time.Now().UTC()
+
+	var rec *idempotency.Record
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(idemBucket)
+
+		stored, err := s.get(b, key)
+		if err != nil {
+			return err
+		}
+
+		if stored != nil && now.Sub(stored.CreatedAt) < s.window {
+			if stored.Fingerprint != fingerprint {
+				return idempotency.ErrKeyReused
+			}
+
+			if !stored.Completed {
+				return idempotency.ErrInProgress
+			}
+
+			rec = stored
+
+			return nil
+		}
+
+		return s.put(b, &idempotency.Record{Key: key, Fingerprint: fingerprint, CreatedAt: now})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rec, nil
+}
+
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, response []byte) error {
+	ctx, span := tracing.Start(ctx, "storage/idempotency/complete")
+	defer span.End()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(idemBucket)
+
+		rec, err := s.get(b, key)
+		if err != nil || rec == nil {
+			return err
+		}
+
+		rec.Response = response
+		rec.Completed = true
+
+		return s.put(b, rec)
+	})
+}
+
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	ctx, span := tracing.Start(ctx, "storage/idempotency/release")
+	defer span.End()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(idemBucket)
+
+		rec, err := s.get(b, key)
+		if err != nil || rec == nil || rec.Completed {
+			return err
+		}
+
+		if err := b.Delete([]byte(key)); err != nil {
+			return fmt.Errorf("failed to release idempotency key: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// get returns the record of the key, nil when the key was never reserved
+func (s *IdempotencyStore) get(b *bolt.Bucket, key string) (*idempotency.Record, error) {
+	data := b.Get([]byte(key))
+	if data == nil {
+		return nil, nil
+	}
+
+	var rec idempotency.Record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("failed to decode idempotency key: %w", err)
+	}
+
+	return &rec, nil
+}
+
+func (s *IdempotencyStore) put(b *bolt.Bucket, rec *idempotency.Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode idempotency key: %w", err)
+	}
+
+	if err := b.Put([]byte(rec.Key), data); err != nil {
+		return fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	return nil
+}
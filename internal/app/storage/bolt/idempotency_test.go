@@ -0,0 +1,107 @@
+package bolt
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/italolelis/coffee-shop/internal/pkg/idempotency"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyStore_Reserve(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "coffee-shop")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		ctx  = context.Background()
+		path = filepath.Join(dir, "orders.db")
+	)
+
+	db, err := Open(path)
+	require.NoError(t, err)
+
+	s := NewIdempotencyStore(db, time.Hour)
+
+	rec, err := s.Reserve(ctx, "key", "request")
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+
+	_, err = s.Reserve(ctx, "key", "request")
+	assert.True(t, errors.Is(err, idempotency.ErrInProgress))
+
+	require.NoError(t, s.Complete(ctx, "key", []byte("response")))
+	require.NoError(t, db.Close())
+
+	db, err = Open(path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	s = NewIdempotencyStore(db, time.Hour)
+
+	rec, err = s.Reserve(ctx, "key", "request")
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, []byte("response"), rec.Response)
+
+	_, err = s.Reserve(ctx, "key", "another request")
+	assert.True(t, errors.Is(err, idempotency.ErrKeyReused))
+}
+
+func TestIdempotencyStore_Release(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "coffee-shop")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := Open(filepath.Join(dir, "orders.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	var (
+		ctx = context.Background()
+		s   = NewIdempotencyStore(db, time.Hour)
+	)
+
+	_, err = s.Reserve(ctx, "key", "request")
+	require.NoError(t, err)
+	require.NoError(t, s.Release(ctx, "key"))
+
+	rec, err := s.Reserve(ctx, "key", "request")
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+}
+
+func TestIdempotencyStore_Window(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "coffee-shop")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := Open(filepath.Join(dir, "orders.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	var (
+		ctx = context.Background()
+		s   = NewIdempotencyStore(db, 0)
+	)
+
+	_, err = s.Reserve(ctx, "key", "request")
+	require.NoError(t, err)
+	require.NoError(t, s.Complete(ctx, "key", []byte("response")))
+
+	rec, err := s.Reserve(ctx, "key", "request")
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+}
@@ -0,0 +1,141 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/order"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+	bolt "go.etcd.io/bbolt"
+)
+
+// OrderReadWrite stores orders as JSON in a single file, keeping an index
+// of the open order of each customer
+type OrderReadWrite struct {
+	db *bolt.DB
+}
+
+func NewOrderReadWrite(db *bolt.DB) *OrderReadWrite {
+	return &OrderReadWrite{db: db}
+}
+
+func (r *OrderReadWrite) FetchByID(ctx context.Context, id uuid.UUID) (*order.Order, error) {
+	ctx, span := tracing.Start(ctx, "storage/order/fetch-by-id")
+	defer span.End()
+
+	var o *order.Order
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		o, err = fetchOrder(tx, id[:])
+		return err
+	})
+
+	return o, err
+}
+
+func (r *OrderReadWrite) FetchActiveByCustomer(ctx context.Context, customerName string) (*order.Order, error) {
+	ctx, span := tracing.Start(ctx, "storage/order/fetch-active-by-customer")
+	defer span.End()
+
+	var o *order.Order
+	err := r.db.View(func(tx *bolt.Tx) error {
+		id := tx.Bucket(cartsBucket).Get([]byte(customerName))
+		if id == nil {
+			return order.ErrNotFound
+		}
+
+		var err error
+		o, err = fetchOrder(tx, id)
+		return err
+	})
+
+	return o, err
+}
+
+// Add stores the order, replacing any previously stored version of it. The order is only
+// replaced when it wasn't saved since it was fetched, otherwise order.ErrConflict is
+// returned and the caller has to fetch it again. A customer can only have one open order.
+func (r *OrderReadWrite) Add(ctx context.Context, o *order.Order) error {
+	ctx, span := tracing.Start(ctx, "storage/order/add")
+	defer span.End()
+
+	data, err := json.Marshal(o)
+	if err != nil {
+		return fmt.Errorf("failed to encode order: %w", err)
+	}
+
+	err = r.db.Update(func(tx *bolt.Tx) error {
+		exists := tx.Bucket(ordersBucket).Get(o.ID[:]) != nil
+		switch {
+		case o.Version == 0 && exists:
+			return fmt.Errorf("%w: %s already exists", order.ErrConflict, o.ID)
+		case o.Version != 0 && (!exists || fetchVersion(tx, o.ID[:]) != o.Version):
+			return fmt.Errorf("%w: %s was saved since version %d", order.ErrConflict, o.ID, o.Version)
+		}
+
+		carts := tx.Bucket(cartsBucket)
+		customer := []byte(o.CustomerName)
+
+		if id := carts.Get(customer); id != nil && !bytes.Equal(id, o.ID[:]) && o.Status == order.StatusOpen {
+			return fmt.Errorf("%w: %s already has an open order", order.ErrConflict, o.CustomerName)
+		}
+
+		if err := tx.Bucket(ordersBucket).Put(o.ID[:], data); err != nil {
+			return fmt.Errorf("failed to save order: %w", err)
+		}
+
+		version := make([]byte, 8)
+		binary.BigEndian.PutUint64(version, uint64(o.Version+1))
+		if err := tx.Bucket(orderVersionsBucket).Put(o.ID[:], version); err != nil {
+			return fmt.Errorf("failed to save order version: %w", err)
+		}
+
+		if o.Status == order.StatusOpen {
+			return carts.Put(customer, o.ID[:])
+		}
+
+		if bytes.Equal(carts.Get(customer), o.ID[:]) {
+			return carts.Delete(customer)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	o.Version++
+
+	return nil
+}
+
+func fetchOrder(tx *bolt.Tx, id []byte) (*order.Order, error) {
+	data := tx.Bucket(ordersBucket).Get(id)
+	if data == nil {
+		return nil, order.ErrNotFound
+	}
+
+	var o order.Order
+	if err := json.Unmarshal(data, &o); err != nil {
+		return nil, fmt.Errorf("failed to decode order: %w", err)
+	}
+
+	o.Version = fetchVersion(tx, id)
+
+	return &o, nil
+}
+
+// fetchVersion reads the version of a stored order, orders saved before versions were
+// kept count as the first one
+func fetchVersion(tx *bolt.Tx, id []byte) int {
+	data := tx.Bucket(orderVersionsBucket).Get(id)
+	if data == nil {
+		return 1
+	}
+
+	return int(binary.BigEndian.Uint64(data))
+}
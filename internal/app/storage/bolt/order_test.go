@@ -0,0 +1,102 @@
+package bolt
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/order"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderReadWrite_SurvivesReopen(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "coffee-shop")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		ctx  = context.Background()
+		path = filepath.Join(dir, "orders.db")
+	)
+
+	db, err := Open(path)
+	require.NoError(t, err)
+
+	o := order.New("anna")
+	require.NoError(t, o.AddItem(&order.Item{Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 2}))
+	require.NoError(t, NewOrderReadWrite(db).Add(ctx, o))
+	require.NoError(t, db.Close())
+
+	db, err = Open(path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	rw := NewOrderReadWrite(db)
+
+	active, err := rw.FetchActiveByCustomer(ctx, "anna")
+	require.NoError(t, err)
+	assert.Equal(t, o.ID, active.ID)
+	assert.Equal(t, o.Total(), active.Total())
+
+	require.NoError(t, active.Checkout())
+	require.NoError(t, active.MarkPaid(uuid.New()))
+	require.NoError(t, rw.Add(ctx, active))
+
+	_, err = rw.FetchActiveByCustomer(ctx, "anna")
+	assert.True(t, errors.Is(err, order.ErrNotFound))
+
+	fetched, err := rw.FetchByID(ctx, o.ID)
+	require.NoError(t, err)
+	assert.Equal(t, order.StatusPaid, fetched.Status)
+	assert.Equal(t, active.PaymentID, fetched.PaymentID)
+}
+
+func TestOrderReadWrite_Conflicts(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "coffee-shop")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := Open(filepath.Join(dir, "orders.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	var (
+		ctx = context.Background()
+		rw  = NewOrderReadWrite(db)
+	)
+
+	o := order.New("anna")
+	require.NoError(t, rw.Add(ctx, o))
+	assert.Equal(t, 1, o.Version)
+
+	stale, err := rw.FetchByID(ctx, o.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stale.Version)
+
+	require.NoError(t, o.AddItem(&order.Item{Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 1}))
+	require.NoError(t, rw.Add(ctx, o))
+	assert.Equal(t, 2, o.Version)
+
+	require.NoError(t, stale.AddItem(&order.Item{Name: "espresso", ServingSize: "S", Price: money.New(180, "EUR"), Qty: 1}))
+	err = rw.Add(ctx, stale)
+	assert.True(t, errors.Is(err, order.ErrConflict))
+
+	err = rw.Add(ctx, order.New("anna"))
+	assert.True(t, errors.Is(err, order.ErrConflict))
+
+	stored, err := rw.FetchByID(ctx, o.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored.Version)
+	require.Len(t, stored.Items, 1)
+	assert.Equal(t, "latte", stored.Items[0].Name)
+}
@@ -0,0 +1,193 @@
+package bolt
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/italolelis/coffee-shop/internal/app/promotion"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+	bolt "go.etcd.io/bbolt"
+)
+
+// PromotionReadWrite stores promotions as JSON in the same file as the orders. Coupons
+// are keyed by their code, automatic promotions by the order they were added in.
+type PromotionReadWrite struct {
+	db *bolt.DB
+}
+
+func NewPromotionReadWrite(db *bolt.DB) *PromotionReadWrite {
+	return &PromotionReadWrite{db: db}
+}
+
+func (r *PromotionReadWrite) FetchAutomatic(ctx context.Context) ([]*promotion.Promotion, error) {
+	ctx, span := tracing.Start(ctx, "storage/promotion/fetch-automatic")
+	defer span.End()
+
+	promotions := make([]*promotion.Promotion, 0)
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(promotionsBucket).ForEach(func(_, data []byte) error {
+			p, err := decodePromotion(data)
+			if err != nil {
+				return err
+			}
+
+			promotions = append(promotions, p)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return promotions, nil
+}
+
+func (r *PromotionReadWrite) FetchByCode(ctx context.Context, code string) (*promotion.Promotion, error) {
+	ctx, span := tracing.Start(ctx, "storage/promotion/fetch-by-code")
+	defer span.End()
+
+	var p *promotion.Promotion
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		p, err = fetchCoupon(tx, code)
+		return err
+	})
+
+	return p, err
+}
+
+func (r *PromotionReadWrite) Add(ctx context.Context, p *promotion.Promotion) error {
+	ctx, span := tracing.Start(ctx, "storage/promotion/add")
+	defer span.End()
+
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return addPromotion(tx, p)
+	})
+}
+
+// Seed adds the promotions when none are stored yet, so the defaults don't come back
+// after they were changed
+func (r *PromotionReadWrite) Seed(ctx context.Context, promotions ...*promotion.Promotion) error {
+	ctx, span := tracing.Start(ctx, "storage/promotion/seed")
+	defer span.End()
+
+	return r.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(promotionsBucket).Stats().KeyN > 0 || tx.Bucket(couponsBucket).Stats().KeyN > 0 {
+			return nil
+		}
+
+		for _, p := range promotions {
+			if err := addPromotion(tx, p); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Redeem checks and counts the use in the same write transaction, bolt only runs one
+// at a time so concurrent redemptions can't go over the usage limit of the coupon
+func (r *PromotionReadWrite) Redeem(ctx context.Context, code string, now time.Time) error {
+	ctx, span := tracing.Start(ctx, "storage/promotion/redeem")
+	defer span.End()
+
+	return r.db.Update(func(tx *bolt.Tx) error {
+		p, err := fetchCoupon(tx, code)
+		if err != nil {
+			return err
+		}
+
+		if err := p.Usable(now); err != nil {
+			return err
+		}
+
+		p.Uses++
+
+		return putCoupon(tx, p)
+	})
+}
+
+func (r *PromotionReadWrite) Release(ctx context.Context, code string) error {
+	ctx, span := tracing.Start(ctx, "storage/promotion/release")
+	defer span.End()
+
+	return r.db.Update(func(tx *bolt.Tx) error {
+		p, err := fetchCoupon(tx, code)
+		if err != nil {
+			return err
+		}
+
+		if p.Uses > 0 {
+			p.Uses--
+		}
+
+		return putCoupon(tx, p)
+	})
+}
+
+func addPromotion(tx *bolt.Tx, p *promotion.Promotion) error {
+	if p.Code != "" {
+		pc := *p
+		pc.Code = strings.ToUpper(pc.Code)
+
+		return putCoupon(tx, &pc)
+	}
+
+	b := tx.Bucket(promotionsBucket)
+
+	seq, err := b.NextSequence()
+	if err != nil {
+		return fmt.Errorf("failed to save promotion: %w", err)
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to encode promotion: %w", err)
+	}
+
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+
+	if err := b.Put(key, data); err != nil {
+		return fmt.Errorf("failed to save promotion: %w", err)
+	}
+
+	return nil
+}
+
+func fetchCoupon(tx *bolt.Tx, code string) (*promotion.Promotion, error) {
+	data := tx.Bucket(couponsBucket).Get([]byte(strings.ToUpper(code)))
+	if data == nil {
+		return nil, promotion.ErrCouponNotFound
+	}
+
+	return decodePromotion(data)
+}
+
+func putCoupon(tx *bolt.Tx, p *promotion.Promotion) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to encode promotion: %w", err)
+	}
+
+	if err := tx.Bucket(couponsBucket).Put([]byte(p.Code), data); err != nil {
+		return fmt.Errorf("failed to save promotion: %w", err)
+	}
+
+	return nil
+}
+
+func decodePromotion(data []byte) (*promotion.Promotion, error) {
+	var p promotion.Promotion
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode promotion: %w", err)
+	}
+
+	return &p, nil
+}
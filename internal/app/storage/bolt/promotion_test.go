@@ -0,0 +1,114 @@
+package bolt
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/italolelis/coffee-shop/internal/app/promotion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromotionReadWrite_Seed(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "coffee-shop")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		ctx  = context.Background()
+		path = filepath.Join(dir, "orders.db")
+	)
+
+	db, err := Open(path)
+	require.NoError(t, err)
+
+	rw := NewPromotionReadWrite(db)
+	require.NoError(t, rw.Seed(ctx, promotion.DefaultPromotions()...))
+	require.NoError(t, rw.Redeem(ctx, "welcome10", time.Now()))
+	require.NoError(t, db.Close())
+
+	db, err = Open(path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	// the promotions are kept across restarts and not seeded again
+	rw = NewPromotionReadWrite(db)
+	require.NoError(t, rw.Seed(ctx, promotion.DefaultPromotions()...))
+
+	automatic, err := rw.FetchAutomatic(ctx)
+	require.NoError(t, err)
+	require.Len(t, automatic, 2)
+	assert.Equal(t, "happy hour", automatic[0].Name)
+	assert.Equal(t, promotion.MustWindow("15:00", "17:00"), automatic[0].Hours)
+	assert.Equal(t, []string{"latte"}, automatic[1].Products)
+
+	welcome, err := rw.FetchByCode(ctx, "WELCOME10")
+	require.NoError(t, err)
+	assert.Equal(t, 1, welcome.Uses)
+
+	_, err = rw.FetchByCode(ctx, "unknown")
+	assert.True(t, errors.Is(err, promotion.ErrCouponNotFound))
+}
+
+func TestPromotionReadWrite_Redeem(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "coffee-shop")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := Open(filepath.Join(dir, "orders.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	var (
+		ctx = context.Background()
+		now = time.Date(2020, 6, 1, 15, 30, 0, 0, time.UTC)
+		rw  = NewPromotionReadWrite(db)
+	)
+
+	require.NoError(t, rw.Add(ctx, &promotion.Promotion{Name: "limited", Kind: promotion.KindPercentage, Percent: 10, Code: "limited", MaxUses: 5}))
+	require.NoError(t, rw.Add(ctx, &promotion.Promotion{Name: "june", Kind: promotion.KindPercentage, Percent: 10, Code: "JUNE", ExpiresAt: now}))
+
+	// more customers than uses redeem the coupon at once, only MaxUses of them get it
+	var (
+		wg      sync.WaitGroup
+		mux     sync.Mutex
+		redeems int
+	)
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := rw.Redeem(ctx, "LIMITED", now)
+			if err == nil {
+				mux.Lock()
+				redeems++
+				mux.Unlock()
+				return
+			}
+
+			assert.True(t, errors.Is(err, promotion.ErrCouponUsedUp), err)
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(t, 5, redeems)
+
+	require.NoError(t, rw.Release(ctx, "limited"))
+	require.NoError(t, rw.Redeem(ctx, "limited", now))
+
+	assert.True(t, errors.Is(rw.Redeem(ctx, "june", now), promotion.ErrCouponExpired))
+	assert.True(t, errors.Is(rw.Redeem(ctx, "unknown", now), promotion.ErrCouponNotFound))
+	assert.True(t, errors.Is(rw.Release(ctx, "unknown"), promotion.ErrCouponNotFound))
+}
@@ -0,0 +1,73 @@
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/italolelis/coffee-shop/internal/app/order"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+	bolt "go.etcd.io/bbolt"
+)
+
+// SagaReadWrite stores checkout sagas as JSON in the same file as the orders
+type SagaReadWrite struct {
+	db *bolt.DB
+}
+
+func NewSagaReadWrite(db *bolt.DB) *SagaReadWrite {
+	return &SagaReadWrite{db: db}
+}
+
+// FetchIncomplete returns the sagas that didn't reach a final step and weren't updated
+// since the given time, oldest first
+func (r *SagaReadWrite) FetchIncomplete(ctx context.Context, updatedBefore time.Time) ([]*order.CheckoutSaga, error) {
+	ctx, span := tracing.Start(ctx, "storage/saga/fetch-incomplete")
+	defer span.End()
+
+	sagas := make([]*order.CheckoutSaga, 0)
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(sagasBucket).ForEach(func(_, data []byte) error {
+			var s order.CheckoutSaga
+			if err := json.Unmarshal(data, &s); err != nil {
+				return fmt.Errorf("failed to decode checkout saga: %w", err)
+			}
+
+			if !s.Step.Done() && s.UpdatedAt.Before(updatedBefore) {
+				sagas = append(sagas, &s)
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(sagas, func(i, j int) bool {
+		return sagas[i].CreatedAt.Before(sagas[j].CreatedAt)
+	})
+
+	return sagas, nil
+}
+
+// Add stores the saga, replacing any previously stored version of it
+func (r *SagaReadWrite) Add(ctx context.Context, s *order.CheckoutSaga) error {
+	ctx, span := tracing.Start(ctx, "storage/saga/add")
+	defer span.End()
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkout saga: %w", err)
+	}
+
+	return r.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(sagasBucket).Put(s.ID[:], data); err != nil {
+			return fmt.Errorf("failed to save checkout saga: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,60 @@
+package bolt
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSagaReadWrite_FetchIncomplete(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "coffee-shop")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		ctx  = context.Background()
+		path = filepath.Join(dir, "orders.db")
+	)
+
+	db, err := Open(path)
+	require.NoError(t, err)
+
+	stale := time.Now().UTC().Add(-time.Hour)
+
+	pending := order.NewCheckoutSaga(uuid.New())
+	pending.Step, pending.PaymentID, pending.UpdatedAt = order.SagaPaid, uuid.New(), stale
+
+	done := order.NewCheckoutSaga(uuid.New())
+	done.Step, done.UpdatedAt = order.SagaCompleted, stale
+
+	// still running, so it's not resumed yet
+	running := order.NewCheckoutSaga(uuid.New())
+	running.Step, running.PaymentID = order.SagaPaid, uuid.New()
+
+	rw := NewSagaReadWrite(db)
+	require.NoError(t, rw.Add(ctx, pending))
+	require.NoError(t, rw.Add(ctx, done))
+	require.NoError(t, rw.Add(ctx, running))
+	require.NoError(t, db.Close())
+
+	db, err = Open(path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	sagas, err := NewSagaReadWrite(db).FetchIncomplete(ctx, time.Now().UTC().Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, sagas, 1)
+	assert.Equal(t, pending.ID, sagas[0].ID)
+	assert.Equal(t, pending.PaymentID, sagas[0].PaymentID)
+	assert.Equal(t, order.SagaPaid, sagas[0].Step)
+}
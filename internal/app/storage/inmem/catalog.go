@@ -0,0 +1,51 @@
+package inmem
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/italolelis/coffee-shop/internal/app/catalog"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+)
+
+type CatalogReadWrite struct {
+	mux      *sync.RWMutex
+	products map[string]*catalog.Product
+}
+
+func NewCatalogReadWrite(products ...*catalog.Product) *CatalogReadWrite {
+	c := &CatalogReadWrite{mux: &sync.RWMutex{}, products: make(map[string]*catalog.Product, len(products))}
+	for _, p := range products {
+		c.products[strings.ToLower(p.Name)] = p
+	}
+
+	return c
+}
+
+func (r *CatalogReadWrite) FetchByName(ctx context.Context, name string) (*catalog.Product, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	_, span := tracing.Start(ctx, "storage/catalog/fetch-by-name")
+	defer span.End()
+
+	p, ok := r.products[strings.ToLower(name)]
+	if !ok {
+		return nil, catalog.ErrProductNotFound
+	}
+
+	return p, nil
+}
+
+func (r *CatalogReadWrite) Add(ctx context.Context, p *catalog.Product) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	_, span := tracing.Start(ctx, "storage/catalog/add")
+	defer span.End()
+
+	r.products[strings.ToLower(p.Name)] = p
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package inmem
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/italolelis/coffee-shop/internal/app/catalog"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogReadWrite_FetchByName(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rw := NewCatalogReadWrite(catalog.NewProduct("Latte", catalog.NewSize("M", money.New(260, "EUR"))))
+
+	p, err := rw.FetchByName(ctx, "latte")
+	require.NoError(t, err)
+	assert.Equal(t, "Latte", p.Name)
+
+	_, err = rw.FetchByName(ctx, "mocha")
+	assert.True(t, errors.Is(err, catalog.ErrProductNotFound))
+
+	require.NoError(t, rw.Add(ctx, catalog.NewProduct("mocha", catalog.NewSize("M", money.New(300, "EUR")))))
+	p, err = rw.FetchByName(ctx, "MOCHA")
+	require.NoError(t, err)
+
+	price, err := p.Price("M")
+	require.NoError(t, err)
+	assert.Equal(t, money.New(300, "EUR"), price)
+}
@@ -0,0 +1,101 @@
+package inmem
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+)
+
+type DrawerReadWrite struct {
+	mux      *sync.RWMutex
+	sessions map[uuid.UUID]*payment.DrawerSession
+	open     uuid.UUID
+}
+
+func NewDrawerReadWrite() *DrawerReadWrite {
+	return &DrawerReadWrite{
+		mux:      &sync.RWMutex{},
+		sessions: make(map[uuid.UUID]*payment.DrawerSession, 0),
+	}
+}
+
+func (r *DrawerReadWrite) FetchByID(ctx context.Context, id uuid.UUID) (*payment.DrawerSession, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	ctx, span := tracing.Start(ctx, "storage/drawer/fetch-by-id")
+	defer span.End()
+
+	d, ok := r.sessions[id]
+	if !ok {
+		return nil, payment.ErrDrawerNotFound
+	}
+
+	dc := *d
+
+	return &dc, nil
+}
+
+func (r *DrawerReadWrite) FetchOpen(ctx context.Context) (*payment.DrawerSession, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	ctx, span := tracing.Start(ctx, "storage/drawer/fetch-open")
+	defer span.End()
+
+	d, ok := r.sessions[r.open]
+	if !ok {
+		return nil, payment.ErrDrawerClosed
+	}
+
+	dc := *d
+
+	return &dc, nil
+}
+
+func (r *DrawerReadWrite) Open(ctx context.Context, d *payment.DrawerSession) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	ctx, span := tracing.Start(ctx, "storage/drawer/open")
+	defer span.End()
+
+	if _, ok := r.sessions[r.open]; ok {
+		return payment.ErrDrawerAlreadyOpen
+	}
+
+	dc := *d
+	r.sessions[d.ID] = &dc
+	r.open = d.ID
+
+	return nil
+}
+
+func (r *DrawerReadWrite) UpdateOpen(ctx context.Context, fn func(*payment.DrawerSession) error) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	ctx, span := tracing.Start(ctx, "storage/drawer/update-open")
+	defer span.End()
+
+	d, ok := r.sessions[r.open]
+	if !ok {
+		return payment.ErrDrawerClosed
+	}
+
+	// fn works on a copy, so a failed change leaves the stored session untouched
+	dc := *d
+	if err := fn(&dc); err != nil {
+		return err
+	}
+
+	r.sessions[dc.ID] = &dc
+	if dc.Status != payment.DrawerOpen {
+		r.open = uuid.Nil
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package inmem
+
+import (
+	"context"
+	"sync"
+
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+)
+
+type GiftCardReadWrite struct {
+	mux   *sync.RWMutex
+	cards map[string]*payment.GiftCardAccount
+}
+
+func NewGiftCardReadWrite() *GiftCardReadWrite {
+	return &GiftCardReadWrite{
+		mux:   &sync.RWMutex{},
+		cards: make(map[string]*payment.GiftCardAccount, 0),
+	}
+}
+
+func (r *GiftCardReadWrite) FetchByNumber(ctx context.Context, number string) (*payment.GiftCardAccount, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	ctx, span := tracing.Start(ctx, "storage/gift-card/fetch-by-number")
+	defer span.End()
+
+	card, ok := r.cards[number]
+	if !ok {
+		return nil, payment.ErrGiftCardNotFound
+	}
+
+	cc := *card
+
+	return &cc, nil
+}
+
+func (r *GiftCardReadWrite) Add(ctx context.Context, card *payment.GiftCardAccount) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	ctx, span := tracing.Start(ctx, "storage/gift-card/add")
+	defer span.End()
+
+	if _, ok := r.cards[card.Number]; ok {
+		return payment.ErrGiftCardExists
+	}
+
+	cc := *card
+	r.cards[card.Number] = &cc
+
+	return nil
+}
+
+func (r *GiftCardReadWrite) Update(ctx context.Context, number string, fn func(*payment.GiftCardAccount) error) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	ctx, span := tracing.Start(ctx, "storage/gift-card/update")
+	defer span.End()
+
+	card, ok := r.cards[number]
+	if !ok {
+		return payment.ErrGiftCardNotFound
+	}
+
+	// fn works on a copy, so a failed change leaves the stored card untouched
+	cc := *card
+	if err := fn(&cc); err != nil {
+		return err
+	}
+
+	r.cards[number] = &cc
+
+	return nil
+}
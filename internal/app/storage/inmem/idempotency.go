@@ -0,0 +1,92 @@
+package inmem
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/italolelis/coffee-shop/internal/pkg/idempotency"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+)
+
+type IdempotencyStore struct {
+	mux     *sync.Mutex
+	window  time.Duration
+	records map[string]*idempotency.Record
+}
+
+func NewIdempotencyStore(window time.Duration) *IdempotencyStore {
+	return &IdempotencyStore{
+		mux:     &sync.Mutex{},
+		window:  window,
+		records: make(map[string]*idempotency.Record, 0),
+	}
+}
+
+func (s *IdempotencyStore) Reserve(ctx context.Context, key string, fingerprint string) (*idempotency.Record, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	ctx, span := tracing.Start(ctx, "storage/idempotency/reserve")
+	defer span.End()
+
+	now := time.Now().UTC()
+	s.evictExpired(now)
+
+	rec, ok := s.records[key]
+	if ok {
+		if rec.Fingerprint != fingerprint {
+			return nil, idempotency.ErrKeyReused
+		}
+
+		if !rec.Completed {
+			return nil, idempotency.ErrInProgress
+		}
+
+		r := *rec
+
+		return &r, nil
+	}
+
+	s.records[key] = &idempotency.Record{Key: key, Fingerprint: fingerprint, CreatedAt: now}
+
+	return nil, nil
+}
+
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, response []byte) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	ctx, span := tracing.Start(ctx, "storage/idempotency/complete")
+	defer span.End()
+
+	if rec, ok := s.records[key]; ok {
+		rec.Response = response
+		rec.Completed = true
+	}
+
+	return nil
+}
+
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	ctx, span := tracing.Start(ctx, "storage/idempotency/release")
+	defer span.End()
+
+	if rec, ok := s.records[key]; ok && !rec.Completed {
+		delete(s.records, key)
+	}
+
+	return nil
+}
+
+// evictExpired forgets the keys that are older than the window
+func (s *IdempotencyStore) evictExpired(now time.Time) {
+	for key, rec := range s.records {
+		if now.Sub(rec.CreatedAt) >= s.window {
+			delete(s.records, key)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package inmem
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/italolelis/coffee-shop/internal/pkg/idempotency"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyStore_Reserve(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := NewIdempotencyStore(time.Hour)
+
+	rec, err := s.Reserve(ctx, "key", "request")
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+
+	_, err = s.Reserve(ctx, "key", "request")
+	assert.True(t, errors.Is(err, idempotency.ErrInProgress))
+
+	require.NoError(t, s.Complete(ctx, "key", []byte("response")))
+
+	rec, err = s.Reserve(ctx, "key", "request")
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, []byte("response"), rec.Response)
+
+	_, err = s.Reserve(ctx, "key", "another request")
+	assert.True(t, errors.Is(err, idempotency.ErrKeyReused))
+}
+
+func TestIdempotencyStore_Release(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := NewIdempotencyStore(time.Hour)
+
+	_, err := s.Reserve(ctx, "key", "request")
+	require.NoError(t, err)
+	require.NoError(t, s.Release(ctx, "key"))
+
+	rec, err := s.Reserve(ctx, "key", "request")
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+}
+
+func TestIdempotencyStore_Window(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := NewIdempotencyStore(0)
+
+	_, err := s.Reserve(ctx, "key", "request")
+	require.NoError(t, err)
+	require.NoError(t, s.Complete(ctx, "key", []byte("response")))
+
+	rec, err := s.Reserve(ctx, "key", "request")
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+}
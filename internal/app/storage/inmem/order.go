@@ -2,6 +2,7 @@ package inmem
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	"github.com/google/uuid"
@@ -12,10 +13,16 @@ import (
 type OrderReadWrite struct {
 	mux    *sync.RWMutex
 	orders map[uuid.UUID]*order.Order
+	// carts indexes the open order of each customer
+	carts map[string]uuid.UUID
 }
 
 func NewOrderReadWrite() *OrderReadWrite {
-	return &OrderReadWrite{mux: &sync.RWMutex{}, orders: make(map[uuid.UUID]*order.Order, 0)}
+	return &OrderReadWrite{
+		mux:    &sync.RWMutex{},
+		orders: make(map[uuid.UUID]*order.Order, 0),
+		carts:  make(map[string]uuid.UUID, 0),
+	}
 }
 
 func (r *OrderReadWrite) FetchByID(ctx context.Context, id uuid.UUID) (*order.Order, error) {
@@ -30,9 +37,27 @@ func (r *OrderReadWrite) FetchByID(ctx context.Context, id uuid.UUID) (*order.Or
 		return nil, order.ErrNotFound
 	}
 
-	return o, nil
+	return copyOrder(o), nil
 }
 
+func (r *OrderReadWrite) FetchActiveByCustomer(ctx context.Context, customerName string) (*order.Order, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	ctx, span := tracing.Start(ctx, "storage/order/fetch-active-by-customer")
+	defer span.End()
+
+	id, ok := r.carts[customerName]
+	if !ok {
+		return nil, order.ErrNotFound
+	}
+
+	return copyOrder(r.orders[id]), nil
+}
+
+// Add stores the order, replacing any previously stored version of it. The order is only
+// replaced when it wasn't saved since it was fetched, otherwise order.ErrConflict is
+// returned and the caller has to fetch it again. A customer can only have one open order.
 func (r *OrderReadWrite) Add(ctx context.Context, o *order.Order) error {
 	r.mux.Lock()
 	defer r.mux.Unlock()
@@ -40,13 +65,72 @@ func (r *OrderReadWrite) Add(ctx context.Context, o *order.Order) error {
 	ctx, span := tracing.Start(ctx, "storage/order/add")
 	defer span.End()
 
-	existingOrder, ok := r.orders[o.ID]
-	if !ok {
-		r.orders[o.ID] = o
-		return nil
+	stored, ok := r.orders[o.ID]
+	switch {
+	case o.Version == 0 && ok:
+		return fmt.Errorf("%w: %s already exists", order.ErrConflict, o.ID)
+	case o.Version != 0 && (!ok || stored.Version != o.Version):
+		return fmt.Errorf("%w: %s was saved since version %d", order.ErrConflict, o.ID, o.Version)
+	}
+
+	if id, ok := r.carts[o.CustomerName]; ok && id != o.ID && o.Status == order.StatusOpen {
+		return fmt.Errorf("%w: %s already has an open order", order.ErrConflict, o.CustomerName)
 	}
 
-	existingOrder.AddItems(o.Items)
+	o.Version++
+	r.orders[o.ID] = copyOrder(o)
+
+	if o.Status == order.StatusOpen {
+		r.carts[o.CustomerName] = o.ID
+	} else if r.carts[o.CustomerName] == o.ID {
+		delete(r.carts, o.CustomerName)
+	}
 
 	return nil
 }
+
+// copyOrder makes a deep copy of the order, so callers can't change the
+// stored state without going through Add
+func copyOrder(o *order.Order) *order.Order {
+	c := *o
+	c.Items = make(order.Items, 0, len(o.Items))
+	for _, i := range o.Items {
+		item := *i
+		if i.Modifiers != nil {
+			item.Modifiers = make(order.Modifiers, 0, len(i.Modifiers))
+			for _, m := range i.Modifiers {
+				modifier := *m
+				item.Modifiers = append(item.Modifiers, &modifier)
+			}
+		}
+
+		c.Items = append(c.Items, &item)
+	}
+
+	if o.PaymentIDs != nil {
+		c.PaymentIDs = append(order.PaymentIDs{}, o.PaymentIDs...)
+	}
+
+	if o.Discounts != nil {
+		c.Discounts = make(order.Discounts, 0, len(o.Discounts))
+		for _, d := range o.Discounts {
+			discount := *d
+			c.Discounts = append(c.Discounts, &discount)
+		}
+	}
+
+	if o.Taxes != nil {
+		c.Taxes = make(order.Taxes, 0, len(o.Taxes))
+		for _, t := range o.Taxes {
+			tax := *t
+			c.Taxes = append(c.Taxes, &tax)
+		}
+	}
+
+	if o.Tip != nil {
+		tip := *o.Tip
+		c.Tip = &tip
+	}
+
+	return &c
+}
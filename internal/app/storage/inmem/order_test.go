@@ -0,0 +1,75 @@
+package inmem
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/italolelis/coffee-shop/internal/app/order"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderReadWrite_FetchActiveByCustomer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rw := NewOrderReadWrite()
+
+	first := order.New("anna")
+	require.NoError(t, first.AddItem(&order.Item{Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 1}))
+	require.NoError(t, rw.Add(ctx, first))
+
+	active, err := rw.FetchActiveByCustomer(ctx, "anna")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, active.ID)
+
+	require.NoError(t, first.Checkout())
+	require.NoError(t, rw.Add(ctx, first))
+
+	_, err = rw.FetchActiveByCustomer(ctx, "anna")
+	assert.True(t, errors.Is(err, order.ErrNotFound))
+
+	second := order.New("anna")
+	require.NoError(t, rw.Add(ctx, second))
+	assert.NotEqual(t, first.ID, second.ID)
+
+	active, err = rw.FetchActiveByCustomer(ctx, "anna")
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, active.ID)
+
+	stored, err := rw.FetchByID(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, order.StatusCheckedOut, stored.Status)
+}
+
+func TestOrderReadWrite_Conflicts(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rw := NewOrderReadWrite()
+
+	o := order.New("anna")
+	require.NoError(t, rw.Add(ctx, o))
+	assert.Equal(t, 1, o.Version)
+
+	stale, err := rw.FetchByID(ctx, o.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, o.AddItem(&order.Item{Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 1}))
+	require.NoError(t, rw.Add(ctx, o))
+	assert.Equal(t, 2, o.Version)
+
+	require.NoError(t, stale.AddItem(&order.Item{Name: "espresso", ServingSize: "S", Price: money.New(180, "EUR"), Qty: 1}))
+	err = rw.Add(ctx, stale)
+	assert.True(t, errors.Is(err, order.ErrConflict))
+
+	err = rw.Add(ctx, order.New("anna"))
+	assert.True(t, errors.Is(err, order.ErrConflict))
+
+	stored, err := rw.FetchByID(ctx, o.ID)
+	require.NoError(t, err)
+	require.Len(t, stored.Items, 1)
+	assert.Equal(t, "latte", stored.Items[0].Name)
+}
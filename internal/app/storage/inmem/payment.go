@@ -0,0 +1,136 @@
+package inmem
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+)
+
+type PaymentReadWrite struct {
+	mux           *sync.RWMutex
+	confirmations map[uuid.UUID]*payment.Confirmation
+	// byOrder holds the payments of each order in the order they were made
+	byOrder map[uuid.UUID][]uuid.UUID
+}
+
+func NewPaymentReadWrite() *PaymentReadWrite {
+	return &PaymentReadWrite{
+		mux:           &sync.RWMutex{},
+		confirmations: make(map[uuid.UUID]*payment.Confirmation, 0),
+		byOrder:       make(map[uuid.UUID][]uuid.UUID, 0),
+	}
+}
+
+func (r *PaymentReadWrite) FetchByID(ctx context.Context, id uuid.UUID) (*payment.Confirmation, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	ctx, span := tracing.Start(ctx, "storage/payment/fetch-by-id")
+	defer span.End()
+
+	c, ok := r.confirmations[id]
+	if !ok {
+		return nil, payment.ErrConfirmationNotFound
+	}
+
+	cc := *c
+
+	return &cc, nil
+}
+
+func (r *PaymentReadWrite) FetchByOrderID(ctx context.Context, orderID uuid.UUID) (*payment.Confirmation, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	ctx, span := tracing.Start(ctx, "storage/payment/fetch-by-order-id")
+	defer span.End()
+
+	ids := r.byOrder[orderID]
+	if len(ids) == 0 {
+		return nil, payment.ErrConfirmationNotFound
+	}
+
+	cc := *r.confirmations[ids[len(ids)-1]]
+
+	return &cc, nil
+}
+
+func (r *PaymentReadWrite) FetchAllByOrderID(ctx context.Context, orderID uuid.UUID) ([]*payment.Confirmation, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	ctx, span := tracing.Start(ctx, "storage/payment/fetch-all-by-order-id")
+	defer span.End()
+
+	confirmations := make([]*payment.Confirmation, 0, len(r.byOrder[orderID]))
+	for _, id := range r.byOrder[orderID] {
+		cc := *r.confirmations[id]
+		confirmations = append(confirmations, &cc)
+	}
+
+	return confirmations, nil
+}
+
+func (r *PaymentReadWrite) FetchExpiredAuthorizations(ctx context.Context, now time.Time) ([]*payment.Confirmation, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	ctx, span := tracing.Start(ctx, "storage/payment/fetch-expired-authorizations")
+	defer span.End()
+
+	expired := make([]*payment.Confirmation, 0)
+	for _, c := range r.confirmations {
+		if c.Status != payment.StatusAuthorized || now.Before(c.ExpiresAt) {
+			continue
+		}
+
+		cc := *c
+		expired = append(expired, &cc)
+	}
+
+	return expired, nil
+}
+
+func (r *PaymentReadWrite) FetchPaidBetween(ctx context.Context, from, to time.Time) ([]*payment.Confirmation, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	ctx, span := tracing.Start(ctx, "storage/payment/fetch-paid-between")
+	defer span.End()
+
+	paid := make([]*payment.Confirmation, 0)
+	for _, c := range r.confirmations {
+		if c.PayedAt.Before(from) || !c.PayedAt.Before(to) {
+			continue
+		}
+
+		cc := *c
+		paid = append(paid, &cc)
+	}
+
+	sort.Slice(paid, func(i, j int) bool { return paid[i].PayedAt.Before(paid[j].PayedAt) })
+
+	return paid, nil
+}
+
+func (r *PaymentReadWrite) Add(ctx context.Context, c *payment.Confirmation) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	ctx, span := tracing.Start(ctx, "storage/payment/add")
+	defer span.End()
+
+	if _, ok := r.confirmations[c.ID]; !ok {
+		r.byOrder[c.OrderID] = append(r.byOrder[c.OrderID], c.ID)
+	}
+
+	cc := *c
+	r.confirmations[c.ID] = &cc
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package inmem
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpireAuthorizations(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx = context.Background()
+		rw  = NewPaymentReadWrite()
+		o   = payment.OrderRequest{OrderID: uuid.New(), Total: money.New(780, "EUR")}
+	)
+
+	expiring := payment.NewAuthorization("credit_card", o, time.Minute)
+	valid := payment.NewAuthorization("credit_card", o, time.Hour)
+	captured := payment.NewConfirmation("credit_card", o)
+	for _, c := range []*payment.Confirmation{expiring, valid, captured} {
+		require.NoError(t, rw.Add(ctx, c))
+	}
+
+	n, err := payment.ExpireAuthorizations(ctx, rw, rw, time.Now().Add(10*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	for _, tt := range []struct {
+		c        *payment.Confirmation
+		expected payment.Status
+	}{
+		{expiring, payment.StatusExpired},
+		{valid, payment.StatusAuthorized},
+		{captured, payment.StatusCaptured},
+	} {
+		c, err := rw.FetchByID(ctx, tt.c.ID)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, c.Status)
+	}
+}
@@ -0,0 +1,132 @@
+package inmem
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/italolelis/coffee-shop/internal/app/promotion"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+)
+
+type PromotionReadWrite struct {
+	mux        *sync.RWMutex
+	promotions []*promotion.Promotion
+	coupons    map[string]*promotion.Promotion
+}
+
+func NewPromotionReadWrite(promotions ...*promotion.Promotion) *PromotionReadWrite {
+	r := &PromotionReadWrite{mux: &sync.RWMutex{}, coupons: make(map[string]*promotion.Promotion)}
+	for _, p := range promotions {
+		r.add(p)
+	}
+
+	return r
+}
+
+func (r *PromotionReadWrite) FetchAutomatic(ctx context.Context) ([]*promotion.Promotion, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	_, span := tracing.Start(ctx, "storage/promotion/fetch-automatic")
+	defer span.End()
+
+	promotions := make([]*promotion.Promotion, 0, len(r.promotions))
+	for _, p := range r.promotions {
+		if p.Code == "" {
+			promotions = append(promotions, copyPromotion(p))
+		}
+	}
+
+	return promotions, nil
+}
+
+func (r *PromotionReadWrite) FetchByCode(ctx context.Context, code string) (*promotion.Promotion, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	_, span := tracing.Start(ctx, "storage/promotion/fetch-by-code")
+	defer span.End()
+
+	p, ok := r.coupons[strings.ToUpper(code)]
+	if !ok {
+		return nil, promotion.ErrCouponNotFound
+	}
+
+	return copyPromotion(p), nil
+}
+
+func (r *PromotionReadWrite) Add(ctx context.Context, p *promotion.Promotion) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	_, span := tracing.Start(ctx, "storage/promotion/add")
+	defer span.End()
+
+	r.add(p)
+
+	return nil
+}
+
+func (r *PromotionReadWrite) Redeem(ctx context.Context, code string, now time.Time) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	_, span := tracing.Start(ctx, "storage/promotion/redeem")
+	defer span.End()
+
+	p, ok := r.coupons[strings.ToUpper(code)]
+	if !ok {
+		return promotion.ErrCouponNotFound
+	}
+
+	if err := p.Usable(now); err != nil {
+		return err
+	}
+
+	p.Uses++
+
+	return nil
+}
+
+func (r *PromotionReadWrite) Release(ctx context.Context, code string) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	_, span := tracing.Start(ctx, "storage/promotion/release")
+	defer span.End()
+
+	p, ok := r.coupons[strings.ToUpper(code)]
+	if !ok {
+		return promotion.ErrCouponNotFound
+	}
+
+	if p.Uses > 0 {
+		p.Uses--
+	}
+
+	return nil
+}
+
+func (r *PromotionReadWrite) add(p *promotion.Promotion) {
+	pc := copyPromotion(p)
+	if pc.Code == "" {
+		r.promotions = append(r.promotions, pc)
+		return
+	}
+
+	pc.Code = strings.ToUpper(pc.Code)
+	r.coupons[pc.Code] = pc
+}
+
+func copyPromotion(p *promotion.Promotion) *promotion.Promotion {
+	pc := *p
+	pc.Products = append([]string(nil), p.Products...)
+	if p.Hours != nil {
+		hours := *p.Hours
+		pc.Hours = &hours
+	}
+
+	return &pc
+}
@@ -0,0 +1,47 @@
+package inmem
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/italolelis/coffee-shop/internal/app/promotion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromotionReadWrite_Redeem(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx = context.Background()
+		now = time.Now()
+		rw  = NewPromotionReadWrite(
+			&promotion.Promotion{Name: "happy hour", Kind: promotion.KindPercentage, Percent: 15},
+			&promotion.Promotion{Name: "welcome", Kind: promotion.KindPercentage, Percent: 10, Code: "welcome10", MaxUses: 2},
+		)
+	)
+
+	automatic, err := rw.FetchAutomatic(ctx)
+	require.NoError(t, err)
+	require.Len(t, automatic, 1)
+	assert.Equal(t, "happy hour", automatic[0].Name)
+
+	require.NoError(t, rw.Redeem(ctx, "WELCOME10", now))
+	require.NoError(t, rw.Redeem(ctx, "welcome10", now))
+	assert.True(t, errors.Is(rw.Redeem(ctx, "WELCOME10", now), promotion.ErrCouponUsedUp))
+
+	require.NoError(t, rw.Release(ctx, "WELCOME10"))
+	p, err := rw.FetchByCode(ctx, "WELCOME10")
+	require.NoError(t, err)
+	assert.Equal(t, 1, p.Uses)
+
+	// fetched promotions are copies
+	p.Uses = 0
+	require.NoError(t, rw.Redeem(ctx, "WELCOME10", now))
+	assert.True(t, errors.Is(rw.Redeem(ctx, "WELCOME10", now), promotion.ErrCouponUsedUp))
+
+	_, err = rw.FetchByCode(ctx, "UNKNOWN")
+	assert.True(t, errors.Is(err, promotion.ErrCouponNotFound))
+}
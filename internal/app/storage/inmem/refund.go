@@ -0,0 +1,90 @@
+package inmem
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+)
+
+type RefundReadWrite struct {
+	mux *sync.RWMutex
+	// refunds holds the refunds of each payment in the order they were made
+	refunds map[uuid.UUID][]*payment.Refund
+	// payments are the refunded payments, they are updated along with their refunds
+	payments *PaymentReadWrite
+}
+
+func NewRefundReadWrite(payments *PaymentReadWrite) *RefundReadWrite {
+	return &RefundReadWrite{
+		mux:      &sync.RWMutex{},
+		refunds:  make(map[uuid.UUID][]*payment.Refund, 0),
+		payments: payments,
+	}
+}
+
+func (r *RefundReadWrite) FetchByPaymentID(ctx context.Context, paymentID uuid.UUID) ([]*payment.Refund, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	ctx, span := tracing.Start(ctx, "storage/refund/fetch-by-payment-id")
+	defer span.End()
+
+	refunds := make([]*payment.Refund, 0, len(r.refunds[paymentID]))
+	for _, rf := range r.refunds[paymentID] {
+		rc := *rf
+		refunds = append(refunds, &rc)
+	}
+
+	return refunds, nil
+}
+
+func (r *RefundReadWrite) Add(ctx context.Context, rf *payment.Refund) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	ctx, span := tracing.Start(ctx, "storage/refund/add")
+	defer span.End()
+
+	rc := *rf
+	r.refunds[rf.PaymentID] = append(r.refunds[rf.PaymentID], &rc)
+
+	return nil
+}
+
+// Refund holds the lock of the payments while fn runs, so the refunds of a payment
+// are applied one after the other
+func (r *RefundReadWrite) Refund(
+	ctx context.Context,
+	paymentID uuid.UUID,
+	fn func(*payment.Confirmation) (*payment.Refund, error),
+) (*payment.Confirmation, *payment.Refund, error) {
+	r.payments.mux.Lock()
+	defer r.payments.mux.Unlock()
+
+	ctx, span := tracing.Start(ctx, "storage/refund/refund")
+	defer span.End()
+
+	stored, ok := r.payments.confirmations[paymentID]
+	if !ok {
+		return nil, nil, payment.ErrConfirmationNotFound
+	}
+
+	c := *stored
+
+	rf, err := fn(&c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	cc, rc := c, *rf
+	r.payments.confirmations[paymentID] = &cc
+	r.refunds[paymentID] = append(r.refunds[paymentID], &rc)
+
+	return &c, rf, nil
+}
@@ -0,0 +1,64 @@
+package inmem
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/order"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+)
+
+type SagaReadWrite struct {
+	mux   *sync.RWMutex
+	sagas map[uuid.UUID]*order.CheckoutSaga
+}
+
+func NewSagaReadWrite() *SagaReadWrite {
+	return &SagaReadWrite{
+		mux:   &sync.RWMutex{},
+		sagas: make(map[uuid.UUID]*order.CheckoutSaga, 0),
+	}
+}
+
+// FetchIncomplete returns the sagas that didn't reach a final step and weren't updated
+// since the given time, oldest first
+func (r *SagaReadWrite) FetchIncomplete(ctx context.Context, updatedBefore time.Time) ([]*order.CheckoutSaga, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	ctx, span := tracing.Start(ctx, "storage/saga/fetch-incomplete")
+	defer span.End()
+
+	sagas := make([]*order.CheckoutSaga, 0)
+	for _, s := range r.sagas {
+		if s.Step.Done() || !s.UpdatedAt.Before(updatedBefore) {
+			continue
+		}
+
+		sc := *s
+		sagas = append(sagas, &sc)
+	}
+
+	sort.Slice(sagas, func(i, j int) bool {
+		return sagas[i].CreatedAt.Before(sagas[j].CreatedAt)
+	})
+
+	return sagas, nil
+}
+
+// Add stores the saga, replacing any previously stored version of it
+func (r *SagaReadWrite) Add(ctx context.Context, s *order.CheckoutSaga) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	ctx, span := tracing.Start(ctx, "storage/saga/add")
+	defer span.End()
+
+	sc := *s
+	r.sagas[s.ID] = &sc
+
+	return nil
+}
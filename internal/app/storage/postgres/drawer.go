@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+	"github.com/jmoiron/sqlx"
+)
+
+type DrawerReadWrite struct {
+	db *sqlx.DB
+}
+
+func NewDrawerReadWrite(db *sqlx.DB) *DrawerReadWrite {
+	return &DrawerReadWrite{db: db}
+}
+
+// drawerRow is the database representation of a payment.DrawerSession
+type drawerRow struct {
+	ID       uuid.UUID    `db:"id"`
+	Status   string       `db:"status"`
+	Float    int64        `db:"float_amount"`
+	Sales    int64        `db:"sales"`
+	PaidOut  int64        `db:"paid_out"`
+	Payments int          `db:"payments"`
+	Counted  int64        `db:"counted"`
+	Currency string       `db:"currency"`
+	OpenedAt time.Time    `db:"opened_at"`
+	ClosedAt sql.NullTime `db:"closed_at"`
+}
+
+func (r drawerRow) toDrawerSession() *payment.DrawerSession {
+	d := &payment.DrawerSession{
+		ID:       r.ID,
+		Status:   payment.DrawerStatus(r.Status),
+		Float:    money.New(r.Float, r.Currency),
+		Sales:    money.New(r.Sales, r.Currency),
+		PaidOut:  money.New(r.PaidOut, r.Currency),
+		Payments: r.Payments,
+		Counted:  money.New(r.Counted, r.Currency),
+		OpenedAt: r.OpenedAt.UTC(),
+	}
+
+	if r.ClosedAt.Valid {
+		d.ClosedAt = r.ClosedAt.Time.UTC()
+	}
+
+	return d
+}
+
+func (r *DrawerReadWrite) FetchByID(ctx context.Context, id uuid.UUID) (*payment.DrawerSession, error) {
+	ctx, span := tracing.Start(ctx, "storage/drawer/fetch-by-id")
+	defer span.End()
+
+	d, err := fetchDrawer(ctx, r.db, `SELECT id, status, float_amount, sales, paid_out, payments, counted, currency, opened_at, closed_at
+		FROM cash_drawer_sessions WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, payment.ErrDrawerNotFound
+	}
+
+	return d, err
+}
+
+func (r *DrawerReadWrite) FetchOpen(ctx context.Context) (*payment.DrawerSession, error) {
+	ctx, span := tracing.Start(ctx, "storage/drawer/fetch-open")
+	defer span.End()
+
+	return fetchOpenDrawer(ctx, r.db, "")
+}
+
+func (r *DrawerReadWrite) Open(ctx context.Context, d *payment.DrawerSession) error {
+	ctx, span := tracing.Start(ctx, "storage/drawer/open")
+	defer span.End()
+
+	// the unique index on open sessions makes the insert a no-op while another session is open
+	res, err := r.db.ExecContext(ctx, `INSERT INTO cash_drawer_sessions
+		(id, status, float_amount, sales, paid_out, payments, counted, currency, opened_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) ON CONFLICT DO NOTHING`,
+		d.ID, d.Status, d.Float.Amount, d.Sales.Amount, d.PaidOut.Amount, d.Payments, d.Counted.Amount,
+		d.Float.Currency, d.OpenedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert drawer session: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to insert drawer session: %w", err)
+	} else if n == 0 {
+		return payment.ErrDrawerAlreadyOpen
+	}
+
+	return nil
+}
+
+// UpdateOpen locks the open session row while the change is applied, so cash
+// taken on several tills at once is serialized
+func (r *DrawerReadWrite) UpdateOpen(ctx context.Context, fn func(*payment.DrawerSession) error) error {
+	ctx, span := tracing.Start(ctx, "storage/drawer/update-open")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	d, err := fetchOpenDrawer(ctx, tx, "FOR UPDATE")
+	if err != nil {
+		return err
+	}
+
+	if err := fn(d); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE cash_drawer_sessions
+		SET status = $2, sales = $3, paid_out = $4, payments = $5, counted = $6, closed_at = $7
+		WHERE id = $1`,
+		d.ID, d.Status, d.Sales.Amount, d.PaidOut.Amount, d.Payments, d.Counted.Amount,
+		sql.NullTime{Time: d.ClosedAt, Valid: !d.ClosedAt.IsZero()},
+	); err != nil {
+		return fmt.Errorf("failed to update drawer session: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func fetchOpenDrawer(ctx context.Context, q sqlx.QueryerContext, lock string) (*payment.DrawerSession, error) {
+	d, err := fetchDrawer(ctx, q, `SELECT id, status, float_amount, sales, paid_out, payments, counted, currency, opened_at, closed_at
+		FROM cash_drawer_sessions WHERE status = $1 `+lock, payment.DrawerOpen)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, payment.ErrDrawerClosed
+	}
+
+	return d, err
+}
+
+// fetchDrawer leaves sql.ErrNoRows to the caller, since a missing session means
+// something different depending on the lookup
+func fetchDrawer(ctx context.Context, q sqlx.QueryerContext, query string, args ...interface{}) (*payment.DrawerSession, error) {
+	var row drawerRow
+	if err := sqlx.GetContext(ctx, q, &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("failed to fetch drawer session: %w", err)
+	}
+
+	return row.toDrawerSession(), nil
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrawerReadWrite_UpdateOpen(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	id, openedAt := uuid.New(), time.Now().UTC()
+	columns := []string{"id", "status", "float_amount", "sales", "paid_out", "payments", "counted", "currency", "opened_at", "closed_at"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM cash_drawer_sessions WHERE status = (.+) FOR UPDATE").
+		WithArgs(payment.DrawerOpen).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(id, "open", 10000, 780, 0, 1, 0, "EUR", openedAt, nil))
+	mock.ExpectExec("UPDATE cash_drawer_sessions SET").
+		WithArgs(id, payment.DrawerClosed, int64(780), int64(0), 1, int64(10780), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	rw := NewDrawerReadWrite(db)
+	require.NoError(t, rw.UpdateOpen(context.Background(), func(d *payment.DrawerSession) error {
+		return d.Close(money.New(10780, "EUR"))
+	}))
+
+	// without an open session there is nothing to update
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM cash_drawer_sessions WHERE status = (.+) FOR UPDATE").
+		WithArgs(payment.DrawerOpen).
+		WillReturnRows(sqlmock.NewRows(columns))
+	mock.ExpectRollback()
+
+	err := rw.UpdateOpen(context.Background(), func(d *payment.DrawerSession) error {
+		return d.TakePayment(money.New(780, "EUR"))
+	})
+	assert.Equal(t, payment.ErrDrawerClosed, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
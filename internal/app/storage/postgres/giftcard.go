@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+	"github.com/jmoiron/sqlx"
+)
+
+type GiftCardReadWrite struct {
+	db *sqlx.DB
+}
+
+func NewGiftCardReadWrite(db *sqlx.DB) *GiftCardReadWrite {
+	return &GiftCardReadWrite{db: db}
+}
+
+// giftCardRow is the database representation of a payment.GiftCardAccount
+type giftCardRow struct {
+	Number    string    `db:"number"`
+	Balance   int64     `db:"balance"`
+	Currency  string    `db:"currency"`
+	IssuedAt  time.Time `db:"issued_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (r giftCardRow) toGiftCard() *payment.GiftCardAccount {
+	return &payment.GiftCardAccount{
+		Number:    r.Number,
+		Balance:   money.New(r.Balance, r.Currency),
+		IssuedAt:  r.IssuedAt.UTC(),
+		UpdatedAt: r.UpdatedAt.UTC(),
+	}
+}
+
+func (r *GiftCardReadWrite) FetchByNumber(ctx context.Context, number string) (*payment.GiftCardAccount, error) {
+	ctx, span := tracing.Start(ctx, "storage/gift-card/fetch-by-number")
+	defer span.End()
+
+	return fetchGiftCard(ctx, r.db, `SELECT number, balance, currency, issued_at, updated_at
+		FROM gift_cards WHERE number = $1`, number)
+}
+
+func (r *GiftCardReadWrite) Add(ctx context.Context, card *payment.GiftCardAccount) error {
+	ctx, span := tracing.Start(ctx, "storage/gift-card/add")
+	defer span.End()
+
+	res, err := r.db.ExecContext(ctx, `INSERT INTO gift_cards (number, balance, currency, issued_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5) ON CONFLICT (number) DO NOTHING`,
+		card.Number, card.Balance.Amount, card.Balance.Currency, card.IssuedAt, card.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert gift card: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to insert gift card: %w", err)
+	} else if n == 0 {
+		return payment.ErrGiftCardExists
+	}
+
+	return nil
+}
+
+// Update locks the gift card row while the change is applied, so concurrent
+// redemptions are serialized
+func (r *GiftCardReadWrite) Update(ctx context.Context, number string, fn func(*payment.GiftCardAccount) error) error {
+	ctx, span := tracing.Start(ctx, "storage/gift-card/update")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	card, err := fetchGiftCard(ctx, tx, `SELECT number, balance, currency, issued_at, updated_at
+		FROM gift_cards WHERE number = $1 FOR UPDATE`, number)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(card); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE gift_cards SET balance = $2, updated_at = $3 WHERE number = $1`,
+		card.Number, card.Balance.Amount, card.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to update gift card: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func fetchGiftCard(ctx context.Context, q sqlx.QueryerContext, query string, args ...interface{}) (*payment.GiftCardAccount, error) {
+	var row giftCardRow
+	if err := sqlx.GetContext(ctx, q, &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, payment.ErrGiftCardNotFound
+		}
+
+		return nil, fmt.Errorf("failed to fetch gift card: %w", err)
+	}
+
+	return row.toGiftCard(), nil
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGiftCardReadWrite_Update(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	issuedAt := time.Now().UTC()
+	columns := []string{"number", "balance", "currency", "issued_at", "updated_at"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM gift_cards WHERE number = (.+) FOR UPDATE").
+		WithArgs("1234").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("1234", 1000, "EUR", issuedAt, issuedAt))
+	mock.ExpectExec("UPDATE gift_cards SET balance").
+		WithArgs("1234", int64(740), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	rw := NewGiftCardReadWrite(db)
+	require.NoError(t, rw.Update(context.Background(), "1234", func(card *payment.GiftCardAccount) error {
+		return card.Redeem(money.New(260, "EUR"))
+	}))
+
+	// a failed redemption rolls back without touching the balance
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM gift_cards WHERE number = (.+) FOR UPDATE").
+		WithArgs("1234").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("1234", 740, "EUR", issuedAt, issuedAt))
+	mock.ExpectRollback()
+
+	err := rw.Update(context.Background(), "1234", func(card *payment.GiftCardAccount) error {
+		return card.Redeem(money.New(1000, "EUR"))
+	})
+	assert.True(t, errors.Is(err, payment.ErrInsufficientBalance))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
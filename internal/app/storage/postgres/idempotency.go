@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/italolelis/coffee-shop/internal/pkg/idempotency"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+	"github.com/jmoiron/sqlx"
+)
+
+type IdempotencyStore struct {
+	db     *sqlx.DB
+	window time.Duration
+}
+
+func NewIdempotencyStore(db *sqlx.DB, window time.Duration) *IdempotencyStore {
+	return &IdempotencyStore{db: db, window: window}
+}
+
+func (s *IdempotencyStore) Reserve(ctx context.Context, key string, fingerprint string) (*idempotency.Record, error) {
+	ctx, span := tracing.Start(ctx, "storage/idempotency/reserve")
+	defer span.End()
+
+	now := time.Now().UTC()
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE key = $1 AND created_at < $2`,
+		key, now.Add(-s.window)); err != nil {
+		return nil, fmt.Errorf("failed to expire idempotency key: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, `INSERT INTO idempotency_keys (key, fingerprint, completed, created_at)
+		VALUES ($1, $2, FALSE, $3) ON CONFLICT (key) DO NOTHING`, key, fingerprint, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	} else if n == 1 {
+		return nil, tx.Commit()
+	}
+
+	var rec idempotency.Record
+	if err := tx.GetContext(ctx, &rec, `SELECT key, fingerprint, response, completed, created_at
+		FROM idempotency_keys WHERE key = $1`, key); err != nil {
+		return nil, fmt.Errorf("failed to fetch idempotency key: %w", err)
+	}
+
+	if rec.Fingerprint != fingerprint {
+		return nil, idempotency.ErrKeyReused
+	}
+
+	if !rec.Completed {
+		return nil, idempotency.ErrInProgress
+	}
+
+	return &rec, tx.Commit()
+}
+
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, response []byte) error {
+	ctx, span := tracing.Start(ctx, "storage/idempotency/complete")
+	defer span.End()
+
+	if _, err := s.db.ExecContext(ctx, `UPDATE idempotency_keys SET response = $2, completed = TRUE WHERE key = $1`,
+		key, response); err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	ctx, span := tracing.Start(ctx, "storage/idempotency/release")
+	defer span.End()
+
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE key = $1 AND NOT completed`,
+		key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// migrations holds the schema changes in the order they are applied. The version
+// of a migration is its position in the slice, so new migrations are always appended.
+var migrations = []string{
+	`CREATE TABLE payment_confirmations (
+		id         UUID PRIMARY KEY,
+		order_id   UUID NOT NULL,
+		method     TEXT NOT NULL,
+		amount     BIGINT NOT NULL,
+		currency   CHAR(3) NOT NULL,
+		payed_at   TIMESTAMPTZ NOT NULL
+	);
+	CREATE INDEX payment_confirmations_order_id_idx ON payment_confirmations (order_id);`,
+	`CREATE TABLE idempotency_keys (
+		key         TEXT PRIMARY KEY,
+		fingerprint TEXT NOT NULL,
+		response    BYTEA,
+		completed   BOOLEAN NOT NULL DEFAULT FALSE,
+		created_at  TIMESTAMPTZ NOT NULL
+	);`,
+	`CREATE TABLE orders (
+		id         UUID PRIMARY KEY,
+		created_at TIMESTAMPTZ NOT NULL,
+		customer   TEXT NOT NULL,
+		status     TEXT NOT NULL,
+		items      JSONB,
+		payment_id UUID
+	);
+	CREATE INDEX orders_open_customer_idx ON orders (customer, created_at) WHERE status = 'open';`,
+	`ALTER TABLE payment_confirmations ADD COLUMN status TEXT NOT NULL DEFAULT 'captured';`,
+	`CREATE TABLE checkout_sagas (
+		id         UUID PRIMARY KEY,
+		order_id   UUID NOT NULL,
+		payment_id UUID,
+		step       TEXT NOT NULL,
+		reason     TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL
+	);
+	CREATE INDEX checkout_sagas_pending_idx ON checkout_sagas (created_at)
+		WHERE step NOT IN ('completed', 'compensated', 'aborted');`,
+	`ALTER TABLE payment_confirmations ADD COLUMN refunded BIGINT NOT NULL DEFAULT 0;
+	CREATE TABLE payment_refunds (
+		id          UUID PRIMARY KEY,
+		payment_id  UUID NOT NULL REFERENCES payment_confirmations (id),
+		order_id    UUID NOT NULL,
+		amount      BIGINT NOT NULL,
+		currency    CHAR(3) NOT NULL,
+		reason      TEXT NOT NULL DEFAULT '',
+		refunded_at TIMESTAMPTZ NOT NULL
+	);
+	CREATE INDEX payment_refunds_payment_id_idx ON payment_refunds (payment_id);`,
+	`ALTER TABLE payment_confirmations ADD COLUMN expires_at TIMESTAMPTZ;
+	CREATE INDEX payment_confirmations_authorized_idx ON payment_confirmations (expires_at)
+		WHERE status = 'authorized';
+	ALTER TABLE orders ADD COLUMN payment_captured BOOLEAN NOT NULL DEFAULT FALSE;`,
+	`CREATE TABLE gift_cards (
+		number     TEXT PRIMARY KEY,
+		balance    BIGINT NOT NULL CHECK (balance >= 0),
+		currency   CHAR(3) NOT NULL,
+		issued_at  TIMESTAMPTZ NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL
+	);
+	ALTER TABLE payment_confirmations ADD COLUMN gift_card_number TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE payment_confirmations ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+	ALTER TABLE orders ADD COLUMN payment_ids JSONB;
+	UPDATE orders SET payment_ids = jsonb_build_array(payment_id) WHERE payment_id IS NOT NULL;`,
+	`CREATE TABLE cash_drawer_sessions (
+		id           UUID PRIMARY KEY,
+		status       TEXT NOT NULL,
+		float_amount BIGINT NOT NULL,
+		sales        BIGINT NOT NULL DEFAULT 0,
+		paid_out     BIGINT NOT NULL DEFAULT 0,
+		payments     INT NOT NULL DEFAULT 0,
+		counted      BIGINT NOT NULL DEFAULT 0,
+		currency     CHAR(3) NOT NULL,
+		opened_at    TIMESTAMPTZ NOT NULL,
+		closed_at    TIMESTAMPTZ
+	);
+	CREATE UNIQUE INDEX cash_drawer_sessions_open_idx ON cash_drawer_sessions (status) WHERE status = 'open';
+	ALTER TABLE payment_confirmations
+		ADD COLUMN cash_tendered BIGINT NOT NULL DEFAULT 0,
+		ADD COLUMN cash_change BIGINT NOT NULL DEFAULT 0;`,
+	`ALTER TABLE orders
+		ADD COLUMN coupon TEXT NOT NULL DEFAULT '',
+		ADD COLUMN discounts JSONB;`,
+	`ALTER TABLE orders
+		ADD COLUMN store TEXT NOT NULL DEFAULT '',
+		ADD COLUMN taxes JSONB;`,
+	`ALTER TABLE payment_confirmations
+		ADD COLUMN tip BIGINT NOT NULL DEFAULT 0,
+		ADD COLUMN barista TEXT NOT NULL DEFAULT '',
+		ADD COLUMN shift_id UUID;
+	CREATE INDEX payment_confirmations_payed_at_idx ON payment_confirmations (payed_at);
+	ALTER TABLE orders ADD COLUMN tip JSONB;`,
+	`CREATE TABLE promotions (
+		id         SERIAL PRIMARY KEY,
+		code       TEXT NOT NULL DEFAULT '',
+		rule       JSONB NOT NULL,
+		max_uses   INT NOT NULL DEFAULT 0,
+		uses       INT NOT NULL DEFAULT 0,
+		expires_at TIMESTAMPTZ
+	);
+	CREATE UNIQUE INDEX promotions_code_idx ON promotions (code) WHERE code <> '';`,
+	// only the newest open order of a customer was ever fetched, the older ones are cancelled
+	// so a customer can have a single open order from now on
+	`ALTER TABLE orders ADD COLUMN version INT NOT NULL DEFAULT 1;
+	UPDATE orders o SET status = 'cancelled' WHERE status = 'open' AND EXISTS (
+		SELECT 1 FROM orders n WHERE n.customer = o.customer AND n.status = 'open'
+			AND (n.created_at, n.id) > (o.created_at, o.id)
+	);
+	DROP INDEX orders_open_customer_idx;
+	CREATE UNIQUE INDEX orders_open_customer_idx ON orders (customer) WHERE status = 'open';`,
+}
+
+// Migrate applies the migrations that weren't applied to the database yet
+func Migrate(ctx context.Context, db *sqlx.DB) error {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INT PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	var current int
+	if err := db.GetContext(ctx, &current, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for i := current; i < len(migrations); i++ {
+		version := i + 1
+
+		tx, err := db.BeginTxx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to start migration %d: %w", version, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply migration %d: %w", version, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record migration %d: %w", version, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", version, err)
+		}
+	}
+
+	return nil
+}
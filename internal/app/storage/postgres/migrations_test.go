@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMigrations_IndexNames catches migrations creating an index that already exists,
+// which only fails once they run against a real database
+func TestMigrations_IndexNames(t *testing.T) {
+	t.Parallel()
+
+	var (
+		statement = regexp.MustCompile(`(?i)(CREATE (?:UNIQUE )?INDEX|DROP INDEX)\s+(?:IF (?:NOT )?EXISTS\s+)?(\w+)`)
+		indexes   = make(map[string]int)
+	)
+
+	for i, m := range migrations {
+		for _, match := range statement.FindAllStringSubmatch(m, -1) {
+			name := match[2]
+			if strings.EqualFold(match[1], "DROP INDEX") {
+				delete(indexes, name)
+				continue
+			}
+
+			if version, ok := indexes[name]; ok {
+				assert.Failf(t, "index already exists", "migration %d creates %s, which migration %d already created", i+1, name, version)
+			}
+
+			indexes[name] = i + 1
+		}
+	}
+}
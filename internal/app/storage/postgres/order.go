@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/order"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the postgres error code of unique constraint violations
+const uniqueViolation = "23505"
+
+type OrderReadWrite struct {
+	db *sqlx.DB
+}
+
+func NewOrderReadWrite(db *sqlx.DB) *OrderReadWrite {
+	return &OrderReadWrite{db: db}
+}
+
+func (r *OrderReadWrite) FetchByID(ctx context.Context, id uuid.UUID) (*order.Order, error) {
+	ctx, span := tracing.Start(ctx, "storage/order/fetch-by-id")
+	defer span.End()
+
+	return r.fetch(ctx, `SELECT id, created_at, customer, status, items, payment_id, payment_ids, payment_captured, coupon, discounts, store, taxes, tip, version
+		FROM orders WHERE id = $1`, id)
+}
+
+func (r *OrderReadWrite) FetchActiveByCustomer(ctx context.Context, customerName string) (*order.Order, error) {
+	ctx, span := tracing.Start(ctx, "storage/order/fetch-active-by-customer")
+	defer span.End()
+
+	return r.fetch(ctx, `SELECT id, created_at, customer, status, items, payment_id, payment_ids, payment_captured, coupon, discounts, store, taxes, tip, version
+		FROM orders WHERE customer = $1 AND status = $2 ORDER BY created_at DESC LIMIT 1`,
+		customerName, order.StatusOpen)
+}
+
+// Add stores the order, replacing any previously stored version of it. The order is only
+// replaced when it wasn't saved since it was fetched, otherwise order.ErrConflict is
+// returned and the caller has to fetch it again. A customer can only have one open order.
+func (r *OrderReadWrite) Add(ctx context.Context, o *order.Order) error {
+	ctx, span := tracing.Start(ctx, "storage/order/add")
+	defer span.End()
+
+	if o.Version == 0 {
+		if _, err := r.db.NamedExecContext(ctx, `INSERT INTO orders
+			(id, created_at, customer, status, items, payment_id, payment_ids, payment_captured, coupon, discounts, store, taxes, tip, version)
+			VALUES (:id, :created_at, :customer, :status, :items,
+				CAST(NULLIF(:payment_id, '00000000-0000-0000-0000-000000000000') AS UUID), :payment_ids, :payment_captured,
+				:coupon, :discounts, :store, :taxes, :tip, 1)`, o); err != nil {
+			return orderWriteError(err)
+		}
+
+		o.Version = 1
+
+		return nil
+	}
+
+	res, err := r.db.NamedExecContext(ctx, `UPDATE orders SET
+		status = :status,
+		items = :items,
+		payment_id = CAST(NULLIF(:payment_id, '00000000-0000-0000-0000-000000000000') AS UUID),
+		payment_ids = :payment_ids,
+		payment_captured = :payment_captured,
+		coupon = :coupon,
+		discounts = :discounts,
+		taxes = :taxes,
+		tip = :tip,
+		version = version + 1
+		WHERE id = :id AND version = :version`, o)
+	if err != nil {
+		return orderWriteError(err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to save order: %w", err)
+	} else if n == 0 {
+		return fmt.Errorf("%w: %s was saved since version %d", order.ErrConflict, o.ID, o.Version)
+	}
+
+	o.Version++
+
+	return nil
+}
+
+// orderWriteError reports unique violations, either of the id or of the open order of
+// the customer, as conflicts
+func orderWriteError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return fmt.Errorf("%w: %s", order.ErrConflict, pqErr.Message)
+	}
+
+	return fmt.Errorf("failed to save order: %w", err)
+}
+
+func (r *OrderReadWrite) fetch(ctx context.Context, query string, args ...interface{}) (*order.Order, error) {
+	var o order.Order
+	if err := r.db.GetContext(ctx, &o, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, order.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to fetch order: %w", err)
+	}
+
+	if o.Items == nil {
+		o.Items = make(order.Items, 0)
+	}
+
+	o.CreatedAt = o.CreatedAt.UTC()
+
+	return &o, nil
+}
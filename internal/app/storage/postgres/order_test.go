@@ -0,0 +1,249 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/order"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOrder(t *testing.T) *order.Order {
+	o := order.New("anna")
+	require.NoError(t, o.AddItem(&order.Item{Name: "latte", ServingSize: "M", Price: money.New(260, "EUR"), Qty: 2}))
+
+	return o
+}
+
+func TestOrderReadWrite_Add(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	o := newTestOrder(t)
+
+	mock.ExpectExec("INSERT INTO orders (.+) VALUES").
+		WithArgs(o.ID, o.CreatedAt, o.CustomerName, o.Status, sqlmock.AnyArg(), o.PaymentID, sqlmock.AnyArg(), o.PaymentCaptured, o.Coupon, sqlmock.AnyArg(), o.Store, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE orders SET (.+) WHERE id = (.+) AND version = (.+)").
+		WithArgs(order.StatusCheckedOut, sqlmock.AnyArg(), o.PaymentID, sqlmock.AnyArg(), o.PaymentCaptured, o.Coupon, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), o.ID, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	rw := NewOrderReadWrite(db)
+	require.NoError(t, rw.Add(context.Background(), o))
+	assert.Equal(t, 1, o.Version)
+
+	require.NoError(t, o.Checkout())
+	require.NoError(t, rw.Add(context.Background(), o))
+	assert.Equal(t, 2, o.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderReadWrite_AddConflict(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		version int
+		expect  func(sqlmock.Sqlmock)
+	}{
+		{
+			name:    "saved since it was fetched",
+			version: 3,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE orders SET (.+) WHERE id = (.+) AND version = (.+)").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "customer has an open order",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO orders (.+) VALUES").
+					WillReturnError(&pq.Error{Code: uniqueViolation, Message: "duplicate key value violates unique constraint"})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := newMockDB(t)
+			o := newTestOrder(t)
+			o.Version = tt.version
+			tt.expect(mock)
+
+			err := NewOrderReadWrite(db).Add(context.Background(), o)
+			assert.True(t, errors.Is(err, order.ErrConflict), err)
+			assert.Equal(t, tt.version, o.Version)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestOrderReadWrite_FetchByID(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	o := newTestOrder(t)
+
+	o.Tip = &order.Tip{Amount: money.New(80, "EUR"), Barista: "ben"}
+	o.Version = 2
+
+	items, err := o.Items.Value()
+	require.NoError(t, err)
+
+	tip, err := o.Tip.Value()
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT (.+) FROM orders WHERE id").
+		WithArgs(o.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "customer", "status", "items", "payment_id", "payment_ids", "payment_captured", "coupon", "discounts", "store", "taxes", "tip", "version"}).
+			AddRow(o.ID, o.CreatedAt, o.CustomerName, string(o.Status), items, nil, nil, false, "", nil, "", nil, tip, 2))
+
+	fetched, err := NewOrderReadWrite(db).FetchByID(context.Background(), o.ID)
+	require.NoError(t, err)
+	assert.Equal(t, o, fetched)
+
+	mock.ExpectQuery("SELECT (.+) FROM orders WHERE customer").
+		WithArgs("anna", order.StatusOpen).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "customer", "status", "items", "payment_id", "payment_ids", "payment_captured", "coupon", "discounts", "store", "taxes", "tip", "version"}))
+
+	_, err = NewOrderReadWrite(db).FetchActiveByCustomer(context.Background(), "anna")
+	assert.True(t, errors.Is(err, order.ErrNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestOrderReadWrite_Postgres runs against a real database when TEST_POSTGRES_DSN is set,
+// e.g. the one started by docker-compose
+func TestOrderReadWrite_Postgres(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	ctx := context.Background()
+
+	db, err := Open(ctx, dsn)
+	require.NoError(t, err)
+	defer db.Close()
+
+	rw := NewOrderReadWrite(db)
+	o := newTestOrder(t)
+	o.CustomerName = uuid.New().String()
+
+	require.NoError(t, rw.Add(ctx, o))
+
+	active, err := rw.FetchActiveByCustomer(ctx, o.CustomerName)
+	require.NoError(t, err)
+	assert.Equal(t, o.ID, active.ID)
+
+	require.NoError(t, o.Checkout())
+	require.NoError(t, o.MarkPaid(uuid.New()))
+	require.NoError(t, rw.Add(ctx, o))
+
+	_, err = rw.FetchActiveByCustomer(ctx, o.CustomerName)
+	assert.True(t, errors.Is(err, order.ErrNotFound))
+
+	fetched, err := rw.FetchByID(ctx, o.ID)
+	require.NoError(t, err)
+	assert.Equal(t, order.StatusPaid, fetched.Status)
+	assert.Equal(t, o.PaymentID, fetched.PaymentID)
+	assert.Equal(t, o.Total(), fetched.Total())
+}
+
+// TestOrderReadWrite_PostgresConcurrentWriters runs against a real database when
+// TEST_POSTGRES_DSN is set, e.g. the one started by docker-compose
+func TestOrderReadWrite_PostgresConcurrentWriters(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	ctx := context.Background()
+
+	db, err := Open(ctx, dsn)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var (
+		rw       = NewOrderReadWrite(db)
+		customer = uuid.New().String()
+		writers  = 10
+	)
+
+	// every writer opens an order for the same customer, only one of them is stored
+	var (
+		wg      sync.WaitGroup
+		mux     sync.Mutex
+		created []*order.Order
+	)
+
+	for i := 0; i < writers; i++ {
+		o := newTestOrder(t)
+		o.CustomerName = customer
+
+		wg.Add(1)
+		go func(o *order.Order) {
+			defer wg.Done()
+
+			err := rw.Add(ctx, o)
+			if err == nil {
+				mux.Lock()
+				created = append(created, o)
+				mux.Unlock()
+				return
+			}
+
+			assert.True(t, errors.Is(err, order.ErrConflict), err)
+		}(o)
+	}
+
+	wg.Wait()
+	require.Len(t, created, 1)
+
+	// every writer changes the order it fetched, only one of the changes is stored
+	var (
+		updated int
+		fetched = make([]*order.Order, writers)
+	)
+
+	for i := range fetched {
+		fetched[i], err = rw.FetchActiveByCustomer(ctx, customer)
+		require.NoError(t, err)
+		require.NoError(t, fetched[i].AddItem(&order.Item{Name: "espresso", ServingSize: "S", Price: money.New(180, "EUR"), Qty: 1}))
+	}
+
+	for _, o := range fetched {
+		wg.Add(1)
+		go func(o *order.Order) {
+			defer wg.Done()
+
+			err := rw.Add(ctx, o)
+			if err == nil {
+				mux.Lock()
+				updated++
+				mux.Unlock()
+				return
+			}
+
+			assert.True(t, errors.Is(err, order.ErrConflict), err)
+		}(o)
+	}
+
+	wg.Wait()
+	assert.Equal(t, 1, updated)
+
+	stored, err := rw.FetchByID(ctx, created[0].ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Items, 2)
+	assert.Equal(t, 2, stored.Version)
+}
@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+	"github.com/jmoiron/sqlx"
+)
+
+type PaymentReadWrite struct {
+	db *sqlx.DB
+}
+
+func NewPaymentReadWrite(db *sqlx.DB) *PaymentReadWrite {
+	return &PaymentReadWrite{db: db}
+}
+
+// confirmationRow is the database representation of a payment.Confirmation
+type confirmationRow struct {
+	ID       uuid.UUID `db:"id"`
+	OrderID  uuid.UUID `db:"order_id"`
+	Method   string    `db:"method"`
+	Amount   int64     `db:"amount"`
+	Currency string    `db:"currency"`
+	Refunded int64     `db:"refunded"`
+	Status   string    `db:"status"`
+	PayedAt  time.Time `db:"payed_at"`
+	// ExpiresAt is only set for authorizations
+	ExpiresAt      sql.NullTime `db:"expires_at"`
+	GiftCardNumber string       `db:"gift_card_number"`
+	CashTendered   int64        `db:"cash_tendered"`
+	CashChange     int64        `db:"cash_change"`
+	Tip            int64        `db:"tip"`
+	Barista        string       `db:"barista"`
+	// ShiftID is NULL for payments made while no cash drawer was open
+	ShiftID uuid.UUID `db:"shift_id"`
+}
+
+func (r confirmationRow) toConfirmation() *payment.Confirmation {
+	c := &payment.Confirmation{
+		ID:             r.ID,
+		OrderID:        r.OrderID,
+		Method:         r.Method,
+		Amount:         money.New(r.Amount, r.Currency),
+		Refunded:       money.New(r.Refunded, r.Currency),
+		Status:         payment.Status(r.Status),
+		PayedAt:        r.PayedAt.UTC(),
+		GiftCardNumber: r.GiftCardNumber,
+		Tip:            money.New(r.Tip, r.Currency),
+		Barista:        r.Barista,
+		ShiftID:        r.ShiftID,
+	}
+
+	if r.ExpiresAt.Valid {
+		c.ExpiresAt = r.ExpiresAt.Time.UTC()
+	}
+
+	// only cash payments are tendered, and they always are
+	if r.CashTendered != 0 {
+		c.Tendered = money.New(r.CashTendered, r.Currency)
+		c.Change = money.New(r.CashChange, r.Currency)
+	}
+
+	return c
+}
+
+func (r *PaymentReadWrite) FetchByID(ctx context.Context, id uuid.UUID) (*payment.Confirmation, error) {
+	ctx, span := tracing.Start(ctx, "storage/payment/fetch-by-id")
+	defer span.End()
+
+	return r.fetch(ctx, `SELECT id, order_id, method, amount, currency, refunded, status, payed_at, expires_at, gift_card_number,
+		cash_tendered, cash_change, tip, barista, shift_id FROM payment_confirmations WHERE id = $1`, id)
+}
+
+func (r *PaymentReadWrite) FetchByOrderID(ctx context.Context, orderID uuid.UUID) (*payment.Confirmation, error) {
+	ctx, span := tracing.Start(ctx, "storage/payment/fetch-by-order-id")
+	defer span.End()
+
+	return r.fetch(ctx, `SELECT id, order_id, method, amount, currency, refunded, status, payed_at, expires_at, gift_card_number,
+		cash_tendered, cash_change, tip, barista, shift_id FROM payment_confirmations WHERE order_id = $1 ORDER BY payed_at DESC LIMIT 1`, orderID)
+}
+
+func (r *PaymentReadWrite) FetchAllByOrderID(ctx context.Context, orderID uuid.UUID) ([]*payment.Confirmation, error) {
+	ctx, span := tracing.Start(ctx, "storage/payment/fetch-all-by-order-id")
+	defer span.End()
+
+	return r.fetchAll(ctx, `SELECT id, order_id, method, amount, currency, refunded, status, payed_at, expires_at, gift_card_number,
+		cash_tendered, cash_change, tip, barista, shift_id FROM payment_confirmations WHERE order_id = $1 ORDER BY created_at`, orderID)
+}
+
+func (r *PaymentReadWrite) FetchExpiredAuthorizations(ctx context.Context, now time.Time) ([]*payment.Confirmation, error) {
+	ctx, span := tracing.Start(ctx, "storage/payment/fetch-expired-authorizations")
+	defer span.End()
+
+	return r.fetchAll(ctx, `SELECT id, order_id, method, amount, currency, refunded, status, payed_at, expires_at, gift_card_number,
+		cash_tendered, cash_change, tip, barista, shift_id FROM payment_confirmations WHERE status = $1 AND expires_at <= $2`, payment.StatusAuthorized, now)
+}
+
+func (r *PaymentReadWrite) FetchPaidBetween(ctx context.Context, from, to time.Time) ([]*payment.Confirmation, error) {
+	ctx, span := tracing.Start(ctx, "storage/payment/fetch-paid-between")
+	defer span.End()
+
+	return r.fetchAll(ctx, `SELECT id, order_id, method, amount, currency, refunded, status, payed_at, expires_at, gift_card_number,
+		cash_tendered, cash_change, tip, barista, shift_id FROM payment_confirmations
+		WHERE payed_at >= $1 AND payed_at < $2 ORDER BY payed_at`, from, to)
+}
+
+func (r *PaymentReadWrite) fetchAll(ctx context.Context, query string, args ...interface{}) ([]*payment.Confirmation, error) {
+	var rows []confirmationRow
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to fetch payment confirmations: %w", err)
+	}
+
+	confirmations := make([]*payment.Confirmation, 0, len(rows))
+	for _, row := range rows {
+		confirmations = append(confirmations, row.toConfirmation())
+	}
+
+	return confirmations, nil
+}
+
+func (r *PaymentReadWrite) fetch(ctx context.Context, query string, args ...interface{}) (*payment.Confirmation, error) {
+	var row confirmationRow
+	if err := r.db.GetContext(ctx, &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, payment.ErrConfirmationNotFound
+		}
+
+		return nil, fmt.Errorf("failed to fetch payment confirmation: %w", err)
+	}
+
+	return row.toConfirmation(), nil
+}
+
+// Add stores the confirmation, updating the mutable fields of a previously stored one
+func (r *PaymentReadWrite) Add(ctx context.Context, c *payment.Confirmation) error {
+	ctx, span := tracing.Start(ctx, "storage/payment/add")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, `INSERT INTO payment_confirmations
+		(id, order_id, method, amount, currency, refunded, status, payed_at, expires_at, gift_card_number,
+		cash_tendered, cash_change, tip, barista, shift_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14,
+			CAST(NULLIF($15, '00000000-0000-0000-0000-000000000000') AS UUID))
+		ON CONFLICT (id) DO UPDATE SET
+			refunded = EXCLUDED.refunded,
+			status = EXCLUDED.status,
+			payed_at = EXCLUDED.payed_at`,
+		c.ID, c.OrderID, c.Method, c.Amount.Amount, c.Amount.Currency, c.Refunded.Amount, c.Status, c.PayedAt,
+		sql.NullTime{Time: c.ExpiresAt, Valid: !c.ExpiresAt.IsZero()}, c.GiftCardNumber,
+		c.Tendered.Amount, c.Change.Amount, c.Tip.Amount, c.Barista, c.ShiftID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert payment confirmation: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	t.Cleanup(func() { db.Close() })
+
+	return sqlx.NewDb(db, "postgres"), mock
+}
+
+func TestPaymentReadWrite_Add(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	c := &payment.Confirmation{
+		ID:       uuid.New(),
+		OrderID:  uuid.New(),
+		Method:   "credit_card",
+		Amount:   money.New(780, "EUR"),
+		Refunded: money.New(0, "EUR"),
+		Status:   payment.StatusCaptured,
+		PayedAt:  time.Now().UTC(),
+	}
+
+	mock.ExpectExec("INSERT INTO payment_confirmations").
+		WithArgs(c.ID, c.OrderID, c.Method, c.Amount.Amount, c.Amount.Currency, c.Refunded.Amount, c.Status, c.PayedAt, sqlmock.AnyArg(), c.GiftCardNumber, c.Tendered.Amount, c.Change.Amount, c.Tip.Amount, c.Barista, c.ShiftID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, NewPaymentReadWrite(db).Add(context.Background(), c))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaymentReadWrite_FetchByOrderID(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	id, orderID, payedAt := uuid.New(), uuid.New(), time.Now().UTC()
+
+	mock.ExpectQuery("SELECT (.+) FROM payment_confirmations WHERE order_id").
+		WithArgs(orderID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "method", "amount", "currency", "refunded", "status", "payed_at", "expires_at", "gift_card_number", "cash_tendered", "cash_change", "tip", "barista", "shift_id"}).
+			AddRow(id, orderID, "apple_pay", 780, "EUR", 200, "partially_refunded", payedAt, nil, "", 0, 0, 0, "", nil))
+
+	c, err := NewPaymentReadWrite(db).FetchByOrderID(context.Background(), orderID)
+	require.NoError(t, err)
+	assert.Equal(t, id, c.ID)
+	assert.Equal(t, money.New(780, "EUR"), c.Amount)
+	assert.Equal(t, money.New(200, "EUR"), c.Refunded)
+
+	mock.ExpectQuery("SELECT (.+) FROM payment_confirmations WHERE id").
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "method", "amount", "currency", "payed_at"}))
+
+	_, err = NewPaymentReadWrite(db).FetchByID(context.Background(), id)
+	assert.True(t, errors.Is(err, payment.ErrConfirmationNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaymentReadWrite_FetchPaidBetween(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	to := time.Now().UTC()
+	from := to.Add(-24 * time.Hour)
+	shiftID := uuid.New()
+
+	mock.ExpectQuery("SELECT (.+) FROM payment_confirmations WHERE payed_at").
+		WithArgs(from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "method", "amount", "currency", "refunded", "status", "payed_at", "expires_at", "gift_card_number", "cash_tendered", "cash_change", "tip", "barista", "shift_id"}).
+			AddRow(uuid.New(), uuid.New(), "cash", 880, "EUR", 0, "captured", to, nil, "", 1000, 120, 100, "anna", shiftID.String()).
+			AddRow(uuid.New(), uuid.New(), "credit_card", 780, "EUR", 0, "captured", to, nil, "", 0, 0, 0, "", nil))
+
+	confirmations, err := NewPaymentReadWrite(db).FetchPaidBetween(context.Background(), from, to)
+	require.NoError(t, err)
+	require.Len(t, confirmations, 2)
+	assert.Equal(t, money.New(100, "EUR"), confirmations[0].Tip)
+	assert.Equal(t, "anna", confirmations[0].Barista)
+	assert.Equal(t, shiftID, confirmations[0].ShiftID)
+	assert.Equal(t, uuid.Nil, confirmations[1].ShiftID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
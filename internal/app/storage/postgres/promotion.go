@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/italolelis/coffee-shop/internal/app/promotion"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+	"github.com/jmoiron/sqlx"
+)
+
+type PromotionReadWrite struct {
+	db *sqlx.DB
+}
+
+func NewPromotionReadWrite(db *sqlx.DB) *PromotionReadWrite {
+	return &PromotionReadWrite{db: db}
+}
+
+// promotionRow is the database representation of a promotion.Promotion. The usage of
+// coupons has its own columns, so it can be counted without rewriting the rule.
+type promotionRow struct {
+	Code      string       `db:"code"`
+	Rule      []byte       `db:"rule"`
+	MaxUses   int          `db:"max_uses"`
+	Uses      int          `db:"uses"`
+	ExpiresAt sql.NullTime `db:"expires_at"`
+}
+
+func (r promotionRow) toPromotion() (*promotion.Promotion, error) {
+	var p promotion.Promotion
+	if err := json.Unmarshal(r.Rule, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode promotion: %w", err)
+	}
+
+	p.Code, p.MaxUses, p.Uses = r.Code, r.MaxUses, r.Uses
+	p.ExpiresAt = time.Time{}
+	if r.ExpiresAt.Valid {
+		p.ExpiresAt = r.ExpiresAt.Time.UTC()
+	}
+
+	return &p, nil
+}
+
+func (r *PromotionReadWrite) FetchAutomatic(ctx context.Context) ([]*promotion.Promotion, error) {
+	ctx, span := tracing.Start(ctx, "storage/promotion/fetch-automatic")
+	defer span.End()
+
+	var rows []promotionRow
+	if err := r.db.SelectContext(ctx, &rows, `SELECT code, rule, max_uses, uses, expires_at
+		FROM promotions WHERE code = '' ORDER BY id`); err != nil {
+		return nil, fmt.Errorf("failed to fetch promotions: %w", err)
+	}
+
+	promotions := make([]*promotion.Promotion, 0, len(rows))
+	for _, row := range rows {
+		p, err := row.toPromotion()
+		if err != nil {
+			return nil, err
+		}
+
+		promotions = append(promotions, p)
+	}
+
+	return promotions, nil
+}
+
+func (r *PromotionReadWrite) FetchByCode(ctx context.Context, code string) (*promotion.Promotion, error) {
+	ctx, span := tracing.Start(ctx, "storage/promotion/fetch-by-code")
+	defer span.End()
+
+	var row promotionRow
+	if err := r.db.GetContext(ctx, &row, `SELECT code, rule, max_uses, uses, expires_at
+		FROM promotions WHERE code = $1`, strings.ToUpper(code)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, promotion.ErrCouponNotFound
+		}
+
+		return nil, fmt.Errorf("failed to fetch promotion: %w", err)
+	}
+
+	return row.toPromotion()
+}
+
+func (r *PromotionReadWrite) Add(ctx context.Context, p *promotion.Promotion) error {
+	ctx, span := tracing.Start(ctx, "storage/promotion/add")
+	defer span.End()
+
+	return addPromotion(ctx, r.db, p)
+}
+
+// Seed adds the promotions when none are stored yet, so the defaults don't come back
+// after they were changed
+func (r *PromotionReadWrite) Seed(ctx context.Context, promotions ...*promotion.Promotion) error {
+	ctx, span := tracing.Start(ctx, "storage/promotion/seed")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE promotions IN EXCLUSIVE MODE`); err != nil {
+		return fmt.Errorf("failed to lock promotions: %w", err)
+	}
+
+	var stored int
+	if err := tx.GetContext(ctx, &stored, `SELECT COUNT(*) FROM promotions`); err != nil {
+		return fmt.Errorf("failed to count promotions: %w", err)
+	}
+
+	if stored > 0 {
+		return nil
+	}
+
+	for _, p := range promotions {
+		if err := addPromotion(ctx, tx, p); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Redeem counts the use in a single conditional update, so concurrent redemptions
+// can't go over the usage limit of the coupon
+func (r *PromotionReadWrite) Redeem(ctx context.Context, code string, now time.Time) error {
+	ctx, span := tracing.Start(ctx, "storage/promotion/redeem")
+	defer span.End()
+
+	code = strings.ToUpper(code)
+
+	res, err := r.db.ExecContext(ctx, `UPDATE promotions SET uses = uses + 1
+		WHERE code = $1 AND (max_uses = 0 OR uses < max_uses) AND (expires_at IS NULL OR expires_at > $2)`,
+		code, now)
+	if err != nil {
+		return fmt.Errorf("failed to redeem coupon: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to redeem coupon: %w", err)
+	} else if n == 1 {
+		return nil
+	}
+
+	// nothing was updated, tell the caller why
+	p, err := r.FetchByCode(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	if err := p.Usable(now); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: %s", promotion.ErrCouponUsedUp, code)
+}
+
+func (r *PromotionReadWrite) Release(ctx context.Context, code string) error {
+	ctx, span := tracing.Start(ctx, "storage/promotion/release")
+	defer span.End()
+
+	res, err := r.db.ExecContext(ctx, `UPDATE promotions SET uses = GREATEST(uses - 1, 0) WHERE code = $1`,
+		strings.ToUpper(code))
+	if err != nil {
+		return fmt.Errorf("failed to release coupon: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to release coupon: %w", err)
+	} else if n == 0 {
+		return promotion.ErrCouponNotFound
+	}
+
+	return nil
+}
+
+func addPromotion(ctx context.Context, e sqlx.ExecerContext, p *promotion.Promotion) error {
+	rule, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to encode promotion: %w", err)
+	}
+
+	var expiresAt sql.NullTime
+	if !p.ExpiresAt.IsZero() {
+		expiresAt = sql.NullTime{Time: p.ExpiresAt, Valid: true}
+	}
+
+	if _, err := e.ExecContext(ctx, `INSERT INTO promotions (code, rule, max_uses, uses, expires_at)
+		VALUES ($1, $2, $3, $4, $5)`,
+		strings.ToUpper(p.Code), rule, p.MaxUses, p.Uses, expiresAt); err != nil {
+		return fmt.Errorf("failed to insert promotion: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/promotion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromotionReadWrite_FetchByCode(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	expiresAt := time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	rule, err := json.Marshal(&promotion.Promotion{Name: "welcome", Kind: promotion.KindPercentage, Percent: 10})
+	require.NoError(t, err)
+
+	columns := []string{"code", "rule", "max_uses", "uses", "expires_at"}
+	mock.ExpectQuery("SELECT (.+) FROM promotions WHERE code = (.+)").
+		WithArgs("WELCOME10").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("WELCOME10", rule, 1000, 3, expiresAt))
+	mock.ExpectQuery("SELECT (.+) FROM promotions WHERE code = (.+)").
+		WithArgs("UNKNOWN").
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	rw := NewPromotionReadWrite(db)
+
+	p, err := rw.FetchByCode(context.Background(), "welcome10")
+	require.NoError(t, err)
+	assert.Equal(t, &promotion.Promotion{
+		Name:      "welcome",
+		Kind:      promotion.KindPercentage,
+		Percent:   10,
+		Code:      "WELCOME10",
+		MaxUses:   1000,
+		Uses:      3,
+		ExpiresAt: expiresAt,
+	}, p)
+
+	_, err = rw.FetchByCode(context.Background(), "unknown")
+	assert.True(t, errors.Is(err, promotion.ErrCouponNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPromotionReadWrite_Redeem(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now     = time.Date(2020, 6, 1, 15, 30, 0, 0, time.UTC)
+		columns = []string{"code", "rule", "max_uses", "uses", "expires_at"}
+	)
+
+	rule, err := json.Marshal(&promotion.Promotion{Name: "welcome", Kind: promotion.KindPercentage, Percent: 10})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		code        string
+		rows        *sqlmock.Rows
+		updated     int64
+		expectedErr error
+	}{
+		{
+			name:    "redeemed",
+			code:    "WELCOME10",
+			updated: 1,
+		},
+		{
+			name:        "not found",
+			code:        "UNKNOWN",
+			rows:        sqlmock.NewRows(columns),
+			expectedErr: promotion.ErrCouponNotFound,
+		},
+		{
+			name:        "expired",
+			code:        "JUNE",
+			rows:        sqlmock.NewRows(columns).AddRow("JUNE", rule, 0, 0, now),
+			expectedErr: promotion.ErrCouponExpired,
+		},
+		{
+			name:        "used up",
+			code:        "WELCOME10",
+			rows:        sqlmock.NewRows(columns).AddRow("WELCOME10", rule, 1000, 1000, nil),
+			expectedErr: promotion.ErrCouponUsedUp,
+		},
+		{
+			name:        "released after the update",
+			code:        "WELCOME10",
+			rows:        sqlmock.NewRows(columns).AddRow("WELCOME10", rule, 1000, 999, nil),
+			expectedErr: promotion.ErrCouponUsedUp,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := newMockDB(t)
+			mock.ExpectExec(`UPDATE promotions SET uses = uses \+ 1 WHERE code = (.+) AND \(max_uses = 0 OR uses < max_uses\)`).
+				WithArgs(tt.code, now).
+				WillReturnResult(sqlmock.NewResult(0, tt.updated))
+			if tt.rows != nil {
+				mock.ExpectQuery("SELECT (.+) FROM promotions WHERE code = (.+)").
+					WithArgs(tt.code).
+					WillReturnRows(tt.rows)
+			}
+
+			err := NewPromotionReadWrite(db).Redeem(context.Background(), tt.code, now)
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, tt.expectedErr), err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPromotionReadWrite_Release(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	mock.ExpectExec(`UPDATE promotions SET uses = GREATEST\(uses - 1, 0\) WHERE code = (.+)`).
+		WithArgs("WELCOME10").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE promotions SET uses = GREATEST\(uses - 1, 0\) WHERE code = (.+)`).
+		WithArgs("UNKNOWN").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rw := NewPromotionReadWrite(db)
+	require.NoError(t, rw.Release(context.Background(), "welcome10"))
+	assert.True(t, errors.Is(rw.Release(context.Background(), "unknown"), promotion.ErrCouponNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPromotionReadWrite_Seed(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defaults := promotion.DefaultPromotions()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("LOCK TABLE promotions").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM promotions`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	for _, p := range defaults {
+		mock.ExpectExec("INSERT INTO promotions").
+			WithArgs(p.Code, sqlmock.AnyArg(), p.MaxUses, 0, nil).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	mock.ExpectCommit()
+
+	// the promotions aren't seeded again once there are any
+	mock.ExpectBegin()
+	mock.ExpectExec("LOCK TABLE promotions").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM promotions`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(len(defaults)))
+	mock.ExpectRollback()
+
+	rw := NewPromotionReadWrite(db)
+	require.NoError(t, rw.Seed(context.Background(), defaults...))
+	require.NoError(t, rw.Seed(context.Background(), defaults...))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPromotionReadWrite_Postgres runs against a real database when TEST_POSTGRES_DSN is set,
+// e.g. the one started by docker-compose
+func TestPromotionReadWrite_Postgres(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	ctx := context.Background()
+
+	db, err := Open(ctx, dsn)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var (
+		rw   = NewPromotionReadWrite(db)
+		code = strings.ToUpper(uuid.New().String())
+		now  = time.Now().UTC()
+	)
+
+	require.NoError(t, rw.Add(ctx, &promotion.Promotion{Name: "limited", Kind: promotion.KindPercentage, Percent: 10, Code: code, MaxUses: 5}))
+
+	// more customers than uses redeem the coupon at once, only MaxUses of them get it
+	var (
+		wg      sync.WaitGroup
+		mux     sync.Mutex
+		redeems int
+	)
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := rw.Redeem(ctx, code, now)
+			if err == nil {
+				mux.Lock()
+				redeems++
+				mux.Unlock()
+				return
+			}
+
+			assert.True(t, errors.Is(err, promotion.ErrCouponUsedUp), err)
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(t, 5, redeems)
+
+	p, err := rw.FetchByCode(ctx, code)
+	require.NoError(t, err)
+	assert.Equal(t, 5, p.Uses)
+
+	require.NoError(t, rw.Release(ctx, code))
+	require.NoError(t, rw.Redeem(ctx, code, now))
+}
@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+	"github.com/jmoiron/sqlx"
+)
+
+type RefundReadWrite struct {
+	db *sqlx.DB
+}
+
+func NewRefundReadWrite(db *sqlx.DB) *RefundReadWrite {
+	return &RefundReadWrite{db: db}
+}
+
+// refundRow is the database representation of a payment.Refund
+type refundRow struct {
+	ID         uuid.UUID `db:"id"`
+	PaymentID  uuid.UUID `db:"payment_id"`
+	OrderID    uuid.UUID `db:"order_id"`
+	Amount     int64     `db:"amount"`
+	Currency   string    `db:"currency"`
+	Reason     string    `db:"reason"`
+	RefundedAt time.Time `db:"refunded_at"`
+}
+
+func (r refundRow) toRefund() *payment.Refund {
+	return &payment.Refund{
+		ID:         r.ID,
+		PaymentID:  r.PaymentID,
+		OrderID:    r.OrderID,
+		Amount:     money.New(r.Amount, r.Currency),
+		Reason:     r.Reason,
+		RefundedAt: r.RefundedAt.UTC(),
+	}
+}
+
+func (r *RefundReadWrite) FetchByPaymentID(ctx context.Context, paymentID uuid.UUID) ([]*payment.Refund, error) {
+	ctx, span := tracing.Start(ctx, "storage/refund/fetch-by-payment-id")
+	defer span.End()
+
+	var rows []refundRow
+	if err := r.db.SelectContext(ctx, &rows, `SELECT id, payment_id, order_id, amount, currency, reason, refunded_at
+		FROM payment_refunds WHERE payment_id = $1 ORDER BY refunded_at`, paymentID); err != nil {
+		return nil, fmt.Errorf("failed to fetch refunds: %w", err)
+	}
+
+	refunds := make([]*payment.Refund, 0, len(rows))
+	for _, row := range rows {
+		refunds = append(refunds, row.toRefund())
+	}
+
+	return refunds, nil
+}
+
+func (r *RefundReadWrite) Add(ctx context.Context, rf *payment.Refund) error {
+	ctx, span := tracing.Start(ctx, "storage/refund/add")
+	defer span.End()
+
+	return insertRefund(ctx, r.db, rf)
+}
+
+// Refund locks the payment row for the duration of the transaction, so concurrent
+// refunds of the same payment are serialized. The refund and the refunded amount of
+// the payment are committed together.
+func (r *RefundReadWrite) Refund(
+	ctx context.Context,
+	paymentID uuid.UUID,
+	fn func(*payment.Confirmation) (*payment.Refund, error),
+) (*payment.Confirmation, *payment.Refund, error) {
+	ctx, span := tracing.Start(ctx, "storage/refund/refund")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var row confirmationRow
+	if err := tx.GetContext(ctx, &row, `SELECT id, order_id, method, amount, currency, refunded, status, payed_at, expires_at,
+		gift_card_number, cash_tendered, cash_change, tip, barista, shift_id FROM payment_confirmations
+		WHERE id = $1 FOR UPDATE`, paymentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, payment.ErrConfirmationNotFound
+		}
+
+		return nil, nil, fmt.Errorf("failed to lock payment confirmation: %w", err)
+	}
+
+	c := row.toConfirmation()
+
+	rf, err := fn(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := insertRefund(ctx, tx, rf); err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE payment_confirmations SET refunded = $2, status = $3 WHERE id = $1`,
+		c.ID, c.Refunded.Amount, c.Status); err != nil {
+		return nil, nil, fmt.Errorf("failed to update payment confirmation: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit refund: %w", err)
+	}
+
+	return c, rf, nil
+}
+
+func insertRefund(ctx context.Context, e sqlx.ExecerContext, rf *payment.Refund) error {
+	_, err := e.ExecContext(ctx, `INSERT INTO payment_refunds
+		(id, payment_id, order_id, amount, currency, reason, refunded_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		rf.ID, rf.PaymentID, rf.OrderID, rf.Amount.Amount, rf.Amount.Currency, rf.Reason, rf.RefundedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert refund: %w", err)
+	}
+
+	return nil
+}
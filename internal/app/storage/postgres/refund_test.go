@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/italolelis/coffee-shop/internal/app/payment"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefundReadWrite(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	rf := &payment.Refund{
+		ID:         uuid.New(),
+		PaymentID:  uuid.New(),
+		OrderID:    uuid.New(),
+		Amount:     money.New(260, "EUR"),
+		Reason:     "cold coffee",
+		RefundedAt: time.Now().UTC(),
+	}
+
+	mock.ExpectExec("INSERT INTO payment_refunds").
+		WithArgs(rf.ID, rf.PaymentID, rf.OrderID, rf.Amount.Amount, rf.Amount.Currency, rf.Reason, rf.RefundedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectQuery("SELECT (.+) FROM payment_refunds WHERE payment_id").
+		WithArgs(rf.PaymentID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "payment_id", "order_id", "amount", "currency", "reason", "refunded_at"}).
+			AddRow(rf.ID, rf.PaymentID, rf.OrderID, 260, "EUR", rf.Reason, rf.RefundedAt))
+
+	rw := NewRefundReadWrite(db)
+	require.NoError(t, rw.Add(context.Background(), rf))
+
+	refunds, err := rw.FetchByPaymentID(context.Background(), rf.PaymentID)
+	require.NoError(t, err)
+	require.Len(t, refunds, 1)
+	assert.Equal(t, rf, refunds[0])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefundReadWrite_Refund(t *testing.T) {
+	t.Parallel()
+
+	var (
+		paymentID = uuid.New()
+		orderID   = uuid.New()
+		payedAt   = time.Now().UTC()
+		columns   = []string{"id", "order_id", "method", "amount", "currency", "refunded", "status", "payed_at", "expires_at",
+			"gift_card_number", "cash_tendered", "cash_change", "tip", "barista", "shift_id"}
+	)
+
+	db, mock := newMockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM payment_confirmations WHERE id = (.+) FOR UPDATE").
+		WithArgs(paymentID).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(paymentID, orderID, "credit_card", 780, "EUR", 200, "partially_refunded", payedAt, nil, "", 0, 0, 0, "", nil))
+	mock.ExpectExec("INSERT INTO payment_refunds").
+		WithArgs(sqlmock.AnyArg(), paymentID, orderID, int64(300), "EUR", "cold coffee", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE payment_confirmations SET refunded = (.+), status = (.+) WHERE id = (.+)").
+		WithArgs(paymentID, int64(500), payment.StatusPartiallyRefunded).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	rw := NewRefundReadWrite(db)
+
+	c, rf, err := rw.Refund(context.Background(), paymentID, func(c *payment.Confirmation) (*payment.Refund, error) {
+		return c.ApplyRefund(payment.RefundRequest{Amount: money.New(300, "EUR"), Reason: "cold coffee"})
+	})
+	require.NoError(t, err)
+	assert.Equal(t, money.New(500, "EUR"), c.Refunded)
+	assert.Equal(t, money.New(300, "EUR"), rf.Amount)
+
+	// a refund over what is left rolls back without saving anything
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM payment_confirmations WHERE id = (.+) FOR UPDATE").
+		WithArgs(paymentID).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(paymentID, orderID, "credit_card", 780, "EUR", 500, "partially_refunded", payedAt, nil, "", 0, 0, 0, "", nil))
+	mock.ExpectRollback()
+
+	_, _, err = rw.Refund(context.Background(), paymentID, func(c *payment.Confirmation) (*payment.Refund, error) {
+		return c.ApplyRefund(payment.RefundRequest{Amount: money.New(300, "EUR")})
+	})
+	assert.True(t, errors.Is(err, payment.ErrRefundExceedsPayment))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM payment_confirmations WHERE id = (.+) FOR UPDATE").
+		WithArgs(orderID).
+		WillReturnRows(sqlmock.NewRows(columns))
+	mock.ExpectRollback()
+
+	_, _, err = rw.Refund(context.Background(), orderID, func(c *payment.Confirmation) (*payment.Refund, error) {
+		return c.ApplyRefund(payment.RefundRequest{})
+	})
+	assert.True(t, errors.Is(err, payment.ErrConfirmationNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
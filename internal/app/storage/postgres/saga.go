@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/italolelis/coffee-shop/internal/app/order"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+	"github.com/jmoiron/sqlx"
+)
+
+type SagaReadWrite struct {
+	db *sqlx.DB
+}
+
+func NewSagaReadWrite(db *sqlx.DB) *SagaReadWrite {
+	return &SagaReadWrite{db: db}
+}
+
+// FetchIncomplete returns the sagas that didn't reach a final step and weren't updated
+// since the given time, oldest first
+func (r *SagaReadWrite) FetchIncomplete(ctx context.Context, updatedBefore time.Time) ([]*order.CheckoutSaga, error) {
+	ctx, span := tracing.Start(ctx, "storage/saga/fetch-incomplete")
+	defer span.End()
+
+	sagas := make([]*order.CheckoutSaga, 0)
+	if err := r.db.SelectContext(ctx, &sagas, `SELECT id, order_id, payment_id, step, reason, created_at, updated_at
+		FROM checkout_sagas WHERE step NOT IN ($1, $2, $3) AND updated_at < $4 ORDER BY created_at`,
+		order.SagaCompleted, order.SagaCompensated, order.SagaAborted, updatedBefore); err != nil {
+		return nil, fmt.Errorf("failed to fetch checkout sagas: %w", err)
+	}
+
+	for _, s := range sagas {
+		s.CreatedAt = s.CreatedAt.UTC()
+		s.UpdatedAt = s.UpdatedAt.UTC()
+	}
+
+	return sagas, nil
+}
+
+// Add stores the saga, replacing any previously stored version of it
+func (r *SagaReadWrite) Add(ctx context.Context, s *order.CheckoutSaga) error {
+	ctx, span := tracing.Start(ctx, "storage/saga/add")
+	defer span.End()
+
+	if _, err := r.db.NamedExecContext(ctx, `INSERT INTO checkout_sagas
+		(id, order_id, payment_id, step, reason, created_at, updated_at)
+		VALUES (:id, :order_id, CAST(NULLIF(:payment_id, '00000000-0000-0000-0000-000000000000') AS UUID),
+			:step, :reason, :created_at, :updated_at)
+		ON CONFLICT (id) DO UPDATE SET
+			payment_id = EXCLUDED.payment_id,
+			step = EXCLUDED.step,
+			reason = EXCLUDED.reason,
+			updated_at = EXCLUDED.updated_at`, s); err != nil {
+		return fmt.Errorf("failed to save checkout saga: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,249 @@
+package tax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/italolelis/coffee-shop/internal/app/order"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/italolelis/coffee-shop/internal/pkg/tracing"
+)
+
+// Any matches every store or category in the key of a rate
+const Any = "*"
+
+// MaxRate is the highest rate in basis points, which is 100%
+const MaxRate = 10000
+
+var ErrInvalidRate = errors.New("invalid tax rate")
+
+// Mode tells if prices include taxes or not
+type Mode string
+
+const (
+	// ModeInclusive prices include the taxes, which are only broken down
+	ModeInclusive Mode = "inclusive"
+	// ModeExclusive prices don't include the taxes, which are added to the total
+	ModeExclusive Mode = "exclusive"
+)
+
+// Rate is the tax rate of the products of a category served in a store. An empty
+// store or category matches any of them.
+type Rate struct {
+	Store    string
+	Category string
+	// Rate is in basis points, e.g. 1900 is 19%
+	Rate int64
+}
+
+// Engine works out the taxes of orders from the configured rates
+type Engine struct {
+	mode  Mode
+	rates []Rate
+}
+
+// NewEngine creates a tax engine, orders aren't taxed when no rates are given. A store
+// and category can only have one rate, so the rate of an item never depends on the
+// order the rates are given in.
+func NewEngine(mode Mode, rates ...Rate) (*Engine, error) {
+	if mode != ModeInclusive && mode != ModeExclusive {
+		return nil, fmt.Errorf("tax mode must be %s or %s, got %q", ModeInclusive, ModeExclusive, mode)
+	}
+
+	seen := make(map[string]bool, len(rates))
+	for _, r := range rates {
+		if r.Rate < 0 || r.Rate > MaxRate {
+			return nil, fmt.Errorf("%w: %s must be between 0 and %d basis points, got %d", ErrInvalidRate, r.key(), MaxRate, r.Rate)
+		}
+
+		if seen[r.key()] {
+			return nil, fmt.Errorf("%w: %s has more than one rate", ErrInvalidRate, r.key())
+		}
+
+		seen[r.key()] = true
+	}
+
+	return &Engine{mode: mode, rates: rates}, nil
+}
+
+// ParseRates parses rates keyed by "store/category" with percentages up to 100 as
+// values, e.g. {"eat-in": "19", "take-away/drinks": "7"}. The store or the category
+// can be "*" to match any of them, so "*" alone is the default rate. Keys naming the
+// same store and category, such as "eat-in" and "eat-in/*", are rejected. The rates
+// are sorted by key, so they are the same on every run.
+func ParseRates(rates map[string]string) ([]Rate, error) {
+	keys := make([]string, 0, len(rates))
+	for key := range rates {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var (
+		parsed = make([]Rate, 0, len(rates))
+		seen   = make(map[string]string, len(rates))
+	)
+
+	for _, key := range keys {
+		var (
+			r     Rate
+			value = rates[key]
+		)
+
+		parts := strings.SplitN(key, "/", 2)
+		if parts[0] == "" || (len(parts) == 2 && parts[1] == "") {
+			return nil, fmt.Errorf("%w: %q must be a store, a category or %q", ErrInvalidRate, key, Any)
+		}
+
+		if parts[0] != Any {
+			r.Store = parts[0]
+		}
+
+		if len(parts) == 2 && parts[1] != Any {
+			r.Category = parts[1]
+		}
+
+		if other, ok := seen[r.key()]; ok {
+			return nil, fmt.Errorf("%w: %q and %q are the same rate", ErrInvalidRate, other, key)
+		}
+
+		seen[r.key()] = key
+
+		percent, err := strconv.ParseFloat(value, 64)
+		if err != nil || percent < 0 || percent > 100 {
+			return nil, fmt.Errorf("%w: %s must be a percentage between 0 and 100, got %q", ErrInvalidRate, key, value)
+		}
+
+		r.Rate = int64(percent*100 + 0.5)
+		parsed = append(parsed, r)
+	}
+
+	return parsed, nil
+}
+
+// Rate returns the most specific rate of a category in a store, preferring rates of the
+// store and the category, then of the store, then of the category and then the default
+func (e *Engine) Rate(store, category string) (Rate, bool) {
+	var (
+		best  Rate
+		score = -1
+	)
+
+	for _, r := range e.rates {
+		if (r.Store != "" && r.Store != store) || (r.Category != "" && r.Category != category) {
+			continue
+		}
+
+		s := 0
+		if r.Store != "" {
+			s += 2
+		}
+
+		if r.Category != "" {
+			s++
+		}
+
+		if s > score {
+			best, score = r, s
+		}
+	}
+
+	return best, score >= 0
+}
+
+// Taxes returns a tax line for each rate of the order. The discounts of the order are
+// spread over the rates by their share of the subtotal, so taxes are worked out on
+// what the customer actually pays.
+func (e *Engine) Taxes(ctx context.Context, o *order.Order) (order.Taxes, error) {
+	_, span := tracing.Start(ctx, "tax/taxes")
+	defer span.End()
+
+	if len(o.Items) == 0 {
+		return nil, nil
+	}
+
+	var (
+		currency = o.Currency()
+		rates    []int64
+		bases    = make(map[int64]money.Money)
+	)
+
+	for _, i := range o.Items {
+		r, _ := e.Rate(o.Store, i.Category)
+
+		base, ok := bases[r.Rate]
+		if !ok {
+			base = money.New(0, currency)
+			rates = append(rates, r.Rate)
+		}
+
+		base.Amount += i.Subtotal().Amount
+		bases[r.Rate] = base
+	}
+
+	var (
+		subtotal = o.Subtotal()
+		discount = o.DiscountTotal()
+		left     = discount.Amount
+		taxes    = make(order.Taxes, 0, len(rates))
+	)
+
+	for idx, rate := range rates {
+		base := bases[rate]
+
+		share := left
+		if idx < len(rates)-1 && subtotal.Amount > 0 {
+			share = discount.MulFrac(base.Amount, subtotal.Amount, money.RoundHalfUp).Amount
+		}
+
+		if share > base.Amount {
+			share = base.Amount
+		}
+
+		base.Amount -= share
+		left -= share
+
+		if rate == 0 {
+			continue
+		}
+
+		t := &order.TaxLine{
+			Name:      "Tax " + formatRate(rate),
+			Rate:      rate,
+			Inclusive: e.mode == ModeInclusive,
+			Base:      base,
+		}
+
+		if t.Inclusive {
+			t.Amount = base.MulFrac(rate, 10000+rate, money.RoundHalfUp)
+		} else {
+			t.Amount = base.MulFrac(rate, 10000, money.RoundHalfUp)
+		}
+
+		taxes = append(taxes, t)
+	}
+
+	return taxes, nil
+}
+
+func (r Rate) key() string {
+	store, category := r.Store, r.Category
+	if store == "" {
+		store = Any
+	}
+
+	if category == "" {
+		category = Any
+	}
+
+	return store + "/" + category
+}
+
+// formatRate formats a rate in basis points as a percentage, e.g. 750 as "7.5%"
+func formatRate(rate int64) string {
+	return strconv.FormatFloat(float64(rate)/100, 'f', -1, 64) + "%"
+}
@@ -0,0 +1,226 @@
+package tax
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/italolelis/coffee-shop/internal/app/order"
+	"github.com/italolelis/coffee-shop/internal/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		rates         map[string]string
+		expectedRates []Rate
+		expectedErr   error
+	}{
+		{
+			name:          "default rate",
+			rates:         map[string]string{"*": "19"},
+			expectedRates: []Rate{{Rate: 1900}},
+		},
+		{
+			name:          "store and category",
+			rates:         map[string]string{"take-away/food": "7.5"},
+			expectedRates: []Rate{{Store: "take-away", Category: "food", Rate: 750}},
+		},
+		{
+			name:          "any store",
+			rates:         map[string]string{"*/drinks": "10"},
+			expectedRates: []Rate{{Category: "drinks", Rate: 1000}},
+		},
+		{
+			name:        "not a number",
+			rates:       map[string]string{"eat-in": "nineteen"},
+			expectedErr: ErrInvalidRate,
+		},
+		{
+			name:        "negative",
+			rates:       map[string]string{"eat-in": "-1"},
+			expectedErr: ErrInvalidRate,
+		},
+		{
+			name:        "above 100%",
+			rates:       map[string]string{"eat-in": "100.5"},
+			expectedErr: ErrInvalidRate,
+		},
+		{
+			name:        "same store",
+			rates:       map[string]string{"eat-in": "19", "eat-in/*": "7"},
+			expectedErr: ErrInvalidRate,
+		},
+		{
+			name:        "same default",
+			rates:       map[string]string{"*": "19", "*/*": "7"},
+			expectedErr: ErrInvalidRate,
+		},
+		{
+			name:        "empty store",
+			rates:       map[string]string{"/drinks": "7"},
+			expectedErr: ErrInvalidRate,
+		},
+		{
+			name:  "sorted by key",
+			rates: map[string]string{"take-away": "7", "*": "19", "*/drinks": "10", "eat-in/food": "19"},
+			expectedRates: []Rate{
+				{Rate: 1900},
+				{Category: "drinks", Rate: 1000},
+				{Store: "eat-in", Category: "food", Rate: 1900},
+				{Store: "take-away", Rate: 700},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rates, err := ParseRates(tt.rates)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRates, rates)
+		})
+	}
+}
+
+func TestEngine_Rate(t *testing.T) {
+	t.Parallel()
+
+	e, err := NewEngine(ModeInclusive,
+		Rate{Rate: 1900},
+		Rate{Store: "take-away", Rate: 700},
+		Rate{Category: "drinks", Rate: 1000},
+		Rate{Store: "take-away", Category: "drinks", Rate: 500},
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		store, category string
+		expectedRate    int64
+	}{
+		{store: "eat-in", category: "food", expectedRate: 1900},
+		{store: "take-away", category: "food", expectedRate: 700},
+		{store: "eat-in", category: "drinks", expectedRate: 1000},
+		{store: "take-away", category: "drinks", expectedRate: 500},
+		{store: "", category: "", expectedRate: 1900},
+	}
+	for _, tt := range tests {
+		r, ok := e.Rate(tt.store, tt.category)
+		assert.True(t, ok)
+		assert.Equal(t, tt.expectedRate, r.Rate, "%s/%s", tt.store, tt.category)
+	}
+
+	e, err = NewEngine(ModeExclusive)
+	require.NoError(t, err)
+	_, ok := e.Rate("eat-in", "food")
+	assert.False(t, ok)
+}
+
+func TestNewEngine(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewEngine("included")
+	assert.Error(t, err)
+
+	_, err = NewEngine(ModeExclusive, Rate{Store: "eat-in", Rate: -100})
+	assert.True(t, errors.Is(err, ErrInvalidRate))
+
+	_, err = NewEngine(ModeExclusive, Rate{Store: "eat-in", Rate: MaxRate + 1})
+	assert.True(t, errors.Is(err, ErrInvalidRate))
+
+	_, err = NewEngine(ModeExclusive, Rate{Store: "eat-in", Rate: 1900}, Rate{Store: "eat-in", Rate: 700})
+	assert.True(t, errors.Is(err, ErrInvalidRate))
+}
+
+func TestEngine_Taxes(t *testing.T) {
+	t.Parallel()
+
+	rates := []Rate{{Rate: 1900}, {Store: "take-away", Category: "food", Rate: 700}}
+
+	tests := []struct {
+		name          string
+		mode          Mode
+		store         string
+		discount      money.Money
+		expectedTaxes order.Taxes
+		expectedTotal money.Money
+	}{
+		{
+			name:  "exclusive",
+			mode:  ModeExclusive,
+			store: "take-away",
+			expectedTaxes: order.Taxes{
+				{Name: "Tax 19%", Rate: 1900, Base: money.New(520, "EUR"), Amount: money.New(99, "EUR")},
+				{Name: "Tax 7%", Rate: 700, Base: money.New(200, "EUR"), Amount: money.New(14, "EUR")},
+			},
+			expectedTotal: money.New(833, "EUR"),
+		},
+		{
+			name:  "inclusive",
+			mode:  ModeInclusive,
+			store: "take-away",
+			expectedTaxes: order.Taxes{
+				{Name: "Tax 19%", Rate: 1900, Inclusive: true, Base: money.New(520, "EUR"), Amount: money.New(83, "EUR")},
+				{Name: "Tax 7%", Rate: 700, Inclusive: true, Base: money.New(200, "EUR"), Amount: money.New(13, "EUR")},
+			},
+			expectedTotal: money.New(720, "EUR"),
+		},
+		{
+			name:     "discount spread over the rates",
+			mode:     ModeExclusive,
+			store:    "take-away",
+			discount: money.New(72, "EUR"),
+			expectedTaxes: order.Taxes{
+				{Name: "Tax 19%", Rate: 1900, Base: money.New(468, "EUR"), Amount: money.New(89, "EUR")},
+				{Name: "Tax 7%", Rate: 700, Base: money.New(180, "EUR"), Amount: money.New(13, "EUR")},
+			},
+			expectedTotal: money.New(750, "EUR"),
+		},
+		{
+			name:  "single rate of the store",
+			mode:  ModeExclusive,
+			store: "eat-in",
+			expectedTaxes: order.Taxes{
+				{Name: "Tax 19%", Rate: 1900, Base: money.New(720, "EUR"), Amount: money.New(137, "EUR")},
+			},
+			expectedTotal: money.New(857, "EUR"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			o := order.New("anna")
+			o.Store = tt.store
+			require.NoError(t, o.AddItems(order.Items{
+				{Name: "latte", ServingSize: "M", Category: "drinks", Price: money.New(260, "EUR"), Qty: 2},
+				{Name: "croissant", ServingSize: "M", Category: "food", Price: money.New(200, "EUR"), Qty: 1},
+			}))
+
+			if tt.discount.Amount != 0 {
+				require.NoError(t, o.ApplyDiscounts(order.Discounts{{Promotion: "ten percent", Amount: tt.discount}}))
+			}
+
+			e, err := NewEngine(tt.mode, rates...)
+			require.NoError(t, err)
+
+			taxes, err := e.Taxes(context.Background(), o)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedTaxes, taxes)
+
+			require.NoError(t, o.ApplyTaxes(taxes))
+			assert.Equal(t, tt.expectedTotal, o.Total())
+		})
+	}
+}
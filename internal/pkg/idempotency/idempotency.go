@@ -0,0 +1,44 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInProgress is returned when another request with the same key is still being processed
+	ErrInProgress = errors.New("a request with the same idempotency key is in progress")
+	// ErrKeyReused is returned when the key was already used for a different request
+	ErrKeyReused = errors.New("idempotency key was already used for a different request")
+)
+
+// Record holds the outcome of a request identified by an idempotency key
+type Record struct {
+	Key         string    `json:"key" db:"key"`
+	Fingerprint string    `json:"fingerprint" db:"fingerprint"`
+	Response    []byte    `json:"response" db:"response"`
+	Completed   bool      `json:"completed" db:"completed"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// Store keeps track of idempotency keys for a configurable window. Keys older than
+// the window are forgotten and can be reused.
+type Store interface {
+	// Reserve claims the key for a new request. If the key was already used by a completed
+	// request, its record is returned so the original response can be replayed.
+	Reserve(ctx context.Context, key string, fingerprint string) (*Record, error)
+	// Complete stores the response of the request that reserved the key
+	Complete(ctx context.Context, key string, response []byte) error
+	// Release frees the key after a failed request, so it can be retried
+	Release(ctx context.Context, key string) error
+}
+
+// Fingerprint hashes the parts of a request that must match when a key is reused
+func Fingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
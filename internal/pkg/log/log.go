@@ -0,0 +1,91 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"go.opentelemetry.io/otel/api/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type loggerKeyType int
+
+const loggerKey loggerKeyType = iota
+
+var (
+	mux    = &sync.RWMutex{}
+	level  = zap.NewAtomicLevelAt(zap.InfoLevel)
+	logger = newLogger("json", zapcore.Lock(os.Stdout))
+)
+
+// newLogger creates a logger with the given encoding, either "json" or "console".
+// All loggers share the same atomic level, so SetLevel affects them at runtime.
+func newLogger(encoding string, out zapcore.WriteSyncer) *zap.SugaredLogger {
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.TimeKey = "time"
+	cfg.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	var enc zapcore.Encoder
+	if encoding == "console" {
+		cfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		enc = zapcore.NewConsoleEncoder(cfg)
+	} else {
+		enc = zapcore.NewJSONEncoder(cfg)
+	}
+
+	core := zapcore.NewCore(enc, out, level)
+
+	return zap.New(core, zap.AddCaller(), zap.ErrorOutput(zapcore.Lock(os.Stderr))).Sugar()
+}
+
+// NewContext returns a context that carries the given logger
+func NewContext(ctx context.Context, l *zap.SugaredLogger) context.Context {
+	return context.WithValue(ctx, loggerKey, l)
+}
+
+// WithContext returns the logger carried by the context, or the global logger if there
+// is none. When the context has an active span, its trace_id and span_id are added
+// to the logger, so log lines can be correlated with traces.
+func WithContext(ctx context.Context) *zap.SugaredLogger {
+	l, ok := ctx.Value(loggerKey).(*zap.SugaredLogger)
+	if !ok {
+		mux.RLock()
+		l = logger
+		mux.RUnlock()
+	}
+
+	if sc := trace.SpanFromContext(ctx).SpanContext(); sc.IsValid() {
+		l = l.With("trace_id", sc.TraceID.String(), "span_id", sc.SpanID.String())
+	}
+
+	return l
+}
+
+// SetLevel changes the level of every logger at runtime, e.g. "debug" or "error".
+// Unknown levels are reported and ignored.
+func SetLevel(lvl string) {
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(lvl)); err != nil {
+		WithContext(context.Background()).Warnw("unknown log level, keeping the current one", "level", lvl)
+		return
+	}
+
+	level.SetLevel(l)
+}
+
+// SetFormat changes the encoding of the global logger to "json" or "console"
+func SetFormat(format string) error {
+	if format != "json" && format != "console" {
+		return fmt.Errorf("log format %q not supported", format)
+	}
+
+	mux.Lock()
+	defer mux.Unlock()
+
+	logger = newLogger(format, zapcore.Lock(os.Stdout))
+
+	return nil
+}
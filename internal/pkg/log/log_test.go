@@ -0,0 +1,49 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithContext_TraceFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctx := NewContext(context.Background(), newLogger("json", zapcore.AddSync(buf)))
+
+	tp, err := sdktrace.NewProvider(sdktrace.WithConfig(sdktrace.Config{DefaultSampler: sdktrace.AlwaysSample()}))
+	require.NoError(t, err)
+
+	ctx, span := tp.Tracer("test").Start(ctx, "test")
+	defer span.End()
+
+	WithContext(ctx).Infow("hello", "order_id", "123")
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "hello", line["msg"])
+	assert.Equal(t, "123", line["order_id"])
+	assert.Equal(t, span.SpanContext().TraceID.String(), line["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID.String(), line["span_id"])
+}
+
+func TestSetLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctx := NewContext(context.Background(), newLogger("console", zapcore.AddSync(buf)))
+	defer SetLevel("info")
+
+	WithContext(ctx).Debug("hidden")
+	assert.Empty(t, buf.String())
+
+	SetLevel("debug")
+	WithContext(ctx).Debug("visible")
+	assert.Contains(t, buf.String(), "visible")
+
+	SetLevel("not-a-level")
+	assert.Equal(t, zapcore.DebugLevel, level.Level())
+}
@@ -0,0 +1,297 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrCurrencyMismatch is returned when operating on amounts of different currencies
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	// ErrUnknownCurrency is returned when the currency code is not a supported ISO 4217 code
+	ErrUnknownCurrency = errors.New("unknown currency")
+	// ErrInvalidAmount is returned when a decimal amount can't be parsed
+	ErrInvalidAmount = errors.New("invalid amount")
+)
+
+// RoundingMode defines how fractions of a minor unit are rounded
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds half a minor unit away from zero, e.g. 0.125 -> 0.13
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds half a minor unit to the nearest even unit, e.g. 0.125 -> 0.12
+	RoundHalfEven
+	// RoundDown truncates any fraction of a minor unit, e.g. 0.129 -> 0.12
+	RoundDown
+)
+
+// exponents holds the number of minor unit digits of the supported ISO 4217 currencies
+var exponents = map[string]int{
+	"AUD": 2,
+	"BHD": 3,
+	"BRL": 2,
+	"CAD": 2,
+	"CHF": 2,
+	"CZK": 2,
+	"DKK": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"JPY": 0,
+	"KWD": 3,
+	"NOK": 2,
+	"PLN": 2,
+	"SEK": 2,
+	"USD": 2,
+}
+
+// Money is an exact amount of money represented in the minor unit of its currency
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// New creates a new Money from an amount in minor units, e.g. New(260, "EUR") is 2.60 EUR
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// Parse parses a decimal amount such as "2.60" in the given currency. Digits beyond the
+// currency precision are rounded using RoundHalfUp.
+func Parse(s string, currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+
+	exp, err := Exponent(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	raw := strings.TrimSpace(s)
+	neg := strings.HasPrefix(raw, "-")
+	raw = strings.TrimPrefix(strings.TrimPrefix(raw, "-"), "+")
+
+	parts := strings.SplitN(raw, ".", 2)
+	whole, frac := parts[0], ""
+	if len(parts) == 2 {
+		frac = parts[1]
+	}
+
+	if whole == "" && frac == "" {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	if whole == "" {
+		whole = "0"
+	}
+
+	for _, part := range []string{whole, frac} {
+		if strings.Trim(part, "0123456789") != "" {
+			return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+		}
+	}
+
+	var roundUp bool
+	if len(frac) > exp {
+		roundUp = frac[exp] >= '5'
+		frac = frac[:exp]
+	}
+	frac += strings.Repeat("0", exp-len(frac))
+
+	amount, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	if roundUp {
+		amount++
+	}
+
+	if neg {
+		amount = -amount
+	}
+
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// MustParse is like Parse but panics if the amount can't be parsed
+func MustParse(s string, currency string) Money {
+	m, err := Parse(s, currency)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// Exponent returns the number of minor unit digits of a currency
+func Exponent(currency string) (int, error) {
+	exp, ok := exponents[strings.ToUpper(currency)]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownCurrency, currency)
+	}
+
+	return exp, nil
+}
+
+// IsZero checks if the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsNegative checks if the amount is less than zero
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// SameCurrency checks if both amounts are in the same currency
+func (m Money) SameCurrency(o Money) bool {
+	return m.Currency == o.Currency
+}
+
+// Add sums two amounts of the same currency. A zero value without currency
+// takes the currency of the other operand, which makes it usable as an accumulator.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency == "" && m.IsZero() {
+		return o, nil
+	}
+
+	if !m.SameCurrency(o) {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}, nil
+}
+
+// Sub subtracts an amount of the same currency
+func (m Money) Sub(o Money) (Money, error) {
+	return m.Add(o.Negate())
+}
+
+// Negate returns the amount with the opposite sign
+func (m Money) Negate() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+// Mul multiplies the amount by a whole quantity
+func (m Money) Mul(qty int64) Money {
+	return Money{Amount: m.Amount * qty, Currency: m.Currency}
+}
+
+// MulFrac multiplies the amount by num/den, rounding the result to a minor unit
+// with the given rounding mode. It's used for percentages, e.g. 15% is MulFrac(15, 100, mode).
+func (m Money) MulFrac(num, den int64, mode RoundingMode) Money {
+	if den == 0 {
+		panic("money: division by zero")
+	}
+
+	if den < 0 {
+		num, den = -num, -den
+	}
+
+	p := m.Amount * num
+	q, r := p/den, p%den
+	if r == 0 {
+		return Money{Amount: q, Currency: m.Currency}
+	}
+
+	sign := int64(1)
+	if p < 0 {
+		sign = -1
+		r = -r
+	}
+
+	switch mode {
+	case RoundHalfUp:
+		if 2*r >= den {
+			q += sign
+		}
+	case RoundHalfEven:
+		if 2*r > den || (2*r == den && q%2 != 0) {
+			q += sign
+		}
+	case RoundDown:
+	}
+
+	return Money{Amount: q, Currency: m.Currency}
+}
+
+// Cmp compares two amounts of the same currency, returning -1, 0 or 1
+func (m Money) Cmp(o Money) (int, error) {
+	if !m.SameCurrency(o) {
+		return 0, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Decimal returns the amount formatted as a decimal string in major units, e.g. "2.60"
+func (m Money) Decimal() string {
+	exp, ok := exponents[m.Currency]
+	if !ok {
+		exp = 2
+	}
+
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	if exp == 0 {
+		return sign + strconv.FormatInt(amount, 10)
+	}
+
+	unit := int64(math.Pow10(exp))
+
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/unit, exp, amount%unit)
+}
+
+// String returns a human readable representation such as "2.60 EUR"
+func (m Money) String() string {
+	return strings.TrimSpace(m.Decimal() + " " + m.Currency)
+}
+
+type jsonMoney struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON encodes the amount as a decimal string to avoid float precision loss
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonMoney{Amount: m.Decimal(), Currency: m.Currency})
+}
+
+// UnmarshalJSON decodes a {"amount": "2.60", "currency": "EUR"} representation. The
+// zero Money has no currency, it's decoded back from {"amount": "0.00", "currency": ""}.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw jsonMoney
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if raw.Currency == "" && raw.Amount == (Money{}).Decimal() {
+		*m = Money{}
+		return nil
+	}
+
+	parsed, err := Parse(raw.Amount, raw.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+
+	return nil
+}
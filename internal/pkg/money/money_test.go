@@ -0,0 +1,141 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		input         string
+		currency      string
+		errorExpected bool
+		expected      Money
+	}{
+		{
+			name:     "parse exact amount",
+			input:    "2.60",
+			currency: "EUR",
+			expected: New(260, "EUR"),
+		},
+		{
+			name:     "parse amount without fraction",
+			input:    "3",
+			currency: "eur",
+			expected: New(300, "EUR"),
+		},
+		{
+			name:     "round extra digits half up",
+			input:    "0.125",
+			currency: "EUR",
+			expected: New(13, "EUR"),
+		},
+		{
+			name:     "parse currency without minor units",
+			input:    "450",
+			currency: "JPY",
+			expected: New(450, "JPY"),
+		},
+		{
+			name:     "parse negative amount",
+			input:    "-1.05",
+			currency: "USD",
+			expected: New(-105, "USD"),
+		},
+		{
+			name:          "reject unknown currency",
+			input:         "1.00",
+			currency:      "XXX",
+			errorExpected: true,
+		},
+		{
+			name:          "reject invalid amount",
+			input:         "1,00",
+			currency:      "EUR",
+			errorExpected: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := Parse(tt.input, tt.currency)
+			if tt.errorExpected {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, m)
+		})
+	}
+}
+
+func TestMoney_MulFrac(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		amount   Money
+		num, den int64
+		mode     RoundingMode
+		expected int64
+	}{
+		{name: "half up", amount: New(125, "EUR"), num: 1, den: 10, mode: RoundHalfUp, expected: 13},
+		{name: "half even rounds down to even", amount: New(125, "EUR"), num: 1, den: 10, mode: RoundHalfEven, expected: 12},
+		{name: "half even rounds up to even", amount: New(135, "EUR"), num: 1, den: 10, mode: RoundHalfEven, expected: 14},
+		{name: "round down", amount: New(129, "EUR"), num: 1, den: 10, mode: RoundDown, expected: 12},
+		{name: "negative half up", amount: New(-125, "EUR"), num: 1, den: 10, mode: RoundHalfUp, expected: -13},
+		{name: "percentage", amount: New(780, "EUR"), num: 15, den: 100, mode: RoundHalfUp, expected: 117},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, New(tt.expected, "EUR"), tt.amount.MulFrac(tt.num, tt.den, tt.mode))
+		})
+	}
+}
+
+func TestMoney_Add(t *testing.T) {
+	t.Parallel()
+
+	total, err := Money{}.Add(New(260, "EUR"))
+	require.NoError(t, err)
+
+	total, err = total.Add(New(520, "EUR"))
+	require.NoError(t, err)
+	assert.Equal(t, New(780, "EUR"), total)
+
+	_, err = total.Add(New(100, "USD"))
+	assert.True(t, errors.Is(err, ErrCurrencyMismatch))
+}
+
+func TestMoney_JSON(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(New(780, "EUR"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"7.80","currency":"EUR"}`, string(data))
+
+	var m Money
+	require.NoError(t, json.Unmarshal(data, &m))
+	assert.Equal(t, New(780, "EUR"), m)
+
+	data, err = json.Marshal(Money{})
+	require.NoError(t, err)
+
+	var zero Money
+	require.NoError(t, json.Unmarshal(data, &zero))
+	assert.Equal(t, Money{}, zero)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"1.00","currency":""}`), &zero))
+}
@@ -0,0 +1,6 @@
+package pb
+
+// ViolationDeclined is the type of the PreconditionFailure violation attached to the
+// FailedPrecondition status returned by Pay when the payment is declined. The description
+// of the violation holds the decline reason.
+const ViolationDeclined = "DECLINED"